@@ -29,7 +29,7 @@ func main() {
 	log.Printf("✅ Configuration loaded: %d markets configured", len(cfg.Markets))
 
 	// Initialize storage
-	store := storage.NewMemoryStorage(cfg.Storage.MaxTicksInMemory)
+	store := storage.NewMemoryStorage(cfg.Storage.MaxTicksInMemory, cfg.Storage.MaxArchiveTicks)
 	log.Println("✅ Storage initialized")
 
 	// Initialize result tracker
@@ -91,6 +91,9 @@ func main() {
 		log.Printf("⚠️  Error during shutdown: %v", err)
 	}
 
+	// Keep learned signal weights and consensus state for the next run
+	engine.SaveLearnedState()
+
 	// Print final performance summary
 	log.Println("\n" + resultTracker.GetPerformanceSummary())
 
@@ -119,6 +122,16 @@ func startBackgroundTasks(engine *predictor.Engine, store *storage.MemoryStorage
 		}
 	}()
 
+	// Background analysis (S/R levels, spike hazard, realized sigma, correlations, GARCH, tick chains)
+	go func() {
+		ticker := time.NewTicker(time.Duration(cfg.Tracking.AnalyticsRefreshInterval) * time.Second)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			engine.RefreshAnalytics()
+		}
+	}()
+
 	// Storage cleanup every hour
 	go func() {
 		ticker := time.NewTicker(time.Duration(cfg.Storage.AutoCleanupInterval) * time.Second)
@@ -152,8 +165,27 @@ func printUsageInstructions(cfg types.Config) {
 	log.Printf("  GET  /api/health                           - Health check\n")
 	log.Printf("  GET  /api/markets                          - List active markets\n")
 	log.Printf("  GET  /api/predict/:market/:duration        - Get prediction\n")
+	log.Printf("  GET  /api/predict/:market/auto             - Prediction at the recommended duration\n")
+	log.Printf("  GET  /api/recommend/:market                - Candidate durations ranked by tracked EV\n")
 	log.Printf("  GET  /api/predict/all/:duration            - All market predictions\n")
+	log.Printf("  GET  /api/indicators                       - Indicator registry\n")
+	log.Printf("  GET  /api/indicators/:market/:key          - Compute indicator by key\n")
+	log.Printf("  GET  /api/strategies                       - Registered and enabled strategies\n")
+	log.Printf("  GET  /api/consensus                        - Consensus methods, settings and record\n")
+	log.Printf("  GET  /api/levels/:market                   - Support/resistance levels\n")
+	log.Printf("  GET  /api/crashboom/:market                - Crash/Boom spike probability\n")
+	log.Printf("  GET  /api/forex/strength                   - Currency strength meter\n")
+	log.Printf("  GET  /api/correlation                      - Correlation matrix and lead-lag pairs\n")
+	log.Printf("  GET  /api/volatility/:market               - Random-walk baseline (?duration=60&barrier=)\n")
+	log.Printf("  GET  /api/garch/:market                    - GARCH/EWMA volatility forecast (?duration=60)\n")
+	log.Printf("  GET  /api/kalman/:market                   - Kalman level, slope and projection (?duration=60)\n")
+	log.Printf("  GET  /api/markov/:market                   - Tick-direction chains and chi-square test (?duration=60)\n")
+	log.Printf("  GET  /api/meanreversion/:market            - OU mean, speed, half-life, z-score (?duration=60)\n")
 	log.Printf("  GET  /api/stats                            - All statistics\n")
+	log.Printf("  GET  /api/stats/strategies                 - Win rate and CI per signal, market type, duration\n")
+	log.Printf("  GET  /api/stats/weights                    - Adaptive signal weight multipliers\n")
+	log.Printf("  GET  /api/calibration/:type/:duration      - Confidence reliability diagram\n")
+	log.Printf("  GET  /api/news/upcoming                    - Upcoming economic releases (?hours=24&currency=USD)\n")
 	log.Printf("  GET  /api/stats/:market                    - Market statistics\n")
 	log.Printf("  GET  /api/results/:market                  - Trade results\n")
 	log.Printf("  GET  /api/performance                      - Performance summary\n")
@@ -0,0 +1,221 @@
+package adaptive
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"otc-predictor/pkg/types"
+)
+
+// Weights learns a weight multiplier per signal × market type × duration
+// from tracked outcomes. Each key keeps an exponentially decayed Beta
+// posterior of the signal's win rate; the multiplier is the posterior mean
+// over a coin flip (0.5), clamped to [Floor, Ceiling]. Keys with fewer than
+// MinSamples effective results keep a multiplier of 1.
+type Weights struct {
+	config types.AdaptiveConfig
+	decay  float64 // Applied to past evidence on every new result
+	stats  map[string]*Record
+	dirty  bool
+	mu     sync.RWMutex
+}
+
+// Record is the learned state for one signal × market type × duration
+type Record struct {
+	Name       string    `json:"name"`
+	MarketType string    `json:"market_type"`
+	Duration   int       `json:"duration"`
+	Wins       float64   `json:"wins"`    // Decayed
+	Losses     float64   `json:"losses"`  // Decayed
+	Samples    int       `json:"samples"` // Undecayed result count
+	Multiplier float64   `json:"multiplier"`
+	Updated    time.Time `json:"updated"`
+}
+
+// NewWeights creates an empty weighting module
+func NewWeights(config types.AdaptiveConfig) *Weights {
+	return &Weights{
+		config: config,
+		decay:  math.Pow(0.5, 1/float64(config.HalfLifeTrades)),
+		stats:  make(map[string]*Record),
+	}
+}
+
+// Update credits or debits every signal that voted on a resolved prediction
+func (w *Weights) Update(result types.TradeResult) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, signal := range result.Signals {
+		if signal.Direction != "UP" && signal.Direction != "DOWN" {
+			continue
+		}
+
+		k := key(signal.Name, result.MarketType, result.Duration)
+		record := w.stats[k]
+		if record == nil {
+			record = &Record{
+				Name:       signal.Name,
+				MarketType: result.MarketType,
+				Duration:   result.Duration,
+			}
+			w.stats[k] = record
+		}
+
+		record.Wins *= w.decay
+		record.Losses *= w.decay
+		if (signal.Direction == "UP" && result.PriceChange > 0) ||
+			(signal.Direction == "DOWN" && result.PriceChange < 0) {
+			record.Wins++
+		} else {
+			record.Losses++
+		}
+		record.Samples++
+		record.Multiplier = w.multiplier(record)
+		record.Updated = result.ExitTime
+	}
+	w.dirty = true
+}
+
+// Multiplier returns the learned weight multiplier for a signal
+func (w *Weights) Multiplier(name, marketType string, duration int) float64 {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if record := w.stats[key(name, marketType, duration)]; record != nil {
+		return record.Multiplier
+	}
+	return 1
+}
+
+// WinRate shrinks a signal's hand-tuned confidence towards its tracked win
+// rate: the decayed Beta posterior with the confidence as prior mean, worth
+// PriorStrength results. With no record it is the confidence itself.
+func (w *Weights) WinRate(name, marketType string, duration int, confidence float64) float64 {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	record := w.stats[key(name, marketType, duration)]
+	if record == nil {
+		return confidence
+	}
+	prior := w.config.PriorStrength
+	return (record.Wins + prior*confidence) / (record.Wins + record.Losses + prior)
+}
+
+// Apply scales each signal's weight by its learned multiplier and sets its
+// win rate
+func (w *Weights) Apply(signals []types.StrategySignal, marketType string, duration int) []types.StrategySignal {
+	adjusted := make([]types.StrategySignal, len(signals))
+	for i, signal := range signals {
+		signal.Weight *= w.Multiplier(signal.Name, marketType, duration)
+		signal.WinRate = w.WinRate(signal.Name, marketType, duration, signal.Confidence)
+		adjusted[i] = signal
+	}
+	return adjusted
+}
+
+// Records returns the learned state sorted by market type, duration and name
+func (w *Weights) Records() []Record {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	records := make([]Record, 0, len(w.stats))
+	for _, record := range w.stats {
+		records = append(records, *record)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].MarketType != records[j].MarketType {
+			return records[i].MarketType < records[j].MarketType
+		}
+		if records[i].Duration != records[j].Duration {
+			return records[i].Duration < records[j].Duration
+		}
+		return records[i].Name < records[j].Name
+	})
+	return records
+}
+
+// Save writes the learned state to the configured file if it changed
+func (w *Weights) Save() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.dirty || w.config.File == "" {
+		return nil
+	}
+
+	records := make([]*Record, 0, len(w.stats))
+	for _, record := range w.stats {
+		records = append(records, record)
+	}
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode adaptive weights: %w", err)
+	}
+
+	// Write then rename so a crash never leaves a half-written file
+	tmp := w.config.File + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write adaptive weights: %w", err)
+	}
+	if err := os.Rename(tmp, w.config.File); err != nil {
+		return fmt.Errorf("failed to write adaptive weights: %w", err)
+	}
+
+	w.dirty = false
+	return nil
+}
+
+// Load restores learned state from the configured file.
+// A missing file is not an error.
+func (w *Weights) Load() error {
+	if w.config.File == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(w.config.File)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read adaptive weights: %w", err)
+	}
+
+	var records []*Record
+	if err := json.Unmarshal(data, &records); err != nil {
+		return fmt.Errorf("failed to parse adaptive weights: %w", err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, record := range records {
+		// Guard rails may have changed since the file was written
+		record.Multiplier = w.multiplier(record)
+		w.stats[key(record.Name, record.MarketType, record.Duration)] = record
+	}
+	return nil
+}
+
+// multiplier is the decayed posterior win rate over 0.5, within guard rails
+func (w *Weights) multiplier(record *Record) float64 {
+	if record.Samples < w.config.MinSamples {
+		return 1
+	}
+
+	prior := w.config.PriorStrength / 2
+	mean := (record.Wins + prior) / (record.Wins + record.Losses + 2*prior)
+	return math.Max(w.config.Floor, math.Min(w.config.Ceiling, mean/0.5))
+}
+
+func key(name, marketType string, duration int) string {
+	return fmt.Sprintf("%s|%s|%d", name, marketType, duration)
+}
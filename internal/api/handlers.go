@@ -8,8 +8,11 @@ import (
 	"sync"
 	"time"
 
+	"otc-predictor/internal/consensus"
+	"otc-predictor/internal/indicators"
 	"otc-predictor/internal/predictor"
 	"otc-predictor/internal/storage"
+	"otc-predictor/internal/strategy"
 	"otc-predictor/internal/tracker"
 	"otc-predictor/pkg/types"
 
@@ -105,29 +108,48 @@ func (h *Handler) storeAnalysis(market string, duration int, analysis MarketOppo
 
 // MarketOpportunity represents a trading opportunity with quality score
 type MarketOpportunity struct {
-	Market       string    `json:"market"`
-	MarketType   string    `json:"market_type"`
-	Direction    string    `json:"direction"`
-	Confidence   float64   `json:"confidence"`
-	QualityScore float64   `json:"quality_score"`
-	CurrentPrice float64   `json:"current_price"`
-	Duration     int       `json:"duration"`
-	DataPoints   int       `json:"data_points"`
-	Reason       string    `json:"reason"`
-	WinRate      float64   `json:"win_rate,omitempty"`
-	TotalTrades  int       `json:"total_trades,omitempty"`
-	Timestamp    time.Time `json:"timestamp"`
+	Market        string    `json:"market"`
+	MarketType    string    `json:"market_type"`
+	Direction     string    `json:"direction"`
+	Confidence    float64   `json:"confidence"`
+	QualityScore  float64   `json:"quality_score"`
+	Payout        float64   `json:"payout"`
+	ExpectedValue float64   `json:"expected_value"` // Per unit stake
+	BreakEven     float64   `json:"break_even"`
+	CurrentPrice  float64   `json:"current_price"`
+	Duration      int       `json:"duration"`
+	DataPoints    int       `json:"data_points"`
+	Reason        string    `json:"reason"`
+	WinRate       float64   `json:"win_rate,omitempty"`
+	TotalTrades   int       `json:"total_trades,omitempty"`
+	Timestamp     time.Time `json:"timestamp"`
 }
 
 // 🔧 PERFORMANCE: Fast batch analysis with smart caching
 // This solves the "25 forex pairs taking too long" problem
+// Query: duration, limit, mode, min_ev (defaults to payout.min_ev) and
+// sort ("ev", "quality" or "confidence")
 func (h *Handler) GetBestMarkets(c *fiber.Ctx) error {
 	durationStr := c.Query("duration", "60")
 	limitStr := c.Query("limit", "5")
 	mode := c.Query("mode", "synthetics")
+	sortBy := c.Query("sort", "ev")
 
-	duration, err := strconv.Atoi(durationStr)
-	if err != nil || duration < 30 || duration > 3600 {
+	minEV := h.engine.MinEV()
+	if minEVStr := c.Query("min_ev"); minEVStr != "" {
+		value, err := strconv.ParseFloat(minEVStr, 64)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid min_ev"})
+		}
+		minEV = value
+	}
+
+	if sortBy != "ev" && sortBy != "quality" && sortBy != "confidence" {
+		return c.Status(400).JSON(fiber.Map{"error": "sort must be ev, quality or confidence"})
+	}
+
+	duration, ok := parseDuration(durationStr)
+	if !ok {
 		duration = 60
 	}
 
@@ -192,14 +214,17 @@ func (h *Handler) GetBestMarkets(c *fiber.Ctx) error {
 		// Try cache first for faster response
 		if cached := h.getCachedAnalysis(market, duration); cached != nil {
 			prediction = types.Prediction{
-				Market:       cached.Market,
-				Direction:    cached.Direction,
-				Confidence:   cached.Confidence,
-				CurrentPrice: cached.CurrentPrice,
-				Duration:     cached.Duration,
-				DataPoints:   cached.DataPoints,
-				Reason:       cached.Reason,
-				Timestamp:    cached.Timestamp,
+				Market:        cached.Market,
+				Direction:     cached.Direction,
+				Confidence:    cached.Confidence,
+				CurrentPrice:  cached.CurrentPrice,
+				Duration:      cached.Duration,
+				DataPoints:    cached.DataPoints,
+				Reason:        cached.Reason,
+				Timestamp:     cached.Timestamp,
+				Payout:        cached.Payout,
+				BreakEven:     cached.BreakEven,
+				ExpectedValue: cached.ExpectedValue,
 			}
 			cacheHits++
 		} else {
@@ -210,8 +235,8 @@ func (h *Handler) GetBestMarkets(c *fiber.Ctx) error {
 			}
 		}
 
-		// Skip if no clear direction or confidence below 58%
-		if prediction.Direction == "NONE" || prediction.Confidence < 0.58 {
+		// Skip if no clear direction or not worth the payout
+		if prediction.Direction == "NONE" || prediction.ExpectedValue < minEV {
 			continue
 		}
 
@@ -222,16 +247,19 @@ func (h *Handler) GetBestMarkets(c *fiber.Ctx) error {
 		qualityScore := calculateQualityScore(prediction, stats)
 
 		opportunity := MarketOpportunity{
-			Market:       prediction.Market,
-			MarketType:   getMarketType(prediction.Market),
-			Direction:    prediction.Direction,
-			Confidence:   prediction.Confidence,
-			QualityScore: qualityScore,
-			CurrentPrice: prediction.CurrentPrice,
-			Duration:     prediction.Duration,
-			DataPoints:   prediction.DataPoints,
-			Reason:       prediction.Reason,
-			Timestamp:    prediction.Timestamp,
+			Market:        prediction.Market,
+			MarketType:    getMarketType(prediction.Market),
+			Direction:     prediction.Direction,
+			Confidence:    prediction.Confidence,
+			QualityScore:  qualityScore,
+			Payout:        prediction.Payout,
+			ExpectedValue: prediction.ExpectedValue,
+			BreakEven:     prediction.BreakEven,
+			CurrentPrice:  prediction.CurrentPrice,
+			Duration:      prediction.Duration,
+			DataPoints:    prediction.DataPoints,
+			Reason:        prediction.Reason,
+			Timestamp:     prediction.Timestamp,
 		}
 
 		// Add stats if available
@@ -252,9 +280,16 @@ func (h *Handler) GetBestMarkets(c *fiber.Ctx) error {
 		}
 	}
 
-	// Sort by quality score (highest first)
+	// Sort by the requested key (highest first)
 	sort.Slice(opportunities, func(i, j int) bool {
-		return opportunities[i].QualityScore > opportunities[j].QualityScore
+		switch sortBy {
+		case "quality":
+			return opportunities[i].QualityScore > opportunities[j].QualityScore
+		case "confidence":
+			return opportunities[i].Confidence > opportunities[j].Confidence
+		default:
+			return opportunities[i].ExpectedValue > opportunities[j].ExpectedValue
+		}
 	})
 
 	// Limit results
@@ -268,6 +303,8 @@ func (h *Handler) GetBestMarkets(c *fiber.Ctx) error {
 		"total_found":    len(opportunities),
 		"mode":           mode,
 		"duration":       duration,
+		"min_ev":         minEV,
+		"sort":           sortBy,
 		"timestamp":      time.Now(),
 		"cache_used":     fmt.Sprintf("%.1f%%", float64(cacheHits)/float64(processed)*100),
 	})
@@ -377,14 +414,44 @@ func getMarketType(market string) string {
 }
 
 // GetPrediction handles GET /predict/:market/:duration
+// A duration of "auto" predicts at the market's recommended duration, and
+// returns NONE until the tracked record shows a duration with an edge
 func (h *Handler) GetPrediction(c *fiber.Ctx) error {
 	market := c.Params("market")
 	durationStr := c.Params("duration")
 
-	duration, err := strconv.Atoi(durationStr)
-	if err != nil || duration < 30 || duration > 3600 {
+	var recommendation *predictor.DurationRecommendation
+	if durationStr == "auto" {
+		recommended, err := h.engine.RecommendDuration(market)
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if recommended.NoEdge {
+			reason := "No duration has a proven edge"
+			if recommended.ReasonCode == "insufficient_history" {
+				reason = "No duration has enough tracked results to prove an edge"
+			}
+			return c.JSON(types.Prediction{
+				Market:       market,
+				MarketType:   recommended.MarketType,
+				Direction:    "NONE",
+				Reason:       reason,
+				ReasonCode:   recommended.ReasonCode,
+				Duration:     recommended.Duration,
+				Timestamp:    time.Now(),
+				AutoDuration: recommended.Reason,
+			})
+		}
+		recommendation = &recommended
+		durationStr = strconv.Itoa(recommended.Duration)
+	}
+
+	duration, ok := parseDuration(durationStr)
+	if !ok {
 		return c.Status(400).JSON(fiber.Map{
-			"error": "Invalid duration (must be between 30-3600 seconds)",
+			"error": fmt.Sprintf("Invalid duration (must be between %d-%d seconds or auto)", types.MinDuration, types.MaxDuration),
 		})
 	}
 
@@ -394,6 +461,9 @@ func (h *Handler) GetPrediction(c *fiber.Ctx) error {
 			"error": err.Error(),
 		})
 	}
+	if recommendation != nil {
+		prediction.AutoDuration = recommendation.Reason
+	}
 
 	return c.JSON(prediction)
 }
@@ -402,17 +472,248 @@ func (h *Handler) GetPrediction(c *fiber.Ctx) error {
 func (h *Handler) GetAllPredictions(c *fiber.Ctx) error {
 	durationStr := c.Params("duration")
 
-	duration, err := strconv.Atoi(durationStr)
-	if err != nil || duration < 30 || duration > 3600 {
-		return c.Status(400).JSON(fiber.Map{
-			"error": "Invalid duration (must be between 30-3600 seconds)",
-		})
+	duration, ok := parseDuration(durationStr)
+	if !ok {
+		return invalidDuration(c)
 	}
 
 	predictions := h.engine.PredictAll(duration)
 	return c.JSON(predictions)
 }
 
+// GetIndicatorRegistry handles GET /indicators
+func (h *Handler) GetIndicatorRegistry(c *fiber.Ctx) error {
+	return c.JSON(indicators.Registered())
+}
+
+// GetStrategies handles GET /strategies
+func (h *Handler) GetStrategies(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"registered": strategy.Registered(),
+		"enabled":    h.engine.EnabledStrategies(),
+	})
+}
+
+// GetConsensus returns consensus settings and each method's record per market type
+func (h *Handler) GetConsensus(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"methods":      consensus.Methods,
+		"market_types": h.engine.ConsensusReport(),
+	})
+}
+
+// GetIndicator handles GET /indicators/:market/:key
+// Query parameters other than "duration" are passed as indicator params.
+func (h *Handler) GetIndicator(c *fiber.Ctx) error {
+	market := c.Params("market")
+	key := c.Params("key")
+
+	duration, ok := parseDuration(c.Query("duration", "60"))
+	if !ok {
+		return invalidDuration(c)
+	}
+
+	params := indicators.Params{}
+	for name, raw := range c.Queries() {
+		if name == "duration" {
+			continue
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"error": fmt.Sprintf("Invalid value for %s: %s", name, raw),
+			})
+		}
+		params[name] = value
+	}
+
+	values, err := h.engine.ComputeIndicator(market, duration, key, params)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"market":    market,
+		"indicator": key,
+		"duration":  duration,
+		"params":    params,
+		"values":    values,
+	})
+}
+
+// GetLevels handles GET /levels/:market
+func (h *Handler) GetLevels(c *fiber.Ctx) error {
+	market := c.Params("market")
+
+	snapshot, err := h.engine.GetLevels(market)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(snapshot)
+}
+
+// GetCrashBoomHazard handles GET /crashboom/:market
+// Forecasts 60, 300 and 900 second contracts unless ?duration= is given
+func (h *Handler) GetCrashBoomHazard(c *fiber.Ctx) error {
+	market := c.Params("market")
+
+	durations := []int{60, 300, 900}
+	if c.Query("duration") != "" {
+		duration, ok := parseDuration(c.Query("duration"))
+		if !ok {
+			return invalidDuration(c)
+		}
+		durations = []int{duration}
+	}
+
+	model, forecasts, err := h.engine.CrashBoomHazard(market, durations)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"model":     model,
+		"forecasts": forecasts,
+	})
+}
+
+// GetVolatilityForecast handles GET /garch/:market
+// Forecasts 60, 300 and 900 second contracts unless ?duration= is given
+func (h *Handler) GetVolatilityForecast(c *fiber.Ctx) error {
+	market := c.Params("market")
+
+	durations := []int{60, 300, 900}
+	if c.Query("duration") != "" {
+		duration, ok := parseDuration(c.Query("duration"))
+		if !ok {
+			return invalidDuration(c)
+		}
+		durations = []int{duration}
+	}
+
+	model, forecasts, err := h.engine.VolatilityForecast(market, durations)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"model":     model,
+		"forecasts": forecasts,
+	})
+}
+
+// GetVolatilityModel handles GET /volatility/:market
+// Query: duration (default 60) and barrier, an offset from the current price
+func (h *Handler) GetVolatilityModel(c *fiber.Ctx) error {
+	duration, ok := parseDuration(c.Query("duration", "60"))
+	if !ok {
+		return invalidDuration(c)
+	}
+
+	var barrier *float64
+	if value := c.Query("barrier"); value != "" {
+		offset, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid barrier offset"})
+		}
+		barrier = &offset
+	}
+
+	report, err := h.engine.VolatilityModel(c.Params("market"), duration, barrier)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(report)
+}
+
+// GetMeanReversion handles GET /meanreversion/:market
+func (h *Handler) GetMeanReversion(c *fiber.Ctx) error {
+	duration, ok := parseDuration(c.Query("duration", "60"))
+	if !ok {
+		return invalidDuration(c)
+	}
+
+	fit, err := h.engine.MeanReversion(c.Params("market"), duration)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fit)
+}
+
+// GetKalmanTrend handles GET /kalman/:market
+func (h *Handler) GetKalmanTrend(c *fiber.Ctx) error {
+	duration, ok := parseDuration(c.Query("duration", "60"))
+	if !ok {
+		return invalidDuration(c)
+	}
+
+	trend, err := h.engine.KalmanTrend(c.Params("market"), duration)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(trend)
+}
+
+// GetTickMarkov handles GET /markov/:market
+func (h *Handler) GetTickMarkov(c *fiber.Ctx) error {
+	duration, ok := parseDuration(c.Query("duration", "60"))
+	if !ok {
+		return invalidDuration(c)
+	}
+
+	model, forecast, err := h.engine.TickMarkov(c.Params("market"), duration)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"model":    model,
+		"forecast": forecast,
+	})
+}
+
+// GetDurationRecommendation handles GET /recommend/:market
+func (h *Handler) GetDurationRecommendation(c *fiber.Ctx) error {
+	recommendation, err := h.engine.RecommendDuration(c.Params("market"))
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(recommendation)
+}
+
+// GetCurrencyStrength handles GET /forex/strength
+func (h *Handler) GetCurrencyStrength(c *fiber.Ctx) error {
+	return c.JSON(h.engine.CurrencyStrength())
+}
+
+// GetCorrelations handles GET /correlation
+func (h *Handler) GetCorrelations(c *fiber.Ctx) error {
+	return c.JSON(h.engine.Correlations())
+}
+
 // GetStats handles GET /stats/:market
 func (h *Handler) GetStats(c *fiber.Ctx) error {
 	market := c.Params("market")
@@ -420,6 +721,56 @@ func (h *Handler) GetStats(c *fiber.Ctx) error {
 	return c.JSON(stats)
 }
 
+// GetStrategyStats handles GET /stats/strategies
+// Optional filters: ?market_type=forex&duration=60
+func (h *Handler) GetStrategyStats(c *fiber.Ctx) error {
+	duration := c.QueryInt("duration", 0)
+	if duration < 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid duration"})
+	}
+
+	return c.JSON(h.tracker.GetSignalStats(c.Query("market_type"), duration))
+}
+
+// GetAdaptiveWeights handles GET /stats/weights
+func (h *Handler) GetAdaptiveWeights(c *fiber.Ctx) error {
+	return c.JSON(h.engine.AdaptiveWeights())
+}
+
+// GetCalibration handles GET /calibration - reliability diagrams for
+// every market type and duration with results
+func (h *Handler) GetCalibration(c *fiber.Ctx) error {
+	return c.JSON(h.engine.AllReliability())
+}
+
+// GetReliability handles GET /calibration/:market_type/:duration
+func (h *Handler) GetReliability(c *fiber.Ctx) error {
+	marketType := c.Params("market_type")
+	if marketType != "forex" && marketType != "volatility" && marketType != "crash_boom" {
+		return c.Status(400).JSON(fiber.Map{"error": "Market type must be forex, volatility or crash_boom"})
+	}
+
+	duration, err := strconv.Atoi(c.Params("duration"))
+	if err != nil || duration <= 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid duration"})
+	}
+
+	return c.JSON(h.engine.Reliability(marketType, duration))
+}
+
+// GetUpcomingNews handles GET /news/upcoming?hours=24&currency=USD
+func (h *Handler) GetUpcomingNews(c *fiber.Ctx) error {
+	hours := c.QueryInt("hours", 24)
+	if hours < 1 || hours > 24*14 {
+		return c.Status(400).JSON(fiber.Map{"error": "hours must be between 1 and 336"})
+	}
+
+	return c.JSON(fiber.Map{
+		"events": h.engine.UpcomingNews(time.Duration(hours)*time.Hour, c.Query("currency")),
+		"hours":  hours,
+	})
+}
+
 // GetAllStats handles GET /stats
 func (h *Handler) GetAllStats(c *fiber.Ctx) error {
 	stats := h.engine.GetAllStats()
@@ -464,8 +815,8 @@ func (h *Handler) WebSocketHandler(c *websocket.Conn) {
 	market := c.Params("market")
 	durationStr := c.Query("duration", "60")
 
-	duration, err := strconv.Atoi(durationStr)
-	if err != nil || duration < 30 || duration > 3600 {
+	duration, ok := parseDuration(durationStr)
+	if !ok {
 		duration = 60
 	}
 
@@ -522,3 +873,20 @@ func (h *Handler) GetPerformanceSummary(c *fiber.Ctx) error {
 	summary := h.tracker.GetPerformanceSummary()
 	return c.SendString(summary)
 }
+
+// parseDuration parses a contract duration in seconds and checks it against
+// the range every endpoint shares with the duration recommender
+func parseDuration(value string) (int, bool) {
+	duration, err := strconv.Atoi(value)
+	if err != nil || duration < types.MinDuration || duration > types.MaxDuration {
+		return 0, false
+	}
+	return duration, true
+}
+
+// invalidDuration responds to a duration parseDuration rejected
+func invalidDuration(c *fiber.Ctx) error {
+	return c.Status(400).JSON(fiber.Map{
+		"error": fmt.Sprintf("Invalid duration (must be between %d-%d seconds)", types.MinDuration, types.MaxDuration),
+	})
+}
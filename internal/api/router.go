@@ -70,12 +70,61 @@ func (s *Server) SetupRoutes() {
 	// ⭐ NEW: Best trading opportunities
 	api.Get("/best-markets", s.handler.GetBestMarkets)
 
-	// Predictions
+	// Predictions (duration "auto" uses the recommended duration)
 	api.Get("/predict/:market/:duration", s.handler.GetPrediction)
 	api.Get("/predict/all/:duration", s.handler.GetAllPredictions)
 
+	// Duration recommendation from tracked results
+	api.Get("/recommend/:market", s.handler.GetDurationRecommendation)
+
+	// Indicator registry
+	api.Get("/indicators", s.handler.GetIndicatorRegistry)
+	api.Get("/indicators/:market/:key", s.handler.GetIndicator)
+
+	// Strategy registry
+	api.Get("/strategies", s.handler.GetStrategies)
+
+	// Consensus methods and their record on resolved predictions
+	api.Get("/consensus", s.handler.GetConsensus)
+
+	// Support/resistance levels
+	api.Get("/levels/:market", s.handler.GetLevels)
+
+	// Crash/Boom spike hazard model
+	api.Get("/crashboom/:market", s.handler.GetCrashBoomHazard)
+
+	// Forex currency strength and triangulation
+	api.Get("/forex/strength", s.handler.GetCurrencyStrength)
+
+	// Cross-market correlation matrix and lead-lag pairs
+	api.Get("/correlation", s.handler.GetCorrelations)
+
+	// Volatility index random-walk baseline
+	api.Get("/volatility/:market", s.handler.GetVolatilityModel)
+
+	// GARCH/EWMA volatility forecasts
+	api.Get("/garch/:market", s.handler.GetVolatilityForecast)
+
+	// Kalman local-linear-trend estimate
+	api.Get("/kalman/:market", s.handler.GetKalmanTrend)
+
+	// Tick-direction Markov chains
+	api.Get("/markov/:market", s.handler.GetTickMarkov)
+
+	// Ornstein-Uhlenbeck mean-reversion fit
+	api.Get("/meanreversion/:market", s.handler.GetMeanReversion)
+
 	// Statistics
 	api.Get("/stats", s.handler.GetAllStats)
+	api.Get("/stats/strategies", s.handler.GetStrategyStats) // Before /stats/:market
+	api.Get("/stats/weights", s.handler.GetAdaptiveWeights)
+
+	// Economic calendar behind forex news blackouts
+	api.Get("/news/upcoming", s.handler.GetUpcomingNews)
+
+	// Confidence calibration reliability diagrams
+	api.Get("/calibration", s.handler.GetCalibration)
+	api.Get("/calibration/:market_type/:duration", s.handler.GetReliability)
 	api.Get("/stats/:market", s.handler.GetStats)
 
 	// Results
@@ -0,0 +1,372 @@
+package calibration
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"otc-predictor/pkg/types"
+)
+
+// Calibrator maps raw prediction confidence to realized win probability,
+// fitted per market type and duration from tracked results
+type Calibrator struct {
+	config  types.CalibrationConfig
+	models  map[group]*Model
+	samples map[group][]sample // Last fitted data, for reliability diagrams
+	mu      sync.RWMutex
+}
+
+// Model is one fitted mapping
+type Model struct {
+	Method  string `json:"method"`
+	Samples int    `json:"samples"`
+
+	// Isotonic: non-decreasing step function through (raw, win rate) points
+	Points []Point `json:"points,omitempty"`
+
+	// Platt: P(win) = sigmoid(A*logit(raw) + B)
+	A float64 `json:"a,omitempty"`
+	B float64 `json:"b,omitempty"`
+}
+
+// Point is a knot of an isotonic fit
+type Point struct {
+	Raw        float64 `json:"raw"`
+	Calibrated float64 `json:"calibrated"`
+}
+
+// Bin is one bucket of a reliability diagram
+type Bin struct {
+	Lower          float64 `json:"lower"`
+	Upper          float64 `json:"upper"`
+	Count          int     `json:"count"`
+	MeanRaw        float64 `json:"mean_raw"`
+	MeanCalibrated float64 `json:"mean_calibrated"`
+	WinRate        float64 `json:"win_rate"` // Realized, 0-1
+}
+
+// Reliability is a reliability diagram for one market type and duration
+type Reliability struct {
+	MarketType string  `json:"market_type"`
+	Duration   int     `json:"duration"`
+	Model      *Model  `json:"model,omitempty"` // Nil until MinSamples results
+	Samples    int     `json:"samples"`
+	BrierRaw   float64 `json:"brier_raw"`
+	BrierCal   float64 `json:"brier_calibrated"`
+	Bins       []Bin   `json:"bins"`
+}
+
+type sample struct {
+	raw float64
+	won bool
+}
+
+// NewCalibrator creates an unfitted calibrator
+func NewCalibrator(config types.CalibrationConfig) *Calibrator {
+	return &Calibrator{
+		config:  config,
+		models:  make(map[group]*Model),
+		samples: make(map[group][]sample),
+	}
+}
+
+// Fit refits every market type × duration from tracked results
+func (c *Calibrator) Fit(results []types.TradeResult) {
+	grouped := make(map[group][]sample)
+	for _, result := range results {
+		raw := result.RawConfidence
+		if raw == 0 {
+			raw = result.Confidence // Results from before raw confidence was tracked
+		}
+		k := key(result.MarketType, result.Duration)
+		grouped[k] = append(grouped[k], sample{raw: raw, won: result.Won})
+	}
+
+	models := make(map[group]*Model)
+	for k, samples := range grouped {
+		if len(samples) < c.config.MinSamples {
+			continue
+		}
+		if c.config.Method == "platt" {
+			models[k] = fitPlatt(samples)
+		} else {
+			models[k] = fitIsotonic(samples)
+		}
+	}
+
+	c.mu.Lock()
+	c.models = models
+	c.samples = grouped
+	c.mu.Unlock()
+}
+
+// Calibrate returns the calibrated probability for a raw confidence and the
+// model used, or the raw confidence and nil if none is fitted yet
+func (c *Calibrator) Calibrate(marketType string, duration int, raw float64) (float64, *Model) {
+	c.mu.RLock()
+	model := c.models[key(marketType, duration)]
+	c.mu.RUnlock()
+
+	if model == nil {
+		return raw, nil
+	}
+	return model.apply(raw), model
+}
+
+// Reliability builds a reliability diagram from the last fitted results
+func (c *Calibrator) Reliability(marketType string, duration int) Reliability {
+	c.mu.RLock()
+	k := key(marketType, duration)
+	model := c.models[k]
+	samples := c.samples[k]
+	c.mu.RUnlock()
+
+	bins := c.config.Bins
+	report := Reliability{
+		MarketType: marketType,
+		Duration:   duration,
+		Model:      model,
+		Samples:    len(samples),
+		Bins:       make([]Bin, bins),
+	}
+
+	for i := range report.Bins {
+		report.Bins[i].Lower = float64(i) / float64(bins)
+		report.Bins[i].Upper = float64(i+1) / float64(bins)
+	}
+
+	for _, s := range samples {
+		calibrated := s.raw
+		if model != nil {
+			calibrated = model.apply(s.raw)
+		}
+		outcome := 0.0
+		if s.won {
+			outcome = 1
+		}
+
+		report.BrierRaw += (s.raw - outcome) * (s.raw - outcome)
+		report.BrierCal += (calibrated - outcome) * (calibrated - outcome)
+
+		i := int(s.raw * float64(bins))
+		if i >= bins {
+			i = bins - 1
+		}
+		if i < 0 {
+			i = 0
+		}
+		bin := &report.Bins[i]
+		bin.Count++
+		bin.MeanRaw += s.raw
+		bin.MeanCalibrated += calibrated
+		bin.WinRate += outcome
+	}
+
+	if len(samples) > 0 {
+		report.BrierRaw /= float64(len(samples))
+		report.BrierCal /= float64(len(samples))
+	}
+	for i := range report.Bins {
+		if n := float64(report.Bins[i].Count); n > 0 {
+			report.Bins[i].MeanRaw /= n
+			report.Bins[i].MeanCalibrated /= n
+			report.Bins[i].WinRate /= n
+		}
+	}
+
+	return report
+}
+
+// All returns a reliability diagram for every market type × duration with results
+func (c *Calibrator) All() []Reliability {
+	c.mu.RLock()
+	groups := make([]group, 0, len(c.samples))
+	for g := range c.samples {
+		groups = append(groups, g)
+	}
+	c.mu.RUnlock()
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].marketType != groups[j].marketType {
+			return groups[i].marketType < groups[j].marketType
+		}
+		return groups[i].duration < groups[j].duration
+	})
+
+	reports := make([]Reliability, 0, len(groups))
+	for _, g := range groups {
+		reports = append(reports, c.Reliability(g.marketType, g.duration))
+	}
+	return reports
+}
+
+// apply maps a raw confidence through the model
+func (m *Model) apply(raw float64) float64 {
+	if m.Method == "platt" {
+		return sigmoid(m.A*logit(raw) + m.B)
+	}
+
+	points := m.Points
+	if len(points) == 0 {
+		return raw
+	}
+	if raw <= points[0].Raw {
+		return points[0].Calibrated
+	}
+	last := points[len(points)-1]
+	if raw >= last.Raw {
+		return last.Calibrated
+	}
+
+	// Interpolate between the surrounding knots
+	i := sort.Search(len(points), func(i int) bool { return points[i].Raw >= raw })
+	lo, hi := points[i-1], points[i]
+	if hi.Raw == lo.Raw {
+		return hi.Calibrated
+	}
+	t := (raw - lo.Raw) / (hi.Raw - lo.Raw)
+	return lo.Calibrated + t*(hi.Calibrated-lo.Calibrated)
+}
+
+// fitIsotonic runs pool-adjacent-violators on results sorted by raw
+// confidence; each pooled block becomes a knot at its mean raw confidence
+func fitIsotonic(samples []sample) *Model {
+	sorted := append([]sample{}, samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].raw < sorted[j].raw })
+
+	type block struct {
+		rawSum, winSum, count float64
+	}
+	var blocks []block
+	for _, s := range sorted {
+		b := block{rawSum: s.raw, count: 1}
+		if s.won {
+			b.winSum = 1
+		}
+		blocks = append(blocks, b)
+
+		// Merge backwards while win rates decrease
+		for len(blocks) > 1 {
+			last, prev := blocks[len(blocks)-1], blocks[len(blocks)-2]
+			if prev.winSum/prev.count <= last.winSum/last.count {
+				break
+			}
+			blocks = blocks[:len(blocks)-2]
+			blocks = append(blocks, block{
+				rawSum: prev.rawSum + last.rawSum,
+				winSum: prev.winSum + last.winSum,
+				count:  prev.count + last.count,
+			})
+		}
+	}
+
+	model := &Model{Method: "isotonic", Samples: len(samples)}
+	for _, b := range blocks {
+		model.Points = append(model.Points, Point{
+			Raw:        b.rawSum / b.count,
+			Calibrated: b.winSum / b.count,
+		})
+	}
+	return model
+}
+
+// fitPlatt fits P(win) = sigmoid(A*logit(raw) + B) by Newton's method with
+// Platt's smoothed targets. Steps are halved until the log loss falls, as in
+// Lin, Lin and Weng's refinement of Platt's algorithm; a plain Newton step
+// overshoots when every result has the same outcome.
+func fitPlatt(samples []sample) *Model {
+	positives, negatives := 0.0, 0.0
+	for _, s := range samples {
+		if s.won {
+			positives++
+		} else {
+			negatives++
+		}
+	}
+	hiTarget := (positives + 1) / (positives + 2)
+	loTarget := 1 / (negatives + 2)
+
+	target := func(s sample) float64 {
+		if s.won {
+			return hiTarget
+		}
+		return loTarget
+	}
+	loss := func(a, b float64) float64 {
+		total := 0.0
+		for _, s := range samples {
+			z, t := a*logit(s.raw)+b, target(s)
+			// -t·log σ(z) - (1-t)·log(1-σ(z)), stable for large |z|
+			total += math.Max(z, 0) - t*z + math.Log1p(math.Exp(-math.Abs(z)))
+		}
+		return total
+	}
+
+	// Start from a flat fit at the smoothed base rate
+	a, b := 0.0, math.Log((positives+1)/(negatives+1))
+	current := loss(a, b)
+	for iteration := 0; iteration < 100; iteration++ {
+		// Gradient and Hessian of the log loss
+		var ga, gb, haa, hab, hbb float64
+		for _, s := range samples {
+			x := logit(s.raw)
+			p := sigmoid(a*x + b)
+			diff := p - target(s)
+			w := p * (1 - p)
+			ga += diff * x
+			gb += diff
+			haa += w * x * x
+			hab += w * x
+			hbb += w
+		}
+		if math.Abs(ga) < 1e-8 && math.Abs(gb) < 1e-8 {
+			break
+		}
+
+		// Small ridge keeps the Hessian invertible
+		haa += 1e-6
+		hbb += 1e-6
+		det := haa*hbb - hab*hab
+		if det == 0 {
+			break
+		}
+		da := (hbb*ga - hab*gb) / det
+		db := (haa*gb - hab*ga) / det
+
+		step := 1.0
+		for ; step > 1e-10; step /= 2 {
+			if next := loss(a-step*da, b-step*db); next < current+1e-4*step*(ga*-da+gb*-db) {
+				a -= step * da
+				b -= step * db
+				current = next
+				break
+			}
+		}
+		if step <= 1e-10 {
+			break
+		}
+	}
+
+	return &Model{Method: "platt", Samples: len(samples), A: a, B: b}
+}
+
+// group identifies a market type × duration
+type group struct {
+	marketType string
+	duration   int
+}
+
+func key(marketType string, duration int) group {
+	return group{marketType: marketType, duration: duration}
+}
+
+// logit of a probability clamped away from 0 and 1
+func logit(p float64) float64 {
+	p = math.Max(0.001, math.Min(0.999, p))
+	return math.Log(p / (1 - p))
+}
+
+func sigmoid(x float64) float64 {
+	return 1 / (1 + math.Exp(-x))
+}
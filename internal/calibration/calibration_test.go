@@ -0,0 +1,168 @@
+package calibration
+
+import (
+	"math"
+	"testing"
+)
+
+// samplesOf builds samples from parallel raw confidences and outcomes
+func samplesOf(raw []float64, won []bool) []sample {
+	samples := make([]sample, len(raw))
+	for i := range raw {
+		samples[i] = sample{raw: raw[i], won: won[i]}
+	}
+	return samples
+}
+
+func TestFitIsotonic(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    []float64
+		won    []bool
+		points []Point
+	}{
+		{
+			name:   "already monotone",
+			raw:    []float64{0.55, 0.65, 0.75},
+			won:    []bool{false, true, true},
+			points: []Point{{0.55, 0}, {0.65, 1}, {0.75, 1}},
+		},
+		{
+			// 0.60 wins and 0.70 loses: the violators pool into one block
+			name:   "adjacent violator pooled",
+			raw:    []float64{0.50, 0.60, 0.70, 0.80},
+			won:    []bool{false, true, false, true},
+			points: []Point{{0.50, 0}, {0.65, 0.5}, {0.80, 1}},
+		},
+		{
+			// Pooling 0.70 and 0.80 drops below 0.60's rate, so the merge cascades
+			name:   "cascading merge",
+			raw:    []float64{0.60, 0.70, 0.80},
+			won:    []bool{true, true, false},
+			points: []Point{{0.70, 2.0 / 3}},
+		},
+		{
+			name:   "input order doesn't matter",
+			raw:    []float64{0.80, 0.50, 0.70, 0.60},
+			won:    []bool{true, false, false, true},
+			points: []Point{{0.50, 0}, {0.65, 0.5}, {0.80, 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := fitIsotonic(samplesOf(tt.raw, tt.won))
+			if len(model.Points) != len(tt.points) {
+				t.Fatalf("points = %v, want %v", model.Points, tt.points)
+			}
+			for i, want := range tt.points {
+				got := model.Points[i]
+				if math.Abs(got.Raw-want.Raw) > 1e-12 || math.Abs(got.Calibrated-want.Calibrated) > 1e-12 {
+					t.Errorf("point %d = %+v, want %+v", i, got, want)
+				}
+				if i > 0 && got.Calibrated < model.Points[i-1].Calibrated {
+					t.Errorf("point %d decreases: %v", i, model.Points)
+				}
+			}
+		})
+	}
+}
+
+func TestIsotonicApply(t *testing.T) {
+	model := &Model{Method: "isotonic", Points: []Point{{0.5, 0.4}, {0.7, 0.6}}}
+
+	tests := []struct {
+		raw, want float64
+	}{
+		{0.3, 0.4}, // Clamped below the first knot
+		{0.5, 0.4},
+		{0.6, 0.5}, // Interpolated
+		{0.7, 0.6},
+		{0.9, 0.6}, // Clamped above the last knot
+	}
+	for _, tt := range tests {
+		if got := model.apply(tt.raw); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("apply(%v) = %v, want %v", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestFitPlatt(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  []float64
+		won  []bool
+		// check is run on the fitted model
+		check func(t *testing.T, m *Model)
+	}{
+		{
+			// Without Platt's smoothed targets the slope would run off to infinity
+			name: "separable",
+			raw:  []float64{0.55, 0.56, 0.57, 0.58, 0.72, 0.73, 0.74, 0.75},
+			won:  []bool{false, false, false, false, true, true, true, true},
+			check: func(t *testing.T, m *Model) {
+				low, high := m.apply(0.55), m.apply(0.75)
+				if !(low < 0.5 && high > 0.5) {
+					t.Errorf("apply(0.55) = %v, apply(0.75) = %v, want either side of 0.5", low, high)
+				}
+				// Smoothed targets of 1/6 and 5/6 keep the fit off 0 and 1
+				if low < 0.05 || high > 0.95 {
+					t.Errorf("apply(0.55) = %v, apply(0.75) = %v, want away from 0 and 1", low, high)
+				}
+			},
+		},
+		{
+			// Every raw confidence equal: only the intercept is identified, so
+			// the fit must land on the smoothed win rate (3+1)/(4+2)
+			name: "degenerate raw",
+			raw:  []float64{0.6, 0.6, 0.6, 0.6, 0.6, 0.6},
+			won:  []bool{true, true, true, false, false, true},
+			check: func(t *testing.T, m *Model) {
+				want := (4*5.0/6 + 2*1.0/4) / 6
+				if got := m.apply(0.6); math.Abs(got-want) > 1e-3 {
+					t.Errorf("apply(0.6) = %v, want %v", got, want)
+				}
+			},
+		},
+		{
+			name: "all lost",
+			raw:  []float64{0.6, 0.7, 0.8, 0.9},
+			won:  []bool{false, false, false, false},
+			check: func(t *testing.T, m *Model) {
+				// The smoothed target for all four is 1/6
+				for _, raw := range []float64{0.6, 0.9} {
+					if got := m.apply(raw); math.Abs(got-1.0/6) > 0.01 {
+						t.Errorf("apply(%v) = %v, want about 1/6", raw, got)
+					}
+				}
+			},
+		},
+		{
+			// Win rate rising with raw confidence keeps a positive slope
+			name: "noisy monotone",
+			raw:  []float64{0.55, 0.55, 0.6, 0.6, 0.65, 0.65, 0.7, 0.7, 0.75, 0.75},
+			won:  []bool{false, false, false, true, false, true, true, true, true, true},
+			check: func(t *testing.T, m *Model) {
+				if m.A <= 0 {
+					t.Errorf("A = %v, want positive", m.A)
+				}
+				if m.apply(0.55) >= m.apply(0.75) {
+					t.Errorf("fit is not increasing: %v >= %v", m.apply(0.55), m.apply(0.75))
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := fitPlatt(samplesOf(tt.raw, tt.won))
+			if model.Method != "platt" || model.Samples != len(tt.raw) {
+				t.Fatalf("model = %+v", model)
+			}
+			if math.IsNaN(model.A) || math.IsNaN(model.B) || math.IsInf(model.A, 0) || math.IsInf(model.B, 0) {
+				t.Fatalf("A, B = %v, %v, want finite", model.A, model.B)
+			}
+			tt.check(t, model)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package candles
+
+import (
+	"sort"
+
+	"otc-predictor/pkg/types"
+)
+
+// GapSpacings is how many median tick spacings a step between two ticks may
+// last before it counts as a feed gap (a disconnect, restart or market
+// pause) rather than tick time
+const GapSpacings = 5
+
+// FeedSteps marks which steps between consecutive ticks are inside the feed:
+// inFeed[i] is false when ticks[i-1] to ticks[i] spans a gap (inFeed[0] is
+// unused). tickSeconds is the mean spacing of the steps inside the feed, or
+// 0 when no time passes between them. Returns over gaps mix several ticks'
+// moves into one, and the time they span was never ticked.
+func FeedSteps(ticks []types.Tick) (inFeed []bool, tickSeconds float64) {
+	inFeed = make([]bool, len(ticks))
+	if len(ticks) < 2 {
+		return inFeed, 0
+	}
+
+	spacings := make([]float64, 0, len(ticks)-1)
+	for i := 1; i < len(ticks); i++ {
+		if dt := ticks[i].Timestamp.Sub(ticks[i-1].Timestamp).Seconds(); dt > 0 {
+			spacings = append(spacings, dt)
+		}
+	}
+	if len(spacings) == 0 {
+		for i := 1; i < len(ticks); i++ {
+			inFeed[i] = true
+		}
+		return inFeed, 0
+	}
+	sort.Float64s(spacings)
+	limit := GapSpacings * spacings[len(spacings)/2]
+
+	var total float64
+	steps := 0
+	for i := 1; i < len(ticks); i++ {
+		dt := ticks[i].Timestamp.Sub(ticks[i-1].Timestamp).Seconds()
+		if dt > limit {
+			continue
+		}
+		inFeed[i] = true
+		total += dt
+		steps++
+	}
+	return inFeed, total / float64(steps)
+}
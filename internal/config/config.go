@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 
+	"otc-predictor/internal/consensus"
+	"otc-predictor/internal/indicators"
 	"otc-predictor/pkg/types"
 
 	"gopkg.in/yaml.v3"
@@ -13,6 +15,10 @@ import (
 func Load(filename string) (types.Config, error) {
 	var config types.Config
 
+	// Weights default before parsing so an explicit 0 in the file survives
+	// and disables the signal
+	setWeightDefaults(&config.Strategy)
+
 	data, err := os.ReadFile(filename)
 	if err != nil {
 		return config, fmt.Errorf("failed to read config file: %w", err)
@@ -134,6 +140,130 @@ func setDefaults(config *types.Config) {
 		config.Strategy.BBStdDev = 2.0
 	}
 
+	// Consensus defaults per market type
+	if config.Strategy.Consensus == nil {
+		config.Strategy.Consensus = make(map[string]types.ConsensusConfig)
+	}
+	for _, marketType := range []string{"volatility", "crash_boom", "forex"} {
+		settings := config.Strategy.Consensus[marketType]
+		if settings.MinConfidence == 0 {
+			settings.MinConfidence = config.Strategy.MinConfidence
+		}
+		if settings.RequiredAgreement == 0 {
+			settings.RequiredAgreement = 0.40
+		}
+		if settings.Method == "" {
+			settings.Method = "weighted_majority"
+		}
+		if settings.StackedMinSamples == 0 {
+			settings.StackedMinSamples = 30
+		}
+		if settings.StackedLearnRate == 0 {
+			settings.StackedLearnRate = 0.05
+		}
+		config.Strategy.Consensus[marketType] = settings
+	}
+	if config.Strategy.ConsensusFile == "" {
+		config.Strategy.ConsensusFile = "consensus_state.json"
+	}
+
+	// Adaptive weighting defaults
+	adaptive := &config.Strategy.Adaptive
+	if adaptive.HalfLifeTrades == 0 {
+		adaptive.HalfLifeTrades = 50
+	}
+	if adaptive.PriorStrength == 0 {
+		adaptive.PriorStrength = 10
+	}
+	if adaptive.MinSamples == 0 {
+		adaptive.MinSamples = 20
+	}
+	if adaptive.Floor == 0 {
+		adaptive.Floor = 0.5
+	}
+	if adaptive.Ceiling == 0 {
+		adaptive.Ceiling = 1.5
+	}
+	if adaptive.File == "" {
+		adaptive.File = "adaptive_weights.json"
+	}
+
+	// Multi-timeframe confirmation defaults
+	multiTimeframe := &config.Strategy.MultiTimeframe
+	if multiTimeframe.Higher == nil {
+		multiTimeframe.Higher = map[string][]int{
+			"volatility": {30, 120},
+			"crash_boom": {30, 120},
+			"forex":      {300, 900},
+		}
+	}
+	if multiTimeframe.Boost == 0 {
+		multiTimeframe.Boost = 0.05
+	}
+	if multiTimeframe.VetoStrength == 0 {
+		multiTimeframe.VetoStrength = 0.5
+	}
+
+	// Kalman trend filter defaults
+	kalman := &config.Strategy.Kalman
+	if kalman.LevelNoise == 0 {
+		kalman.LevelNoise = 1.0
+	}
+	if kalman.SlopeNoise == 0 {
+		kalman.SlopeNoise = 0.01
+	}
+	if kalman.MinZ == 0 {
+		kalman.MinZ = 1.5
+	}
+
+	// Regime detector defaults
+	regime := &config.Strategy.Regime
+	if regime.ADXPeriod == 0 {
+		regime.ADXPeriod = 14
+	}
+	if regime.TrendADX == 0 {
+		regime.TrendADX = 25
+	}
+	if regime.EfficiencyPeriod == 0 {
+		regime.EfficiencyPeriod = 20
+	}
+	if regime.TrendEfficiency == 0 {
+		regime.TrendEfficiency = 0.40
+	}
+	if regime.HurstWindow == 0 {
+		regime.HurstWindow = 128
+	}
+	if regime.TrendHurst == 0 {
+		regime.TrendHurst = 0.65
+	}
+	if regime.VolatilityWindow == 0 {
+		regime.VolatilityWindow = 20
+	}
+	if regime.HighVolPercentile == 0 {
+		regime.HighVolPercentile = 0.90
+	}
+	if regime.QuietVolPercentile == 0 {
+		regime.QuietVolPercentile = 0.10
+	}
+	if regime.Rules == nil {
+		trendRule := types.RegimeRule{
+			Disable: []string{"MeanReversion"},
+			Weights: map[string]float64{"Momentum": 1.25, "StrongTrend": 1.25, "MomentumContinuation": 1.2, "BollingerBands": 0.7, "RSI": 0.7},
+		}
+		regime.Rules = map[string]types.RegimeRule{
+			"trending_up":   trendRule,
+			"trending_down": trendRule,
+			"ranging": {
+				Disable: []string{"Momentum", "StrongTrend", "MomentumContinuation"},
+				Weights: map[string]float64{"MeanReversion": 1.25, "SupportResistance": 1.2, "BollingerBands": 1.15},
+			},
+			"high_volatility": {
+				Disable: []string{"Candle_*"},
+				Weights: map[string]float64{"*": 0.85},
+			},
+		}
+	}
+
 	// Risk defaults - global
 	if config.Risk.MaxPredictionsPerMinute == 0 {
 		config.Risk.MaxPredictionsPerMinute = 30
@@ -142,7 +272,7 @@ func setDefaults(config *types.Config) {
 		config.Risk.MinTicksRequired = 60
 	}
 	if config.Risk.SkipHighVolatilityThreshold == 0 {
-		config.Risk.SkipHighVolatilityThreshold = 0.03
+		config.Risk.SkipHighVolatilityThreshold = 2.0
 	}
 	if config.Risk.MaxSpreadPips == 0 {
 		config.Risk.MaxSpreadPips = 2.5
@@ -156,7 +286,7 @@ func setDefaults(config *types.Config) {
 		config.Risk.Synthetics.MinTicksRequired = 60
 	}
 	if config.Risk.Synthetics.SkipHighVolatilityThreshold == 0 {
-		config.Risk.Synthetics.SkipHighVolatilityThreshold = 0.03
+		config.Risk.Synthetics.SkipHighVolatilityThreshold = 2.0
 	}
 	if config.Risk.Synthetics.PreferredDuration == 0 {
 		config.Risk.Synthetics.PreferredDuration = 60
@@ -170,7 +300,7 @@ func setDefaults(config *types.Config) {
 		config.Risk.Forex.MinTicksRequired = 80
 	}
 	if config.Risk.Forex.SkipHighVolatilityThreshold == 0 {
-		config.Risk.Forex.SkipHighVolatilityThreshold = 0.025
+		config.Risk.Forex.SkipHighVolatilityThreshold = 2.0
 	}
 	if config.Risk.Forex.PreferredDuration == 0 {
 		config.Risk.Forex.PreferredDuration = 180
@@ -180,6 +310,9 @@ func setDefaults(config *types.Config) {
 	if config.Storage.MaxTicksInMemory == 0 {
 		config.Storage.MaxTicksInMemory = 500
 	}
+	if config.Storage.MaxArchiveTicks == 0 {
+		config.Storage.MaxArchiveTicks = 20000
+	}
 	if config.Storage.KeepPredictionsHours == 0 {
 		config.Storage.KeepPredictionsHours = 12
 	}
@@ -191,6 +324,9 @@ func setDefaults(config *types.Config) {
 	if config.Tracking.CalculateStatsInterval == 0 {
 		config.Tracking.CalculateStatsInterval = 60
 	}
+	if config.Tracking.AnalyticsRefreshInterval == 0 {
+		config.Tracking.AnalyticsRefreshInterval = 30
+	}
 	if config.Tracking.MinTradesForStats == 0 {
 		config.Tracking.MinTradesForStats = 5
 	}
@@ -198,6 +334,132 @@ func setDefaults(config *types.Config) {
 		config.Tracking.DisplayRecentTrades = 20
 	}
 
+	// Level service defaults
+	if config.Levels.SwingWindow == 0 {
+		config.Levels.SwingWindow = 5
+	}
+	if config.Levels.ClusterTolerance == 0 {
+		config.Levels.ClusterTolerance = 0.0015
+	}
+	if config.Levels.PivotPeriodMinutes == 0 {
+		config.Levels.PivotPeriodMinutes = 1440
+	}
+	if config.Levels.MaxLevels == 0 {
+		config.Levels.MaxLevels = 12
+	}
+
+	// Crash/Boom hazard model defaults
+	if config.Hazard.SpikeSigma == 0 {
+		config.Hazard.SpikeSigma = 8
+	}
+	if config.Hazard.PriorSpikes == 0 {
+		config.Hazard.PriorSpikes = 5
+	}
+	if config.Hazard.MinTicks == 0 {
+		config.Hazard.MinTicks = 1000
+	}
+
+	// Currency strength meter defaults
+	if config.Strength.LookbackMinutes == 0 {
+		config.Strength.LookbackMinutes = 60
+	}
+	if config.Strength.TriangulationToleranceBps == 0 {
+		config.Strength.TriangulationToleranceBps = 5
+	}
+	if config.Strength.MinDifferential == 0 {
+		config.Strength.MinDifferential = 0.10
+	}
+
+	// Correlation analyzer defaults
+	if config.Correlation.BarSeconds == 0 {
+		config.Correlation.BarSeconds = 60
+	}
+	if config.Correlation.WindowBars == 0 {
+		config.Correlation.WindowBars = 240
+	}
+	if config.Correlation.MaxLag == 0 {
+		config.Correlation.MaxLag = 5
+	}
+	if config.Correlation.MinCorrelation == 0 {
+		config.Correlation.MinCorrelation = 0.30
+	}
+	if config.Correlation.MoveSigma == 0 {
+		config.Correlation.MoveSigma = 1.5
+	}
+
+	// Volatility forecaster defaults
+	if config.GARCH.BarSeconds == 0 {
+		config.GARCH.BarSeconds = 60
+	}
+	if config.GARCH.MinBars == 0 {
+		config.GARCH.MinBars = 100
+	}
+	if config.GARCH.EWMALambda == 0 {
+		config.GARCH.EWMALambda = 0.94
+	}
+
+	// Tick-direction Markov model defaults
+	if config.Markov.MaxOrder == 0 {
+		config.Markov.MaxOrder = 3
+	}
+	if config.Markov.Significance == 0 {
+		config.Markov.Significance = 0.01
+	}
+	if config.Markov.MinTicks == 0 {
+		config.Markov.MinTicks = 1000
+	}
+	if config.Markov.MinEffect == 0 {
+		config.Markov.MinEffect = 0.02
+	}
+
+	// Duration recommender defaults
+	if len(config.Recommend.Durations) == 0 {
+		config.Recommend.Durations = []int{30, 60, 120, 180, 300, 600, 900, 1800, 3600}
+	}
+	if config.Recommend.MinTrades == 0 {
+		config.Recommend.MinTrades = 20
+	}
+
+	// Calibration defaults
+	if config.Calibration.Method == "" {
+		config.Calibration.Method = "isotonic"
+	}
+	if config.Calibration.MinSamples == 0 {
+		config.Calibration.MinSamples = 50
+	}
+	if config.Calibration.Bins == 0 {
+		config.Calibration.Bins = 10
+	}
+
+	// Forex session calendar defaults
+	if config.Sessions.Timezone == "" {
+		config.Sessions.Timezone = "America/New_York"
+	}
+	if config.Sessions.Rollover == "" {
+		config.Sessions.Rollover = "17:00"
+	}
+	if config.Sessions.Sessions == nil {
+		config.Sessions.Sessions = []types.SessionConfig{
+			{Name: "sydney", Timezone: "Australia/Sydney", Open: "07:00", Close: "16:00", Currencies: []string{"AUD", "NZD"}, Asian: true},
+			{Name: "tokyo", Timezone: "Asia/Tokyo", Open: "09:00", Close: "18:00", Currencies: []string{"JPY"}, Asian: true},
+			{Name: "london", Timezone: "Europe/London", Open: "08:00", Close: "17:00", Currencies: []string{"GBP", "EUR", "CHF"}},
+			{Name: "new_york", Timezone: "America/New_York", Open: "08:00", Close: "17:00", Currencies: []string{"USD", "CAD"}},
+		}
+	}
+
+	// News blackout defaults
+	if config.News.Windows == nil {
+		config.News.Windows = map[string]types.NewsWindow{
+			"high":   {Before: 15, After: 30},
+			"medium": {Before: 5, After: 10},
+		}
+	}
+
+	// Payout defaults
+	if config.Payout.Default == 0 {
+		config.Payout.Default = 0.85
+	}
+
 	// Logging defaults
 	if config.Logging.Level == "" {
 		config.Logging.Level = "info"
@@ -211,6 +473,47 @@ func setDefaults(config *types.Config) {
 	}
 }
 
+// setWeightDefaults fills every strategy weight. It runs before the file is
+// parsed, so a weight the file sets (including 0) replaces the default.
+func setWeightDefaults(strategy *types.StrategyConfig) {
+	strategy.Volatility = types.VolatilityWeights{
+		MeanReversionWeight: 0.45,
+		MomentumWeight:      0.35,
+		BollingerWeight:     0.25,
+		RSIWeight:           0.15,
+		EMACrossoverWeight:  0.30,
+		SignalWeights:       defaultSignalWeights(),
+	}
+	strategy.CrashBoom = types.CrashBoomWeights{
+		TrendWeight:      0.30,
+		VolatilityWeight: 0.25,
+		RecoveryWeight:   0.20,
+		HazardWeight:     0.45,
+		SignalWeights:    defaultSignalWeights(),
+	}
+	strategy.Forex = types.ForexWeights{
+		TrendFollowingWeight:    0.45,
+		SupportResistanceWeight: 0.35,
+		EMACrossoverWeight:      0.40,
+		PullbackWeight:          0.30,
+		StrengthWeight:          0.35,
+		SignalWeights:           defaultSignalWeights(),
+	}
+}
+
+// defaultSignalWeights returns the weights shared by every market type
+func defaultSignalWeights() types.SignalWeights {
+	return types.SignalWeights{
+		DivergenceWeight:  0.55,
+		PatternWeight:     0.45,
+		CandlestickWeight: 0.30,
+		LevelWeight:       0.30,
+		LeadLagWeight:     0.35,
+		KalmanWeight:      0.40,
+		MarkovWeight:      0.35,
+	}
+}
+
 // validate validates configuration
 func validate(config types.Config) error {
 	if len(config.Markets) == 0 {
@@ -229,6 +532,88 @@ func validate(config types.Config) error {
 		return fmt.Errorf("invalid API port")
 	}
 
+	for marketType, settings := range config.Strategy.Consensus {
+		if settings.MinConfidence < 0 || settings.MinConfidence > 1 {
+			return fmt.Errorf("consensus.%s.min_confidence must be between 0 and 1", marketType)
+		}
+		if settings.RequiredAgreement < 0 || settings.RequiredAgreement > 1 {
+			return fmt.Errorf("consensus.%s.required_agreement must be between 0 and 1", marketType)
+		}
+		if !consensus.IsMethod(settings.Method) {
+			return fmt.Errorf("consensus.%s.method '%s' is not one of %v", marketType, settings.Method, consensus.Methods)
+		}
+	}
+
+	if config.Strategy.Adaptive.Floor > config.Strategy.Adaptive.Ceiling {
+		return fmt.Errorf("adaptive.floor must not exceed adaptive.ceiling")
+	}
+
+	if config.Strategy.Kalman.LevelNoise < 0 || config.Strategy.Kalman.SlopeNoise < 0 {
+		return fmt.Errorf("strategy.kalman noise ratios must not be negative")
+	}
+
+	if config.Calibration.Method != "isotonic" && config.Calibration.Method != "platt" {
+		return fmt.Errorf("calibration method '%s' must be 'isotonic' or 'platt'", config.Calibration.Method)
+	}
+
+	if config.Hazard.SpikeSigma < 0 || config.Hazard.PriorSpikes < 0 {
+		return fmt.Errorf("hazard.spike_sigma and hazard.prior_spikes must not be negative")
+	}
+
+	// Volatility thresholds are multiples of the long-run level; values from
+	// before GARCH (fractions of price) would skip every entry
+	for name, threshold := range map[string]float64{
+		"risk":            config.Risk.SkipHighVolatilityThreshold,
+		"risk.synthetics": config.Risk.Synthetics.SkipHighVolatilityThreshold,
+		"risk.forex":      config.Risk.Forex.SkipHighVolatilityThreshold,
+	} {
+		if threshold <= 1 {
+			return fmt.Errorf("%s.skip_high_volatility_threshold must be above 1 (a multiple of long-run volatility), got %g", name, threshold)
+		}
+	}
+
+	if config.GARCH.BarSeconds < 1 {
+		return fmt.Errorf("garch.bar_seconds must be positive")
+	}
+	if config.GARCH.EWMALambda <= 0 || config.GARCH.EWMALambda >= 1 {
+		return fmt.Errorf("garch.ewma_lambda must be between 0 and 1")
+	}
+
+	if config.Markov.MaxOrder < 1 || config.Markov.MaxOrder > 3 {
+		return fmt.Errorf("markov.max_order must be between 1 and 3")
+	}
+	if config.Markov.Significance <= 0 || config.Markov.Significance >= 1 {
+		return fmt.Errorf("markov.significance must be between 0 and 1")
+	}
+	if config.Markov.MinEffect < 0 || config.Markov.MinEffect >= 0.5 {
+		return fmt.Errorf("markov.min_effect must be between 0 and 0.5")
+	}
+
+	for _, spec := range config.Strategy.ExtraIndicators {
+		def, ok := indicators.Lookup(spec.Key)
+		if !ok {
+			return fmt.Errorf("extra_indicators: unknown indicator '%s'", spec.Key)
+		}
+		if _, err := def.Resolve(spec.Params); err != nil {
+			return fmt.Errorf("extra_indicators: %w", err)
+		}
+	}
+
+	for _, duration := range config.Recommend.Durations {
+		if duration < types.MinDuration || duration > types.MaxDuration {
+			return fmt.Errorf("recommend.durations must be between %d and %d seconds, got %d", types.MinDuration, types.MaxDuration, duration)
+		}
+	}
+
+	if config.Payout.Default <= 0 {
+		return fmt.Errorf("payout.default must be positive")
+	}
+	for i, rule := range config.Payout.Rules {
+		if rule.Payout <= 0 {
+			return fmt.Errorf("payout.rules[%d].payout must be positive", i)
+		}
+	}
+
 	// Validate mode
 	validModes := map[string]bool{"synthetics": true, "forex": true, "both": true}
 	if !validModes[config.Mode] {
@@ -0,0 +1,493 @@
+package consensus
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"otc-predictor/pkg/types"
+)
+
+// Method names usable in strategy.consensus.<market type>.method
+const (
+	Majority         = "majority"
+	WeightedMajority = "weighted_majority"
+	Unanimity        = "unanimity"
+	LogOdds          = "log_odds"
+	Stacked          = "stacked"
+)
+
+// Methods lists every consensus method in reporting order
+var Methods = []string{Majority, WeightedMajority, Unanimity, LogOdds, Stacked}
+
+// Method combines strategy signals into one verdict
+type Method interface {
+	Name() string
+	Combine(marketType string, signals []types.StrategySignal, config types.ConsensusConfig) types.ConsensusVerdict
+}
+
+// Combiner runs every consensus method on the same signals
+type Combiner struct {
+	methods map[string]Method
+	stacked *StackedModel
+	scores  map[string]map[string]*MethodScore // Market type -> method
+	file    string
+	dirty   bool
+	mu      sync.RWMutex
+}
+
+// state is what Save writes: method scores and the stacked model, both by
+// market type
+type state struct {
+	Scores  map[string]map[string]*MethodScore `json:"scores"`
+	Stacked map[string]*logisticModel          `json:"stacked"`
+}
+
+// MethodScore is how often a method's calls matched the realized direction
+type MethodScore struct {
+	Calls    int     `json:"calls"`   // Resolved predictions where it chose a side
+	Correct  int     `json:"correct"` // Calls matching the price move
+	Accuracy float64 `json:"accuracy"`
+}
+
+// NewCombiner creates a combiner with all methods. The stacked model starts
+// empty and learns from resolved predictions through Observe; Save and Load
+// keep it and the method scores in file across restarts.
+func NewCombiner(file string) *Combiner {
+	stacked := NewStackedModel()
+	return &Combiner{
+		methods: map[string]Method{
+			Majority:         majority{},
+			WeightedMajority: weightedMajority{},
+			Unanimity:        unanimity{},
+			LogOdds:          logOdds{},
+			Stacked:          stacked,
+		},
+		stacked: stacked,
+		scores:  make(map[string]map[string]*MethodScore),
+		file:    file,
+	}
+}
+
+// IsMethod reports whether a consensus method name is known
+func IsMethod(name string) bool {
+	for _, method := range Methods {
+		if method == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Combine returns every method's verdict with the configured one first and
+// marked Selected. An unknown method falls back to weighted majority.
+func (c *Combiner) Combine(marketType string, signals []types.StrategySignal, config types.ConsensusConfig) []types.ConsensusVerdict {
+	selected := config.Method
+	if _, known := c.methods[selected]; !known {
+		selected = WeightedMajority
+	}
+
+	verdicts := make([]types.ConsensusVerdict, 0, len(Methods))
+	for _, name := range Methods {
+		verdict := c.methods[name].Combine(marketType, signals, config)
+		verdict.Method = name
+		sortContributions(verdict.Contributions)
+		if name == selected {
+			verdict.Selected = true
+			verdicts = append([]types.ConsensusVerdict{verdict}, verdicts...)
+			continue
+		}
+		verdicts = append(verdicts, verdict)
+	}
+	return verdicts
+}
+
+// Observe scores each method's verdict on a resolved prediction and trains
+// the stacked model. up is whether price finished above the entry.
+func (c *Combiner) Observe(marketType string, signals []types.StrategySignal, verdicts []types.ConsensusVerdict, up bool, config types.ConsensusConfig) {
+	actual := "DOWN"
+	if up {
+		actual = "UP"
+	}
+
+	c.mu.Lock()
+	if c.scores[marketType] == nil {
+		c.scores[marketType] = make(map[string]*MethodScore)
+	}
+	for _, verdict := range verdicts {
+		if verdict.Direction == "NONE" {
+			continue
+		}
+		score := c.scores[marketType][verdict.Method]
+		if score == nil {
+			score = &MethodScore{}
+			c.scores[marketType][verdict.Method] = score
+		}
+		score.Calls++
+		if verdict.Direction == actual {
+			score.Correct++
+		}
+		score.Accuracy = float64(score.Correct) / float64(score.Calls)
+	}
+	c.dirty = true
+	c.mu.Unlock()
+
+	c.stacked.Observe(marketType, signals, up, config)
+}
+
+// Scores returns each method's record for a market type
+func (c *Combiner) Scores(marketType string) map[string]MethodScore {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	scores := make(map[string]MethodScore)
+	for method, score := range c.scores[marketType] {
+		scores[method] = *score
+	}
+	return scores
+}
+
+// Save writes the method scores and stacked model to the configured file if
+// they changed
+func (c *Combiner) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.dirty || c.file == "" {
+		return nil
+	}
+
+	c.stacked.mu.RLock()
+	data, err := json.MarshalIndent(state{Scores: c.scores, Stacked: c.stacked.models}, "", "  ")
+	c.stacked.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode consensus state: %w", err)
+	}
+
+	// Write then rename so a crash never leaves a half-written file
+	tmp := c.file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write consensus state: %w", err)
+	}
+	if err := os.Rename(tmp, c.file); err != nil {
+		return fmt.Errorf("failed to write consensus state: %w", err)
+	}
+
+	c.dirty = false
+	return nil
+}
+
+// Load restores method scores and the stacked model from the configured
+// file. A missing file is not an error.
+func (c *Combiner) Load() error {
+	if c.file == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(c.file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read consensus state: %w", err)
+	}
+
+	var saved state
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return fmt.Errorf("failed to parse consensus state: %w", err)
+	}
+
+	c.mu.Lock()
+	for marketType, scores := range saved.Scores {
+		c.scores[marketType] = scores
+	}
+	c.mu.Unlock()
+
+	c.stacked.mu.Lock()
+	for marketType, model := range saved.Stacked {
+		if model.Weights == nil {
+			model.Weights = make(map[string]float64)
+		}
+		c.stacked.models[marketType] = model
+	}
+	c.stacked.mu.Unlock()
+	return nil
+}
+
+// majority is the original vote count rule: the side with more signals wins
+// if it has RequiredAgreement of the votes; confidence is its weighted
+// average confidence
+type majority struct{}
+
+func (majority) Name() string { return Majority }
+
+func (majority) Combine(marketType string, signals []types.StrategySignal, config types.ConsensusConfig) types.ConsensusVerdict {
+	sides := tally(signals)
+	total := sides.upVotes + sides.downVotes
+	if total == 0 {
+		return none("No directional signals", nil)
+	}
+
+	verdict := none(fmt.Sprintf("Conflicting signals: %d UP vs %d DOWN", sides.upVotes, sides.downVotes), nil)
+	direction, votes := "UP", sides.upVotes
+	if sides.downVotes > sides.upVotes {
+		direction, votes = "DOWN", sides.downVotes
+	}
+
+	for _, signal := range signals {
+		if signal.Direction == "UP" || signal.Direction == "DOWN" {
+			verdict.Contributions = append(verdict.Contributions, contribution(signal, sign(signal.Direction)/float64(total)))
+		}
+	}
+
+	voteRatio := float64(votes) / float64(total)
+	if sides.upVotes == sides.downVotes || voteRatio < config.RequiredAgreement {
+		return verdict
+	}
+
+	verdict.Direction = direction
+	verdict.Confidence = sides.weightedConfidence(direction)
+	verdict.Reason = fmt.Sprintf("%d/%d strategies %s (%.0f%% agreement): %v",
+		votes, total, direction, voteRatio*100, sides.reasons(direction))
+	return verdict
+}
+
+// weightedMajority lets each signal vote with its weight; the heavier side
+// wins if it carries RequiredAgreement of the total weight
+type weightedMajority struct{}
+
+func (weightedMajority) Name() string { return WeightedMajority }
+
+func (weightedMajority) Combine(marketType string, signals []types.StrategySignal, config types.ConsensusConfig) types.ConsensusVerdict {
+	sides := tally(signals)
+	totalWeight := sides.upWeight + sides.downWeight
+	if totalWeight == 0 {
+		return none("No directional signals", nil)
+	}
+
+	var contributions []types.SignalContribution
+	for _, signal := range signals {
+		if signal.Direction == "UP" || signal.Direction == "DOWN" {
+			contributions = append(contributions, contribution(signal, sign(signal.Direction)*signal.Weight/totalWeight))
+		}
+	}
+
+	direction, weight := "UP", sides.upWeight
+	if sides.downWeight > sides.upWeight {
+		direction, weight = "DOWN", sides.downWeight
+	}
+
+	share := weight / totalWeight
+	if sides.upWeight == sides.downWeight || share < config.RequiredAgreement {
+		return none(fmt.Sprintf("Conflicting weight: %.2f UP vs %.2f DOWN", sides.upWeight, sides.downWeight), contributions)
+	}
+
+	return types.ConsensusVerdict{
+		Direction:     direction,
+		Confidence:    sides.weightedConfidence(direction),
+		Reason:        fmt.Sprintf("%.0f%% of signal weight %s: %v", share*100, direction, sides.reasons(direction)),
+		Contributions: contributions,
+	}
+}
+
+// unanimity requires every veto signal to agree with the weighted majority.
+// With no veto list every directional signal can veto.
+type unanimity struct{}
+
+func (unanimity) Name() string { return Unanimity }
+
+func (unanimity) Combine(marketType string, signals []types.StrategySignal, config types.ConsensusConfig) types.ConsensusVerdict {
+	sides := tally(signals)
+	totalWeight := sides.upWeight + sides.downWeight
+	if totalWeight == 0 || sides.upWeight == sides.downWeight {
+		return none("No clear side to agree on", nil)
+	}
+
+	direction := "UP"
+	if sides.downWeight > sides.upWeight {
+		direction = "DOWN"
+	}
+
+	var contributions []types.SignalContribution
+	var vetoes []string
+	for _, signal := range signals {
+		if signal.Direction != "UP" && signal.Direction != "DOWN" {
+			continue
+		}
+		entry := contribution(signal, sign(signal.Direction)*signal.Weight/totalWeight)
+		if signal.Direction != direction && canVeto(signal.Name, config.Veto) {
+			entry.Vetoed = true
+			vetoes = append(vetoes, signal.Name)
+		}
+		contributions = append(contributions, entry)
+	}
+
+	if len(vetoes) > 0 {
+		return none(fmt.Sprintf("Vetoed by %s", strings.Join(vetoes, ", ")), contributions)
+	}
+
+	return types.ConsensusVerdict{
+		Direction:     direction,
+		Confidence:    sides.weightedConfidence(direction),
+		Reason:        fmt.Sprintf("No veto against %s: %v", direction, sides.reasons(direction)),
+		Contributions: contributions,
+	}
+}
+
+// logOdds fuses signal probabilities naive-Bayes style: each signal adds
+// weight × logit(p) towards its direction, starting from even odds. p is the
+// signal's win rate, its confidence shrunk towards its tracked record, so a
+// signal that has been right half the time adds nothing however confident
+// it claims to be.
+type logOdds struct{}
+
+func (logOdds) Name() string { return LogOdds }
+
+func (logOdds) Combine(marketType string, signals []types.StrategySignal, config types.ConsensusConfig) types.ConsensusVerdict {
+	var contributions []types.SignalContribution
+	sum := 0.0
+	for _, signal := range signals {
+		if signal.Direction != "UP" && signal.Direction != "DOWN" {
+			continue
+		}
+		p := signal.WinRate
+		if p == 0 {
+			p = signal.Confidence
+		}
+		term := sign(signal.Direction) * signal.Weight * logit(p)
+		sum += term
+		contributions = append(contributions, contribution(signal, term))
+	}
+
+	if len(contributions) == 0 {
+		return none("No directional signals", nil)
+	}
+
+	return fromProbability(sigmoid(sum), fmt.Sprintf("Log-odds %+.2f from %d signals", sum, len(contributions)), contributions)
+}
+
+// fromProbability turns P(UP) into a verdict
+func fromProbability(pUp float64, reason string, contributions []types.SignalContribution) types.ConsensusVerdict {
+	if pUp == 0.5 {
+		return none(reason, contributions)
+	}
+
+	verdict := types.ConsensusVerdict{
+		Direction:     "UP",
+		Confidence:    pUp,
+		Reason:        reason,
+		Contributions: contributions,
+	}
+	if pUp < 0.5 {
+		verdict.Direction = "DOWN"
+		verdict.Confidence = 1 - pUp
+	}
+	return verdict
+}
+
+// sideTally sums directional votes, weights and confidences
+type sideTally struct {
+	upVotes, downVotes           int
+	upWeight, downWeight         float64
+	upConfidence, downConfidence float64 // Confidence × weight
+	upReasons, downReasons       []string
+}
+
+func tally(signals []types.StrategySignal) sideTally {
+	var t sideTally
+	for _, signal := range signals {
+		label := fmt.Sprintf("%s(%.0f%%)", signal.Name, signal.Confidence*100)
+		switch signal.Direction {
+		case "UP":
+			t.upVotes++
+			t.upWeight += signal.Weight
+			t.upConfidence += signal.Confidence * signal.Weight
+			t.upReasons = append(t.upReasons, label)
+		case "DOWN":
+			t.downVotes++
+			t.downWeight += signal.Weight
+			t.downConfidence += signal.Confidence * signal.Weight
+			t.downReasons = append(t.downReasons, label)
+		}
+	}
+	return t
+}
+
+// weightedConfidence is the weight-averaged confidence of one side
+func (t sideTally) weightedConfidence(direction string) float64 {
+	if direction == "UP" && t.upWeight > 0 {
+		return t.upConfidence / t.upWeight
+	}
+	if direction == "DOWN" && t.downWeight > 0 {
+		return t.downConfidence / t.downWeight
+	}
+	return 0
+}
+
+func (t sideTally) reasons(direction string) []string {
+	if direction == "UP" {
+		return t.upReasons
+	}
+	return t.downReasons
+}
+
+// canVeto reports whether a signal is on the veto list ("Candle_*" matches a
+// prefix); an empty list lets every signal veto
+func canVeto(name string, veto []string) bool {
+	if len(veto) == 0 {
+		return true
+	}
+	for _, pattern := range veto {
+		if pattern == "*" || pattern == name ||
+			(strings.HasSuffix(pattern, "*") && strings.HasPrefix(name, strings.TrimSuffix(pattern, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+func none(reason string, contributions []types.SignalContribution) types.ConsensusVerdict {
+	return types.ConsensusVerdict{
+		Direction:     "NONE",
+		Reason:        reason,
+		Contributions: contributions,
+	}
+}
+
+func contribution(signal types.StrategySignal, value float64) types.SignalContribution {
+	return types.SignalContribution{
+		Name:         signal.Name,
+		Direction:    signal.Direction,
+		Confidence:   signal.Confidence,
+		Weight:       signal.Weight,
+		Contribution: value,
+	}
+}
+
+// sortContributions orders contributions by absolute size, largest first
+func sortContributions(contributions []types.SignalContribution) {
+	sort.SliceStable(contributions, func(i, j int) bool {
+		return math.Abs(contributions[i].Contribution) > math.Abs(contributions[j].Contribution)
+	})
+}
+
+func sign(direction string) float64 {
+	if direction == "DOWN" {
+		return -1
+	}
+	return 1
+}
+
+// logit of a probability clamped away from 0 and 1
+func logit(p float64) float64 {
+	p = math.Max(0.01, math.Min(0.99, p))
+	return math.Log(p / (1 - p))
+}
+
+func sigmoid(x float64) float64 {
+	return 1 / (1 + math.Exp(-x))
+}
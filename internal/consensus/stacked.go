@@ -0,0 +1,116 @@
+package consensus
+
+import (
+	"fmt"
+	"sync"
+
+	"otc-predictor/pkg/types"
+)
+
+// StackedModel is an online logistic regression over signal outputs,
+// learned per market type from tracked results. Each signal name is a
+// feature worth +confidence when it votes UP and -confidence when it votes
+// DOWN. Until a market type has StackedMinSamples results the verdict falls
+// back to weighted majority.
+type StackedModel struct {
+	models map[string]*logisticModel // Keyed by market type
+	mu     sync.RWMutex
+}
+
+type logisticModel struct {
+	Bias    float64            `json:"bias"`
+	Weights map[string]float64 `json:"weights"`
+	Samples int                `json:"samples"`
+}
+
+// NewStackedModel creates an untrained stacked model
+func NewStackedModel() *StackedModel {
+	return &StackedModel{
+		models: make(map[string]*logisticModel),
+	}
+}
+
+func (m *StackedModel) Name() string { return Stacked }
+
+// Combine scores the signals with the learned coefficients
+func (m *StackedModel) Combine(marketType string, signals []types.StrategySignal, config types.ConsensusConfig) types.ConsensusVerdict {
+	m.mu.RLock()
+	model := m.models[marketType]
+	samples, bias := 0, 0.0
+	coefficients := map[string]float64{}
+	if model != nil {
+		samples, bias = model.Samples, model.Bias
+		for name, weight := range model.Weights {
+			coefficients[name] = weight
+		}
+	}
+	m.mu.RUnlock()
+
+	if samples < config.StackedMinSamples {
+		verdict := weightedMajority{}.Combine(marketType, signals, config)
+		verdict.Reason = fmt.Sprintf("Stacked model untrained (%d/%d results) - weighted majority: %s",
+			samples, config.StackedMinSamples, verdict.Reason)
+		return verdict
+	}
+
+	var contributions []types.SignalContribution
+	sum := bias
+	for _, signal := range signals {
+		if signal.Direction != "UP" && signal.Direction != "DOWN" {
+			continue
+		}
+		term := coefficients[signal.Name] * feature(signal)
+		sum += term
+		contributions = append(contributions, contribution(signal, term))
+	}
+
+	if len(contributions) == 0 {
+		return none("No directional signals", nil)
+	}
+
+	return fromProbability(sigmoid(sum), fmt.Sprintf("Stacked model %+.2f (bias %+.2f, %d results)", sum, bias, samples), contributions)
+}
+
+// Observe takes one stochastic gradient step towards the realized direction
+func (m *StackedModel) Observe(marketType string, signals []types.StrategySignal, up bool, config types.ConsensusConfig) {
+	features := map[string]float64{}
+	for _, signal := range signals {
+		if signal.Direction == "UP" || signal.Direction == "DOWN" {
+			features[signal.Name] += feature(signal)
+		}
+	}
+	if len(features) == 0 {
+		return
+	}
+
+	target := 0.0
+	if up {
+		target = 1
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	model := m.models[marketType]
+	if model == nil {
+		model = &logisticModel{Weights: make(map[string]float64)}
+		m.models[marketType] = model
+	}
+
+	sum := model.Bias
+	for name, x := range features {
+		sum += model.Weights[name] * x
+	}
+
+	gradient := target - sigmoid(sum)
+	model.Bias += config.StackedLearnRate * gradient
+	for name, x := range features {
+		model.Weights[name] += config.StackedLearnRate * gradient * x
+	}
+	model.Samples++
+}
+
+// feature is a signal's signed confidence
+func feature(signal types.StrategySignal) float64 {
+	return sign(signal.Direction) * signal.Confidence
+}
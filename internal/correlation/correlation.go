@@ -0,0 +1,262 @@
+package correlation
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"otc-predictor/internal/storage"
+	"otc-predictor/pkg/types"
+)
+
+// Analyzer measures return correlations and lead-lag relationships between
+// every active market. Archive ticks are resampled onto a shared bar grid so
+// markets that tick at different rates line up; a leader is a market whose
+// returns correlate with another's returns a few bars later.
+type Analyzer struct {
+	storage *storage.MemoryStorage
+	config  types.CorrelationConfig
+	report  Report
+	sigmas  map[string]float64 // Bar return deviation per market
+	mu      sync.RWMutex
+}
+
+// Report is the latest analysis
+type Report struct {
+	BarSeconds int         `json:"bar_seconds"`
+	Bars       int         `json:"bars"`
+	Markets    []string    `json:"markets"`
+	Matrix     [][]float64 `json:"matrix"` // Same-bar return correlation, rows and columns as Markets
+	LeadLag    []LeadLag   `json:"lead_lag"`
+	Updated    time.Time   `json:"updated"`
+}
+
+// LeadLag is a leader whose returns anticipate a follower's
+type LeadLag struct {
+	Leader      string  `json:"leader"`
+	Follower    string  `json:"follower"`
+	Lag         int     `json:"lag"`         // Bars the follower trails by
+	Correlation float64 `json:"correlation"` // Of leader returns with follower returns Lag bars later
+	Samples     int     `json:"samples"`
+}
+
+// Move is a leader's recent move that a follower has yet to answer
+type Move struct {
+	LeadLag
+	Return float64 `json:"return"` // Leader log return over the last Lag bars
+	ZScore float64 `json:"z_score"`
+}
+
+// NewAnalyzer creates an analyzer over the markets in storage
+func NewAnalyzer(store *storage.MemoryStorage, config types.CorrelationConfig) *Analyzer {
+	return &Analyzer{
+		storage: store,
+		config:  config,
+		report:  Report{Markets: []string{}, Matrix: [][]float64{}, LeadLag: []LeadLag{}},
+		sigmas:  make(map[string]float64),
+	}
+}
+
+// Refresh recomputes the correlation matrix and lead-lag pairs from the archive
+func (a *Analyzer) Refresh() {
+	bar := time.Duration(a.config.BarSeconds) * time.Second
+	end := time.Now().Truncate(bar)
+	start := end.Add(-time.Duration(a.config.WindowBars) * bar)
+
+	markets := a.storage.GetActiveMarkets()
+	sort.Strings(markets)
+
+	returns := make(map[string][]float64, len(markets))
+	sigmas := make(map[string]float64, len(markets))
+	for _, market := range markets {
+		r := barReturns(a.storage.GetArchiveTicks(market), start, bar, a.config.WindowBars)
+		returns[market] = r
+		sigmas[market] = deviation(r)
+	}
+
+	report := Report{
+		BarSeconds: a.config.BarSeconds,
+		Bars:       a.config.WindowBars,
+		Markets:    markets,
+		Matrix:     make([][]float64, len(markets)),
+		LeadLag:    []LeadLag{},
+		Updated:    time.Now(),
+	}
+
+	for i, leader := range markets {
+		report.Matrix[i] = make([]float64, len(markets))
+		for j, follower := range markets {
+			if i == j {
+				report.Matrix[i][j] = 1
+				continue
+			}
+			report.Matrix[i][j], _ = lagged(returns[leader], returns[follower], 0)
+
+			if best, ok := a.bestLag(returns[leader], returns[follower]); ok {
+				best.Leader, best.Follower = leader, follower
+				report.LeadLag = append(report.LeadLag, best)
+			}
+		}
+	}
+
+	sort.Slice(report.LeadLag, func(i, j int) bool {
+		return math.Abs(report.LeadLag[i].Correlation) > math.Abs(report.LeadLag[j].Correlation)
+	})
+
+	a.mu.Lock()
+	a.report = report
+	a.sigmas = sigmas
+	a.mu.Unlock()
+}
+
+// Report returns the latest analysis
+func (a *Analyzer) Report() Report {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.report
+}
+
+// RecentMoves returns the leaders of a market that moved at least MoveSigma
+// bar deviations over their lag ending at now
+func (a *Analyzer) RecentMoves(follower string, now time.Time) []Move {
+	a.mu.RLock()
+	var leads []LeadLag
+	for _, lead := range a.report.LeadLag {
+		if lead.Follower == follower {
+			leads = append(leads, lead)
+		}
+	}
+	sigmas := a.sigmas
+	a.mu.RUnlock()
+
+	bar := time.Duration(a.config.BarSeconds) * time.Second
+	moves := []Move{}
+	for _, lead := range leads {
+		sigma := sigmas[lead.Leader]
+		if sigma <= 0 {
+			continue
+		}
+
+		ticks := a.storage.GetAllTicks(lead.Leader)
+		if len(ticks) < 2 {
+			continue
+		}
+		then := priceAt(ticks, now.Add(-time.Duration(lead.Lag)*bar))
+		current := priceAt(ticks, now)
+		if then <= 0 || current <= 0 {
+			continue
+		}
+
+		r := math.Log(current / then)
+		z := r / (sigma * math.Sqrt(float64(lead.Lag)))
+		if math.Abs(z) >= a.config.MoveSigma {
+			moves = append(moves, Move{LeadLag: lead, Return: r, ZScore: z})
+		}
+	}
+	return moves
+}
+
+// bestLag finds the lag in 1..MaxLag with the strongest correlation of
+// leader returns with later follower returns, if it is both above
+// MinCorrelation and significant (|r| > 2/sqrt(n))
+func (a *Analyzer) bestLag(leader, follower []float64) (LeadLag, bool) {
+	var best LeadLag
+	for lag := 1; lag <= a.config.MaxLag; lag++ {
+		r, n := lagged(leader, follower, lag)
+		if n < 10 || math.Abs(r) <= math.Abs(best.Correlation) {
+			continue
+		}
+		best = LeadLag{Lag: lag, Correlation: r, Samples: n}
+	}
+
+	if best.Lag == 0 || math.Abs(best.Correlation) < a.config.MinCorrelation ||
+		math.Abs(best.Correlation) <= 2/math.Sqrt(float64(best.Samples)) {
+		return LeadLag{}, false
+	}
+	return best, true
+}
+
+// barReturns resamples ticks to bar closes from start and returns the log
+// return of each bar; bars before the first tick are NaN
+func barReturns(ticks []types.Tick, start time.Time, bar time.Duration, bars int) []float64 {
+	closes := make([]float64, bars+1)
+	idx := 0
+	last := math.NaN()
+	for i := range closes {
+		barEnd := start.Add(time.Duration(i) * bar)
+		for idx < len(ticks) && !ticks[idx].Timestamp.After(barEnd) {
+			last = ticks[idx].Price
+			idx++
+		}
+		closes[i] = last
+	}
+
+	returns := make([]float64, bars)
+	for i := range returns {
+		returns[i] = math.NaN()
+		if closes[i] > 0 && closes[i+1] > 0 {
+			returns[i] = math.Log(closes[i+1] / closes[i])
+		}
+	}
+	return returns
+}
+
+// lagged is the Pearson correlation of x[t] with y[t+lag] over bars where
+// both are known, and the number of such bars
+func lagged(x, y []float64, lag int) (float64, int) {
+	var sx, sy, sxx, syy, sxy float64
+	n := 0
+	for t := 0; t+lag < len(y) && t < len(x); t++ {
+		a, b := x[t], y[t+lag]
+		if math.IsNaN(a) || math.IsNaN(b) {
+			continue
+		}
+		sx += a
+		sy += b
+		sxx += a * a
+		syy += b * b
+		sxy += a * b
+		n++
+	}
+	if n < 2 {
+		return 0, n
+	}
+
+	nf := float64(n)
+	cov := sxy - sx*sy/nf
+	vx := sxx - sx*sx/nf
+	vy := syy - sy*sy/nf
+	if vx <= 0 || vy <= 0 {
+		return 0, n
+	}
+	return cov / math.Sqrt(vx*vy), n
+}
+
+// deviation is the standard deviation of the known values
+func deviation(values []float64) float64 {
+	var sum, sumSq float64
+	n := 0
+	for _, v := range values {
+		if math.IsNaN(v) {
+			continue
+		}
+		sum += v
+		sumSq += v * v
+		n++
+	}
+	if n < 2 {
+		return 0
+	}
+	mean := sum / float64(n)
+	return math.Sqrt(math.Max(0, sumSq/float64(n)-mean*mean))
+}
+
+// priceAt returns the last price at or before t
+func priceAt(ticks []types.Tick, t time.Time) float64 {
+	i := sort.Search(len(ticks), func(i int) bool { return ticks[i].Timestamp.After(t) })
+	if i == 0 {
+		return 0
+	}
+	return ticks[i-1].Price
+}
@@ -0,0 +1,291 @@
+package garch
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"otc-predictor/internal/candles"
+	"otc-predictor/internal/storage"
+	"otc-predictor/pkg/types"
+)
+
+// minEWMABars is the fewest bar returns an EWMA fallback is estimated from
+const minEWMABars = 20
+
+// Service fits a GARCH(1,1) volatility model per market on bar returns from
+// the tick archive:
+//
+//	σ²[t] = ω + α·r²[t-1] + β·σ²[t-1]
+//
+// Variance shocks decay at rate α+β towards the long-run variance ω/(1-α-β),
+// so a forecast over several bars starts at today's variance and drifts back
+// to normal. Markets with too few bars, or whose fit has no mean reversion,
+// fall back to a RiskMetrics-style EWMA with a flat forecast.
+type Service struct {
+	storage *storage.MemoryStorage
+	config  types.GARCHConfig
+	models  map[string]Model
+	fitted  map[string]time.Time // Last fit attempt per market, successful or not
+	mu      sync.RWMutex
+}
+
+// Model is the fitted volatility model of one market
+type Model struct {
+	Market        string    `json:"market"`
+	Method        string    `json:"method"` // "garch" or "ewma"
+	BarSeconds    int       `json:"bar_seconds"`
+	Bars          int       `json:"bars"` // Bar returns fitted
+	Omega         float64   `json:"omega,omitempty"`
+	Alpha         float64   `json:"alpha,omitempty"`
+	Beta          float64   `json:"beta,omitempty"`
+	Lambda        float64   `json:"lambda,omitempty"` // EWMA decay
+	Persistence   float64   `json:"persistence"`      // α+β (λ for EWMA)
+	HalfLife      float64   `json:"half_life"`        // Bars for a variance shock to halve, 0 for EWMA
+	LongRunSigma  float64   `json:"long_run_sigma"`   // Per bar; sample deviation for EWMA
+	Sigma         float64   `json:"sigma"`            // Next bar conditional deviation
+	LogLikelihood float64   `json:"log_likelihood"`
+	Updated       time.Time `json:"updated"`
+}
+
+// NewService creates a volatility forecaster over the markets in storage
+func NewService(store *storage.MemoryStorage, config types.GARCHConfig) *Service {
+	return &Service{
+		storage: store,
+		config:  config,
+		models:  make(map[string]Model),
+		fitted:  make(map[string]time.Time),
+	}
+}
+
+// Refresh refits every active market
+func (s *Service) Refresh() {
+	for _, market := range s.storage.GetActiveMarkets() {
+		s.RefreshMarket(market)
+	}
+}
+
+// RefreshMarket refits one market from its archive. Markets with too few
+// bars keep their previous model.
+func (s *Service) RefreshMarket(market string) {
+	s.mu.Lock()
+	s.fitted[market] = time.Now()
+	s.mu.Unlock()
+
+	bar := time.Duration(s.config.BarSeconds) * time.Second
+	returns := barReturns(candles.TicksToCandles(s.storage.GetArchiveTicks(market), bar))
+	if len(returns) < minEWMABars {
+		return
+	}
+
+	model := Fit(returns, s.config)
+	model.Market = market
+	model.Updated = time.Now()
+
+	s.mu.Lock()
+	s.models[market] = model
+	s.mu.Unlock()
+}
+
+// Ensure fits a market that has no model yet. Markets whose archive was too
+// short are retried at most once per bar, since no new bar can complete
+// sooner, so callers on the request path don't rebuild bars every time.
+func (s *Service) Ensure(market string) {
+	s.mu.RLock()
+	_, ok := s.models[market]
+	last := s.fitted[market]
+	s.mu.RUnlock()
+
+	if ok || time.Since(last) < time.Duration(s.config.BarSeconds)*time.Second {
+		return
+	}
+	s.RefreshMarket(market)
+}
+
+// Model returns the fitted model of a market
+func (s *Service) Model(market string) (Model, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	model, ok := s.models[market]
+	return model, ok
+}
+
+// Forecast returns a market's volatility forecast over a duration in seconds
+func (s *Service) Forecast(market string, duration int) (types.VolatilityForecast, bool) {
+	model, ok := s.Model(market)
+	if !ok {
+		return types.VolatilityForecast{}, false
+	}
+	return model.Forecast(duration), true
+}
+
+// Fit estimates GARCH(1,1) by maximum likelihood with the long-run variance
+// targeted to the sample variance, and falls back to EWMA when there are
+// fewer than MinBars returns or the fit is integrated (α+β ≈ 1)
+func Fit(returns []float64, config types.GARCHConfig) Model {
+	returns = demean(returns)
+	variance := 0.0
+	for _, r := range returns {
+		variance += r * r
+	}
+	variance /= float64(len(returns))
+
+	model := Model{BarSeconds: config.BarSeconds, Bars: len(returns)}
+	if variance <= 0 {
+		model.Method = "ewma"
+		model.Lambda = config.EWMALambda
+		model.Persistence = config.EWMALambda
+		return model
+	}
+
+	if len(returns) >= config.MinBars {
+		alpha, beta, ll := maximize(returns, variance)
+		if alpha+beta < 0.999 {
+			model.Method = "garch"
+			model.Alpha = alpha
+			model.Beta = beta
+			model.Omega = variance * (1 - alpha - beta)
+			model.Persistence = alpha + beta
+			model.HalfLife = math.Log(0.5) / math.Log(model.Persistence)
+			model.LongRunSigma = math.Sqrt(variance)
+			model.Sigma = math.Sqrt(filter(returns, model.Omega, alpha, beta, variance))
+			model.LogLikelihood = ll
+			return model
+		}
+	}
+
+	// EWMA: σ²[t] = λ·σ²[t-1] + (1-λ)·r²[t-1]
+	lambda := config.EWMALambda
+	model.Method = "ewma"
+	model.Lambda = lambda
+	model.Persistence = lambda
+	model.LongRunSigma = math.Sqrt(variance)
+	model.Sigma = math.Sqrt(filter(returns, 0, 1-lambda, lambda, variance))
+	model.LogLikelihood = -0.5 * negLogLikelihood(returns, 0, 1-lambda, lambda, variance)
+	return model
+}
+
+// Forecast sums the per-bar variance forecasts over a duration in seconds.
+// GARCH variance k bars ahead is V + (α+β)^(k-1)·(σ²[t+1] - V); EWMA stays
+// flat. A partial last bar counts in proportion.
+func (m Model) Forecast(duration int) types.VolatilityForecast {
+	bars := float64(duration) / float64(m.BarSeconds)
+	next := m.Sigma * m.Sigma
+
+	variance := next * bars
+	if m.Method == "garch" {
+		longRun := m.LongRunSigma * m.LongRunSigma
+		variance = 0
+		step := 1.0
+		for k := 0.0; k < bars; k++ {
+			weight := math.Min(1, bars-k)
+			variance += weight * (longRun + step*(next-longRun))
+			step *= m.Persistence
+		}
+	}
+
+	sigma := math.Sqrt(variance)
+	forecast := types.VolatilityForecast{
+		Method:       m.Method,
+		Duration:     duration,
+		Bars:         bars,
+		Sigma:        sigma,
+		ExpectedMove: sigma * math.Sqrt(2/math.Pi),
+	}
+	if normal := m.LongRunSigma * math.Sqrt(bars); normal > 0 {
+		forecast.Ratio = sigma / normal
+	}
+	return forecast
+}
+
+// maximize grid-searches α and persistence α+β, then refines the best point
+// with a shrinking pattern search
+func maximize(returns []float64, variance float64) (float64, float64, float64) {
+	nll := func(alpha, persistence float64) float64 {
+		if alpha <= 0 || persistence <= alpha || persistence >= 0.9995 || alpha >= 1 {
+			return math.Inf(1)
+		}
+		beta := persistence - alpha
+		return negLogLikelihood(returns, variance*(1-persistence), alpha, beta, variance)
+	}
+
+	bestAlpha, bestPersistence, best := 0.0, 0.0, math.Inf(1)
+	for alpha := 0.02; alpha <= 0.30; alpha += 0.02 {
+		for _, persistence := range []float64{0.5, 0.6, 0.7, 0.8, 0.85, 0.9, 0.93, 0.95, 0.97, 0.98, 0.99, 0.995} {
+			if value := nll(alpha, persistence); value < best {
+				bestAlpha, bestPersistence, best = alpha, persistence, value
+			}
+		}
+	}
+
+	for step := 0.01; step > 1e-4; step /= 2 {
+		improved := true
+		for improved {
+			improved = false
+			for _, move := range [][2]float64{{step, 0}, {-step, 0}, {0, step}, {0, -step}} {
+				alpha, persistence := bestAlpha+move[0], bestPersistence+move[1]
+				if value := nll(alpha, persistence); value < best {
+					bestAlpha, bestPersistence, best = alpha, persistence, value
+					improved = true
+				}
+			}
+		}
+	}
+
+	if math.IsInf(best, 1) {
+		return 0, 1, math.Inf(-1)
+	}
+	return bestAlpha, bestPersistence - bestAlpha, -0.5 * best
+}
+
+// negLogLikelihood is twice the negative Gaussian log likelihood, dropping
+// the constant, with the recursion started at the sample variance
+func negLogLikelihood(returns []float64, omega, alpha, beta, variance float64) float64 {
+	sigma2 := variance
+	total := 0.0
+	for _, r := range returns {
+		if sigma2 <= 0 {
+			return math.Inf(1)
+		}
+		total += math.Log(sigma2) + r*r/sigma2
+		sigma2 = omega + alpha*r*r + beta*sigma2
+	}
+	return total
+}
+
+// filter runs the variance recursion over the returns and returns the
+// next-bar variance
+func filter(returns []float64, omega, alpha, beta, variance float64) float64 {
+	sigma2 := variance
+	for _, r := range returns {
+		sigma2 = omega + alpha*r*r + beta*sigma2
+	}
+	return sigma2
+}
+
+// barReturns returns the log returns between consecutive candle closes
+func barReturns(candleData []types.Candle) []float64 {
+	returns := make([]float64, 0, len(candleData))
+	for i := 1; i < len(candleData); i++ {
+		if candleData[i-1].Close > 0 && candleData[i].Close > 0 {
+			returns = append(returns, math.Log(candleData[i].Close/candleData[i-1].Close))
+		}
+	}
+	return returns
+}
+
+// demean subtracts the sample mean
+func demean(values []float64) []float64 {
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	out := make([]float64, len(values))
+	for i, v := range values {
+		out[i] = v - mean
+	}
+	return out
+}
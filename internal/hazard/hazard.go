@@ -0,0 +1,270 @@
+package hazard
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"otc-predictor/internal/candles"
+	"otc-predictor/internal/storage"
+	"otc-predictor/pkg/types"
+)
+
+// Service fits a spike hazard model per Crash/Boom index from the tick archive.
+// Spikes arrive as a Bernoulli trial on every tick, so the chance of at least
+// one spike within n ticks is 1-(1-p)^n regardless of the time since the last
+// one. The rate p blends the spikes seen in the archive with the nominal rate
+// in the index name (CRASH500 spikes once every 500 ticks on average).
+type Service struct {
+	storage    *storage.MemoryStorage
+	config     types.HazardConfig
+	marketType func(string) string
+	models     map[string]Model
+	mu         sync.RWMutex
+}
+
+// Model is the fitted hazard of one index
+type Model struct {
+	Market          string    `json:"market"`
+	Kind            string    `json:"kind"`             // "crash" or "boom"
+	NominalInterval float64   `json:"nominal_interval"` // Ticks between spikes from the name, 0 if unknown
+	Ticks           int       `json:"ticks"`            // Archive returns observed
+	Spikes          int       `json:"spikes"`           // Spikes detected in the archive
+	ObservedRate    float64   `json:"observed_rate"`    // Spikes per tick in the archive
+	SpikeRate       float64   `json:"spike_rate"`       // Posterior spikes per tick
+	MeanInterval    float64   `json:"mean_interval"`    // Expected ticks between spikes (1/SpikeRate)
+	TickSeconds     float64   `json:"tick_seconds"`     // Mean seconds between ticks, feed gaps excluded
+	Drift           float64   `json:"drift"`            // Mean log return per tick between spikes
+	DriftSigma      float64   `json:"drift_sigma"`      // Log return deviation per tick between spikes
+	MeanSpike       float64   `json:"mean_spike"`       // Mean log return of a spike tick
+	TicksSinceSpike int       `json:"ticks_since_spike"`
+	LastSpike       time.Time `json:"last_spike,omitempty"`
+	Updated         time.Time `json:"updated"`
+}
+
+// Forecast is the model's view of one contract duration
+type Forecast struct {
+	Duration         int     `json:"duration"` // Seconds
+	Ticks            int     `json:"ticks"`
+	SpikeProbability float64 `json:"spike_probability"` // At least one spike within the duration
+	ExpectedDrift    float64 `json:"expected_drift"`    // Log return if no spike occurs
+	ExpectedMove     float64 `json:"expected_move"`     // Log return including spikes
+	ProbabilityUp    float64 `json:"probability_up"`
+	ProbabilityDown  float64 `json:"probability_down"`
+}
+
+// NewService creates a hazard service. marketType maps a market symbol to
+// "forex", "volatility" or "crash_boom".
+func NewService(store *storage.MemoryStorage, config types.HazardConfig, marketType func(string) string) *Service {
+	return &Service{
+		storage:    store,
+		config:     config,
+		marketType: marketType,
+		models:     make(map[string]Model),
+	}
+}
+
+// Refresh refits the model of every active Crash/Boom index
+func (s *Service) Refresh() {
+	for _, market := range s.storage.GetActiveMarkets() {
+		if s.marketType(market) == "crash_boom" {
+			s.RefreshMarket(market)
+		}
+	}
+}
+
+// RefreshMarket refits one index from its archive. Markets with fewer than
+// MinTicks archived ticks keep their previous model.
+func (s *Service) RefreshMarket(market string) {
+	ticks := s.storage.GetArchiveTicks(market)
+	if len(ticks) < s.config.MinTicks || len(ticks) < 3 {
+		return
+	}
+
+	model := fit(market, ticks, s.config)
+
+	s.mu.Lock()
+	s.models[market] = model
+	s.mu.Unlock()
+}
+
+// Model returns the fitted model of a market
+func (s *Service) Model(market string) (Model, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	model, ok := s.models[market]
+	return model, ok
+}
+
+// Forecast applies the model to a contract duration in seconds. The price
+// rises only if no spike occurs (crash) or a spike does (boom) and the drift
+// between spikes beats its noise, modelled as a normal random walk.
+func (m Model) Forecast(duration int) Forecast {
+	n := int(math.Max(1, math.Round(float64(duration)/m.TickSeconds)))
+	noSpike := math.Pow(1-m.SpikeRate, float64(n))
+
+	forecast := Forecast{
+		Duration:         duration,
+		Ticks:            n,
+		SpikeProbability: 1 - noSpike,
+		ExpectedDrift:    float64(n) * m.Drift,
+		ExpectedMove:     float64(n) * (m.Drift + m.SpikeRate*m.MeanSpike),
+	}
+
+	// P(up | no spike) from the between-spike random walk
+	upWithoutSpike := 0.5
+	if m.DriftSigma > 0 {
+		upWithoutSpike = normalCDF(m.Drift * math.Sqrt(float64(n)) / m.DriftSigma)
+	}
+
+	if m.Kind == "crash" {
+		forecast.ProbabilityUp = noSpike * upWithoutSpike
+	} else {
+		forecast.ProbabilityUp = forecast.SpikeProbability + noSpike*upWithoutSpike
+	}
+	forecast.ProbabilityDown = 1 - forecast.ProbabilityUp
+
+	return forecast
+}
+
+// fit detects spikes as one-tick log returns beyond SpikeSigma robust
+// deviations in the index's spike direction and estimates the rate and the
+// drift between them. Returns across feed gaps are dropped: they hold an
+// unknown number of ticks, and the downtime isn't tick time.
+func fit(market string, ticks []types.Tick, config types.HazardConfig) Model {
+	model := Model{
+		Market:          market,
+		Kind:            Kind(market),
+		NominalInterval: NominalInterval(market),
+		Updated:         time.Now(),
+	}
+
+	inFeed, tickSeconds := candles.FeedSteps(ticks)
+	returns := make([]float64, 0, len(ticks)-1)
+	times := make([]time.Time, 0, len(ticks)-1) // Time of each return's closing tick
+	for i := 1; i < len(ticks); i++ {
+		if !inFeed[i] || ticks[i-1].Price <= 0 || ticks[i].Price <= 0 {
+			continue
+		}
+		returns = append(returns, math.Log(ticks[i].Price/ticks[i-1].Price))
+		times = append(times, ticks[i].Timestamp)
+	}
+	model.Ticks = len(returns)
+
+	model.TickSeconds = tickSeconds
+	if model.TickSeconds <= 0 {
+		model.TickSeconds = 1
+	}
+
+	center, scale := robustScale(returns)
+	threshold := config.SpikeSigma * scale
+
+	lastSpike := -1
+	var driftSum, driftSq, spikeSum float64
+	drifts := 0
+	for i, r := range returns {
+		deviation := r - center
+		if model.Kind == "crash" {
+			deviation = -deviation
+		}
+		if scale > 0 && deviation > threshold {
+			model.Spikes++
+			spikeSum += r
+			lastSpike = i
+			continue
+		}
+		driftSum += r
+		driftSq += r * r
+		drifts++
+	}
+
+	if drifts > 0 {
+		model.Drift = driftSum / float64(drifts)
+		model.DriftSigma = math.Sqrt(math.Max(0, driftSq/float64(drifts)-model.Drift*model.Drift))
+	}
+	if model.Spikes > 0 {
+		model.MeanSpike = spikeSum / float64(model.Spikes)
+	}
+	if lastSpike >= 0 {
+		model.TicksSinceSpike = len(returns) - lastSpike - 1
+		model.LastSpike = times[lastSpike]
+	} else {
+		model.TicksSinceSpike = len(returns)
+	}
+
+	model.ObservedRate = float64(model.Spikes) / float64(model.Ticks)
+	if model.NominalInterval > 0 {
+		// Prior of PriorSpikes spikes over PriorSpikes nominal intervals
+		model.SpikeRate = (float64(model.Spikes) + config.PriorSpikes) /
+			(float64(model.Ticks) + config.PriorSpikes*model.NominalInterval)
+	} else {
+		model.SpikeRate = (float64(model.Spikes) + 0.5) / (float64(model.Ticks) + 1)
+	}
+	model.MeanInterval = 1 / model.SpikeRate
+
+	return model
+}
+
+// robustScale returns the median and the MAD-based standard deviation of
+// values, which spikes barely move
+func robustScale(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	median := sorted[len(sorted)/2]
+
+	deviations := make([]float64, len(sorted))
+	for i, v := range sorted {
+		deviations[i] = math.Abs(v - median)
+	}
+	sort.Float64s(deviations)
+	return median, 1.4826 * deviations[len(deviations)/2]
+}
+
+// Kind returns "crash" or "boom" for an index symbol
+func Kind(market string) string {
+	if strings.Contains(strings.ToLower(market), "crash") {
+		return "crash"
+	}
+	return "boom"
+}
+
+// NominalInterval reads the average ticks between spikes from an index
+// symbol such as crash_500_1s or BOOM1000, or 0 if it has none
+func NominalInterval(market string) float64 {
+	m := strings.ToLower(market)
+	found := false
+	for _, name := range []string{"crash", "boom"} {
+		if i := strings.Index(m, name); i >= 0 {
+			m = m[i+len(name):]
+			found = true
+		}
+	}
+	if !found {
+		return 0
+	}
+
+	start := strings.IndexAny(m, "0123456789")
+	if start < 0 {
+		return 0
+	}
+	end := start
+	for end < len(m) && m[end] >= '0' && m[end] <= '9' {
+		end++
+	}
+	interval, err := strconv.Atoi(m[start:end])
+	if err != nil {
+		return 0
+	}
+	return float64(interval)
+}
+
+func normalCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
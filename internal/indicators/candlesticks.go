@@ -0,0 +1,261 @@
+package indicators
+
+import (
+	"math"
+	"otc-predictor/pkg/types"
+)
+
+// CandlestickType represents a Japanese candlestick pattern
+type CandlestickType string
+
+const (
+	BullishEngulfing   CandlestickType = "bullish_engulfing"
+	BearishEngulfing   CandlestickType = "bearish_engulfing"
+	Hammer             CandlestickType = "hammer"
+	HangingMan         CandlestickType = "hanging_man"
+	InvertedHammer     CandlestickType = "inverted_hammer"
+	ShootingStar       CandlestickType = "shooting_star"
+	BullishPinBar      CandlestickType = "bullish_pin_bar"
+	BearishPinBar      CandlestickType = "bearish_pin_bar"
+	Doji               CandlestickType = "doji"
+	DragonflyDoji      CandlestickType = "dragonfly_doji"
+	GravestoneDoji     CandlestickType = "gravestone_doji"
+	MorningStar        CandlestickType = "morning_star"
+	EveningStar        CandlestickType = "evening_star"
+	ThreeWhiteSoldiers CandlestickType = "three_white_soldiers"
+	ThreeBlackCrows    CandlestickType = "three_black_crows"
+	BullishHarami      CandlestickType = "bullish_harami"
+	BearishHarami      CandlestickType = "bearish_harami"
+	PiercingLine       CandlestickType = "piercing_line"
+	DarkCloudCover     CandlestickType = "dark_cloud_cover"
+	TweezerBottom      CandlestickType = "tweezer_bottom"
+	TweezerTop         CandlestickType = "tweezer_top"
+	InsideBar          CandlestickType = "inside_bar"
+	BullishOutsideBar  CandlestickType = "bullish_outside_bar"
+	BearishOutsideBar  CandlestickType = "bearish_outside_bar"
+)
+
+// CandlestickPattern holds a detected candlestick pattern on the latest bars
+type CandlestickPattern struct {
+	Type       CandlestickType
+	Direction  string  // "UP", "DOWN" or "NONE" for indecision patterns
+	Confidence float64 // 0.55-0.66 before context
+	Strength   float64 // 0-1, pattern quality
+	Bars       int     // Number of candles forming the pattern
+}
+
+// candleShape holds derived measurements of a single candle
+type candleShape struct {
+	body        float64
+	rng         float64
+	upperShadow float64
+	lowerShadow float64
+	bullish     bool
+	bearish     bool
+}
+
+func shapeOf(c types.Candle) candleShape {
+	return candleShape{
+		body:        math.Abs(c.Close - c.Open),
+		rng:         c.High - c.Low,
+		upperShadow: c.High - math.Max(c.Open, c.Close),
+		lowerShadow: math.Min(c.Open, c.Close) - c.Low,
+		bullish:     c.Close > c.Open,
+		bearish:     c.Close < c.Open,
+	}
+}
+
+// DetectCandlestickPatterns detects candlestick patterns ending on the last candle.
+// Bodies and ranges are judged against the average of the preceding candles so
+// that tiny, low-tick candles do not produce patterns.
+func DetectCandlestickPatterns(candles []types.Candle) []CandlestickPattern {
+	patterns := []CandlestickPattern{}
+	if len(candles) < 8 {
+		return patterns
+	}
+
+	n := len(candles)
+	c, p, pp := candles[n-1], candles[n-2], candles[n-3]
+	cs, ps, pps := shapeOf(c), shapeOf(p), shapeOf(pp)
+	avgBody, avgRange := averageBodyRange(candles[:n-1], 10)
+
+	// Ignore dead candles (no price movement relative to recent bars)
+	if avgRange == 0 || cs.rng < avgRange*0.5 {
+		return patterns
+	}
+
+	trend := priorTrend(candles[:n-1], 5)
+
+	// --- Single-candle patterns ---
+	hammerShape := cs.body > cs.rng*0.1 && cs.lowerShadow >= cs.body*2 && cs.upperShadow <= cs.rng*0.15
+	invertedShape := cs.body > cs.rng*0.1 && cs.upperShadow >= cs.body*2 && cs.lowerShadow <= cs.rng*0.15
+	wickStrength := func(wick float64) float64 {
+		return math.Min(1.0, wick/cs.rng)
+	}
+
+	switch {
+	case hammerShape && trend < 0:
+		patterns = append(patterns, newCandlestick(Hammer, "UP", 0.62, wickStrength(cs.lowerShadow), 1))
+	case hammerShape && trend > 0:
+		patterns = append(patterns, newCandlestick(HangingMan, "DOWN", 0.58, wickStrength(cs.lowerShadow), 1))
+	case invertedShape && trend > 0:
+		patterns = append(patterns, newCandlestick(ShootingStar, "DOWN", 0.62, wickStrength(cs.upperShadow), 1))
+	case invertedShape && trend < 0:
+		patterns = append(patterns, newCandlestick(InvertedHammer, "UP", 0.58, wickStrength(cs.upperShadow), 1))
+	}
+
+	// Pin bars: dominant wick protruding beyond the previous candle
+	if !hammerShape && cs.lowerShadow >= cs.rng*0.66 && c.Low < p.Low {
+		patterns = append(patterns, newCandlestick(BullishPinBar, "UP", 0.63, wickStrength(cs.lowerShadow), 1))
+	}
+	if !invertedShape && cs.upperShadow >= cs.rng*0.66 && c.High > p.High {
+		patterns = append(patterns, newCandlestick(BearishPinBar, "DOWN", 0.63, wickStrength(cs.upperShadow), 1))
+	}
+
+	// Doji family (a pin bar already covers the one-sided dojis)
+	if cs.body <= cs.rng*0.1 {
+		switch {
+		case hasCandlestick(patterns, BullishPinBar) || hasCandlestick(patterns, BearishPinBar):
+			// Already reported as a pin bar
+		case cs.lowerShadow >= cs.rng*0.6 && cs.upperShadow <= cs.rng*0.1:
+			patterns = append(patterns, newCandlestick(DragonflyDoji, "UP", 0.60, wickStrength(cs.lowerShadow), 1))
+		case cs.upperShadow >= cs.rng*0.6 && cs.lowerShadow <= cs.rng*0.1:
+			patterns = append(patterns, newCandlestick(GravestoneDoji, "DOWN", 0.60, wickStrength(cs.upperShadow), 1))
+		default:
+			patterns = append(patterns, newCandlestick(Doji, "NONE", 0.55, 1-cs.body/cs.rng, 1))
+		}
+	}
+
+	// --- Two-candle patterns ---
+	if ps.bearish && cs.bullish && c.Open <= p.Close && c.Close >= p.Open && cs.body > ps.body {
+		patterns = append(patterns, newCandlestick(BullishEngulfing, "UP", 0.64, math.Min(1.0, cs.body/(ps.body*2)), 2))
+	}
+	if ps.bullish && cs.bearish && c.Open >= p.Close && c.Close <= p.Open && cs.body > ps.body {
+		patterns = append(patterns, newCandlestick(BearishEngulfing, "DOWN", 0.64, math.Min(1.0, cs.body/(ps.body*2)), 2))
+	}
+
+	bodyInside := math.Max(c.Open, c.Close) < math.Max(p.Open, p.Close) &&
+		math.Min(c.Open, c.Close) > math.Min(p.Open, p.Close)
+	if ps.body >= avgBody && bodyInside && cs.body < ps.body*0.5 {
+		if ps.bearish && cs.bullish && trend < 0 {
+			patterns = append(patterns, newCandlestick(BullishHarami, "UP", 0.60, 1-cs.body/ps.body, 2))
+		}
+		if ps.bullish && cs.bearish && trend > 0 {
+			patterns = append(patterns, newCandlestick(BearishHarami, "DOWN", 0.60, 1-cs.body/ps.body, 2))
+		}
+	}
+
+	prevMid := (p.Open + p.Close) / 2
+	if ps.bearish && ps.body >= avgBody && cs.bullish && c.Open < p.Close && c.Close > prevMid && c.Close < p.Open {
+		patterns = append(patterns, newCandlestick(PiercingLine, "UP", 0.62, (c.Close-prevMid)/(p.Open-prevMid), 2))
+	}
+	if ps.bullish && ps.body >= avgBody && cs.bearish && c.Open > p.Close && c.Close < prevMid && c.Close > p.Open {
+		patterns = append(patterns, newCandlestick(DarkCloudCover, "DOWN", 0.62, (prevMid-c.Close)/(prevMid-p.Open), 2))
+	}
+
+	tolerance := avgRange * 0.05
+	if ps.bearish && cs.bullish && trend < 0 && math.Abs(c.Low-p.Low) <= tolerance {
+		patterns = append(patterns, newCandlestick(TweezerBottom, "UP", 0.60, 0.7, 2))
+	}
+	if ps.bullish && cs.bearish && trend > 0 && math.Abs(c.High-p.High) <= tolerance {
+		patterns = append(patterns, newCandlestick(TweezerTop, "DOWN", 0.60, 0.7, 2))
+	}
+
+	if c.High < p.High && c.Low > p.Low {
+		patterns = append(patterns, newCandlestick(InsideBar, "NONE", 0.55, 1-cs.rng/ps.rng, 2))
+	}
+	engulfing := hasCandlestick(patterns, BullishEngulfing) || hasCandlestick(patterns, BearishEngulfing)
+	if !engulfing && c.High > p.High && c.Low < p.Low && cs.body >= cs.rng*0.5 {
+		if cs.bullish {
+			patterns = append(patterns, newCandlestick(BullishOutsideBar, "UP", 0.61, cs.body/cs.rng, 2))
+		} else if cs.bearish {
+			patterns = append(patterns, newCandlestick(BearishOutsideBar, "DOWN", 0.61, cs.body/cs.rng, 2))
+		}
+	}
+
+	// --- Three-candle patterns ---
+	ppMid := (pp.Open + pp.Close) / 2
+	if pps.bearish && pps.body >= avgBody && ps.body <= pps.body*0.3 &&
+		cs.bullish && c.Close > ppMid {
+		patterns = append(patterns, newCandlestick(MorningStar, "UP", 0.66, math.Min(1.0, (c.Close-ppMid)/pps.body+0.5), 3))
+	}
+	if pps.bullish && pps.body >= avgBody && ps.body <= pps.body*0.3 &&
+		cs.bearish && c.Close < ppMid {
+		patterns = append(patterns, newCandlestick(EveningStar, "DOWN", 0.66, math.Min(1.0, (ppMid-c.Close)/pps.body+0.5), 3))
+	}
+
+	if pps.bullish && ps.bullish && cs.bullish &&
+		p.Close > pp.Close && c.Close > p.Close &&
+		p.Open >= pp.Open && p.Open <= pp.Close && c.Open >= p.Open && c.Open <= p.Close &&
+		cs.upperShadow <= cs.body*0.3 && ps.body >= avgBody*0.8 && cs.body >= avgBody*0.8 {
+		patterns = append(patterns, newCandlestick(ThreeWhiteSoldiers, "UP", 0.64, math.Min(1.0, (cs.body+ps.body+pps.body)/(avgBody*4.5)), 3))
+	}
+	if pps.bearish && ps.bearish && cs.bearish &&
+		p.Close < pp.Close && c.Close < p.Close &&
+		p.Open <= pp.Open && p.Open >= pp.Close && c.Open <= p.Open && c.Open >= p.Close &&
+		cs.lowerShadow <= cs.body*0.3 && ps.body >= avgBody*0.8 && cs.body >= avgBody*0.8 {
+		patterns = append(patterns, newCandlestick(ThreeBlackCrows, "DOWN", 0.64, math.Min(1.0, (cs.body+ps.body+pps.body)/(avgBody*4.5)), 3))
+	}
+
+	return patterns
+}
+
+// newCandlestick builds a pattern with confidence scaled by strength
+func newCandlestick(t CandlestickType, direction string, baseConfidence, strength float64, bars int) CandlestickPattern {
+	strength = math.Max(0, math.Min(1.0, strength))
+	return CandlestickPattern{
+		Type:       t,
+		Direction:  direction,
+		Confidence: baseConfidence + strength*0.02,
+		Strength:   strength,
+		Bars:       bars,
+	}
+}
+
+// hasCandlestick reports whether a pattern type was already detected
+func hasCandlestick(patterns []CandlestickPattern, t CandlestickType) bool {
+	for _, p := range patterns {
+		if p.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+// averageBodyRange returns the mean body and range of the last n candles
+func averageBodyRange(candles []types.Candle, n int) (float64, float64) {
+	if len(candles) < n {
+		n = len(candles)
+	}
+	if n == 0 {
+		return 0, 0
+	}
+
+	bodies, ranges := 0.0, 0.0
+	for _, c := range candles[len(candles)-n:] {
+		bodies += math.Abs(c.Close - c.Open)
+		ranges += c.High - c.Low
+	}
+	return bodies / float64(n), ranges / float64(n)
+}
+
+// priorTrend returns 1 (up), -1 (down) or 0 from closes over the last n candles
+func priorTrend(candles []types.Candle, n int) int {
+	if len(candles) <= n {
+		return 0
+	}
+
+	first := candles[len(candles)-n-1].Close
+	last := candles[len(candles)-1].Close
+	_, avgRange := averageBodyRange(candles, n)
+
+	// Require a move of at least one average candle range
+	switch {
+	case last-first > avgRange:
+		return 1
+	case first-last > avgRange:
+		return -1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,150 @@
+package indicators
+
+import (
+	"testing"
+	"time"
+
+	"otc-predictor/pkg/types"
+)
+
+// bar builds a candle from OHLC
+func bar(open, high, low, close float64) types.Candle {
+	return types.Candle{Open: open, High: high, Low: low, Close: close, Volume: 1}
+}
+
+// downtrendBars: ten bearish candles stepping 0.6 down from 100; the last one
+// is open 94.6, high 94.8, low 93.8, close 94.0 (body 0.6, range 1.0)
+func downtrendBars() []types.Candle {
+	bars := []types.Candle{}
+	for i := 0; i < 10; i++ {
+		open := 100 - 0.6*float64(i)
+		bars = append(bars, bar(open, open+0.2, open-0.8, open-0.6))
+	}
+	return bars
+}
+
+// withTimestamps numbers candles one minute apart
+func withTimestamps(bars []types.Candle) []types.Candle {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range bars {
+		bars[i].Market = "fixture"
+		bars[i].Timestamp = start.Add(time.Duration(i) * time.Minute)
+	}
+	return bars
+}
+
+// mirrorBars reflects candles around a level, turning bullish setups bearish
+func mirrorBars(bars []types.Candle, level float64) []types.Candle {
+	out := make([]types.Candle, len(bars))
+	for i, b := range bars {
+		out[i] = b
+		out[i].Open = level - b.Open
+		out[i].Close = level - b.Close
+		out[i].High = level - b.Low
+		out[i].Low = level - b.High
+	}
+	return out
+}
+
+func TestDetectCandlestickPatterns(t *testing.T) {
+	// Each fixture follows downtrendBars; the mirrored series (an uptrend)
+	// must produce the bearish counterpart
+	tests := []struct {
+		name     string
+		bars     []types.Candle
+		want     CandlestickType
+		mirrored CandlestickType
+	}{
+		{"hammer", []types.Candle{bar(94, 94.2, 93.2, 94.2)}, Hammer, ShootingStar},
+		{"inverted hammer", []types.Candle{bar(94, 95, 94, 94.2)}, InvertedHammer, HangingMan},
+		{"pin bar", []types.Candle{bar(94.05, 94.15, 93, 94.1)}, BullishPinBar, BearishPinBar},
+		{"dragonfly doji", []types.Candle{bar(94.7, 94.75, 93.85, 94.72)}, DragonflyDoji, GravestoneDoji},
+		{"doji", []types.Candle{bar(94, 94.5, 93.5, 94.02)}, Doji, Doji},
+		{"engulfing", []types.Candle{bar(93.9, 94.9, 93.85, 94.8)}, BullishEngulfing, BearishEngulfing},
+		{"harami", []types.Candle{bar(94.2, 94.55, 93.95, 94.4)}, BullishHarami, BearishHarami},
+		{"inside bar", []types.Candle{bar(94.2, 94.55, 93.95, 94.4)}, InsideBar, InsideBar},
+		{"piercing line", []types.Candle{bar(93.9, 94.45, 93.85, 94.4)}, PiercingLine, DarkCloudCover},
+		{"tweezer", []types.Candle{bar(94, 94.4, 93.8, 94.3)}, TweezerBottom, TweezerTop},
+		{"outside bar", []types.Candle{bar(94.1, 95.1, 93.7, 95)}, BullishOutsideBar, BearishOutsideBar},
+		{"morning star", []types.Candle{
+			bar(93.9, 94, 93.6, 93.85),
+			bar(93.9, 94.6, 93.85, 94.5),
+		}, MorningStar, EveningStar},
+		{"three soldiers", []types.Candle{
+			bar(94, 94.8, 93.9, 94.7),
+			bar(94.4, 95.3, 94.3, 95.2),
+			bar(94.9, 95.8, 94.8, 95.7),
+		}, ThreeWhiteSoldiers, ThreeBlackCrows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series := withTimestamps(append(downtrendBars(), tt.bars...))
+
+			assertCandlestick(t, DetectCandlestickPatterns(series), tt.want)
+			assertCandlestick(t, DetectCandlestickPatterns(mirrorBars(series, 200)), tt.mirrored)
+		})
+	}
+}
+
+func TestDetectCandlestickPatternsDedup(t *testing.T) {
+	// A pin bar with a doji body is reported once, as the pin bar
+	pin := withTimestamps(append(downtrendBars(), bar(94.05, 94.15, 93, 94.1)))
+	if hasCandlestick(DetectCandlestickPatterns(pin), Doji) || hasCandlestick(DetectCandlestickPatterns(pin), DragonflyDoji) {
+		t.Error("pin bar also reported as a doji")
+	}
+
+	// An engulfing candle is not also an outside bar
+	engulfing := withTimestamps(append(downtrendBars(), bar(93.9, 94.9, 93.7, 94.85)))
+	patterns := DetectCandlestickPatterns(engulfing)
+	if !hasCandlestick(patterns, BullishEngulfing) || hasCandlestick(patterns, BullishOutsideBar) {
+		t.Errorf("patterns = %v, want engulfing without outside bar", patternTypes(patterns))
+	}
+}
+
+func TestDetectCandlestickPatternsGuards(t *testing.T) {
+	if got := DetectCandlestickPatterns(withTimestamps(downtrendBars()[:7])); len(got) != 0 {
+		t.Errorf("under 8 candles: got %v", patternTypes(got))
+	}
+
+	// A tiny last candle (under half the average range) is ignored
+	dead := withTimestamps(append(downtrendBars(), bar(94, 94.1, 93.8, 94.08)))
+	if got := DetectCandlestickPatterns(dead); len(got) != 0 {
+		t.Errorf("dead candle: got %v", patternTypes(got))
+	}
+
+	// Hammer shape without a prior downtrend is not a hammer
+	flat := []types.Candle{}
+	for i := 0; i < 10; i++ {
+		flat = append(flat, bar(94, 94.5, 93.5, 94))
+	}
+	got := DetectCandlestickPatterns(withTimestamps(append(flat, bar(94, 94.2, 93.2, 94.2))))
+	if hasCandlestick(got, Hammer) || hasCandlestick(got, HangingMan) {
+		t.Errorf("no trend: got %v", patternTypes(got))
+	}
+}
+
+func assertCandlestick(t *testing.T, patterns []CandlestickPattern, want CandlestickType) {
+	t.Helper()
+	for _, p := range patterns {
+		if p.Type != want {
+			continue
+		}
+		if p.Confidence < 0.55 || p.Confidence > 0.68 {
+			t.Errorf("%s confidence = %.3f, want 0.55-0.68", p.Type, p.Confidence)
+		}
+		if p.Strength < 0 || p.Strength > 1 {
+			t.Errorf("%s strength = %.3f, want 0-1", p.Type, p.Strength)
+		}
+		return
+	}
+	t.Errorf("patterns = %v, want %s", patternTypes(patterns), want)
+}
+
+func patternTypes(patterns []CandlestickPattern) []CandlestickType {
+	out := make([]CandlestickType, len(patterns))
+	for i, p := range patterns {
+		out[i] = p.Type
+	}
+	return out
+}
@@ -0,0 +1,243 @@
+package indicators
+
+import (
+	"math"
+	"otc-predictor/pkg/types"
+)
+
+// Channel holds upper, middle and lower bands of a price channel
+type Channel struct {
+	Upper  float64
+	Middle float64
+	Lower  float64
+}
+
+// CalculateATR calculates Average True Range with Wilder smoothing
+func CalculateATR(candles []types.Candle, period int) float64 {
+	candles = finiteCandles(candles)
+	if len(candles) < 2 || period < 1 {
+		return 0
+	}
+
+	trueRanges := make([]float64, 0, len(candles)-1)
+	for i := 1; i < len(candles); i++ {
+		trueRanges = append(trueRanges, trueRange(candles[i], candles[i-1].Close))
+	}
+
+	if len(trueRanges) < period {
+		return mean(trueRanges)
+	}
+
+	atr := mean(trueRanges[:period])
+	for i := period; i < len(trueRanges); i++ {
+		atr = (atr*float64(period-1) + trueRanges[i]) / float64(period)
+	}
+
+	return atr
+}
+
+// CalculateKeltnerChannel calculates Keltner channels (EMA +/- ATR multiple)
+func CalculateKeltnerChannel(candles []types.Candle, emaPeriod, atrPeriod int, multiplier float64) Channel {
+	if len(candles) == 0 {
+		return Channel{}
+	}
+
+	closes := make([]float64, len(candles))
+	for i, c := range candles {
+		closes[i] = c.Close
+	}
+	ema := emaSeries(closes, emaPeriod)
+	middle := ema[len(ema)-1]
+	atr := CalculateATR(candles, atrPeriod)
+
+	return Channel{
+		Upper:  middle + atr*multiplier,
+		Middle: middle,
+		Lower:  middle - atr*multiplier,
+	}
+}
+
+// CalculateDonchianChannel calculates the highest high / lowest low channel
+func CalculateDonchianChannel(candles []types.Candle, period int) Channel {
+	if len(candles) == 0 {
+		return Channel{}
+	}
+
+	if len(candles) < period {
+		period = len(candles)
+	}
+
+	highest, lowest := highLow(candles[len(candles)-period:])
+
+	return Channel{
+		Upper:  highest,
+		Middle: (highest + lowest) / 2,
+		Lower:  lowest,
+	}
+}
+
+// CalculateTickVWAP calculates VWAP using tick count (candle volume) as weight.
+// Deriv feeds carry no traded volume, so ticks per candle stand in for it.
+func CalculateTickVWAP(candles []types.Candle, period int) float64 {
+	if len(candles) == 0 {
+		return 0
+	}
+
+	if len(candles) < period || period < 1 {
+		period = len(candles)
+	}
+
+	weighted := 0.0
+	totalVolume := 0.0
+	for _, c := range candles[len(candles)-period:] {
+		volume := c.Volume
+		if volume <= 0 {
+			volume = 1
+		}
+		weighted += (c.High + c.Low + c.Close) / 3 * volume
+		totalVolume += volume
+	}
+
+	return weighted / totalVolume
+}
+
+// ParabolicSAR holds stop-and-reverse level and current trend
+type ParabolicSAR struct {
+	SAR   float64
+	Trend int // 1 = uptrend, -1 = downtrend
+}
+
+// CalculateParabolicSAR calculates Wilder's Parabolic SAR
+func CalculateParabolicSAR(candles []types.Candle, step, maxStep float64) ParabolicSAR {
+	candles = finiteCandles(candles)
+	if len(candles) < 2 {
+		if len(candles) == 1 {
+			return ParabolicSAR{SAR: candles[0].Low, Trend: 1}
+		}
+		return ParabolicSAR{}
+	}
+
+	trend := 1
+	if candles[1].Close < candles[0].Close {
+		trend = -1
+	}
+
+	sar := candles[0].Low
+	extreme := candles[0].High
+	if trend == -1 {
+		sar = candles[0].High
+		extreme = candles[0].Low
+	}
+	af := step
+
+	for i := 1; i < len(candles); i++ {
+		c := candles[i]
+		sar += af * (extreme - sar)
+
+		if trend == 1 {
+			// SAR may not enter the prior two bars' range
+			sar = math.Min(sar, candles[i-1].Low)
+			if i >= 2 {
+				sar = math.Min(sar, candles[i-2].Low)
+			}
+
+			if c.Low < sar {
+				trend = -1
+				sar = extreme
+				extreme = c.Low
+				af = step
+				continue
+			}
+
+			if c.High > extreme {
+				extreme = c.High
+				af = math.Min(af+step, maxStep)
+			}
+		} else {
+			sar = math.Max(sar, candles[i-1].High)
+			if i >= 2 {
+				sar = math.Max(sar, candles[i-2].High)
+			}
+
+			if c.High > sar {
+				trend = 1
+				sar = extreme
+				extreme = c.High
+				af = step
+				continue
+			}
+
+			if c.Low < extreme {
+				extreme = c.Low
+				af = math.Min(af+step, maxStep)
+			}
+		}
+	}
+
+	return ParabolicSAR{SAR: sar, Trend: trend}
+}
+
+// Ichimoku holds Ichimoku Kinko Hyo lines
+type Ichimoku struct {
+	Tenkan  float64 // Conversion line
+	Kijun   float64 // Base line
+	SenkouA float64 // Leading span A (cloud currently in effect)
+	SenkouB float64 // Leading span B (cloud currently in effect)
+	Chikou  float64 // Lagging span (current close)
+}
+
+// CalculateIchimoku calculates Ichimoku lines.
+// Senkou spans are taken from displacement bars ago so they describe the
+// cloud under the current bar; with short history the current values are used.
+func CalculateIchimoku(candles []types.Candle, tenkanPeriod, kijunPeriod, senkouBPeriod, displacement int) Ichimoku {
+	if len(candles) == 0 {
+		return Ichimoku{}
+	}
+
+	tenkan := midpoint(candles, tenkanPeriod)
+	kijun := midpoint(candles, kijunPeriod)
+
+	cloudBase := candles
+	if len(candles) > displacement+kijunPeriod {
+		cloudBase = candles[:len(candles)-displacement]
+	}
+
+	return Ichimoku{
+		Tenkan:  tenkan,
+		Kijun:   kijun,
+		SenkouA: (midpoint(cloudBase, tenkanPeriod) + midpoint(cloudBase, kijunPeriod)) / 2,
+		SenkouB: midpoint(cloudBase, senkouBPeriod),
+		Chikou:  candles[len(candles)-1].Close,
+	}
+}
+
+// finiteCandles drops candles with NaN or infinite prices so one bad print
+// does not poison recursive indicators (ATR, SAR) for the rest of the series
+func finiteCandles(candles []types.Candle) []types.Candle {
+	for i, c := range candles {
+		if !isFinite(c.High) || !isFinite(c.Low) || !isFinite(c.Close) {
+			clean := append([]types.Candle{}, candles[:i]...)
+			for _, c := range candles[i+1:] {
+				if isFinite(c.High) && isFinite(c.Low) && isFinite(c.Close) {
+					clean = append(clean, c)
+				}
+			}
+			return clean
+		}
+	}
+	return candles
+}
+
+// trueRange returns the true range of a candle given the previous close
+func trueRange(c types.Candle, prevClose float64) float64 {
+	return math.Max(c.High-c.Low, math.Max(math.Abs(c.High-prevClose), math.Abs(c.Low-prevClose)))
+}
+
+// midpoint returns (highest high + lowest low) / 2 over the last period candles
+func midpoint(candles []types.Candle, period int) float64 {
+	if len(candles) < period {
+		period = len(candles)
+	}
+	highest, lowest := highLow(candles[len(candles)-period:])
+	return (highest + lowest) / 2
+}
@@ -0,0 +1,108 @@
+package indicators
+
+import (
+	"testing"
+
+	"otc-predictor/pkg/types"
+)
+
+// wiggle knocks every other price down by amp so RSI sees both gains and losses
+func wiggle(prices []float64, amp float64) []float64 {
+	out := append([]float64{}, prices...)
+	for i := range out {
+		if i%2 == 1 {
+			out[i] -= amp
+		}
+	}
+	return out
+}
+
+// bearishDivergenceFixture: a sharp rally to 102, a pullback, then a slower
+// grind to a marginally higher high at 102.3 on weaker RSI
+func bearishDivergenceFixture() []float64 {
+	return wiggle(path(
+		[2]float64{0, 100}, [2]float64{20, 100.2}, [2]float64{30, 102}, [2]float64{37, 100.8},
+		[2]float64{50, 102.3}, [2]float64{60, 101.6},
+	), 0.12)
+}
+
+// confirmedHighFixture: the second rally is steeper, so RSI confirms the new high
+func confirmedHighFixture() []float64 {
+	return wiggle(path(
+		[2]float64{0, 100}, [2]float64{20, 100.2}, [2]float64{30, 101}, [2]float64{37, 100.6},
+		[2]float64{44, 102.4}, [2]float64{60, 101.6},
+	), 0.12)
+}
+
+func TestFindPivots(t *testing.T) {
+	pivots := findPivots(ticksFromPrices(bearishDivergenceFixture()), 15, 40)
+
+	wantHighs := []PricePivot{{Index: 30, Price: 102}, {Index: 50, Price: 102.3}}
+	if len(pivots.Highs) != len(wantHighs) {
+		t.Fatalf("highs = %+v, want %+v", pivots.Highs, wantHighs)
+	}
+	for i, want := range wantHighs {
+		if pivots.Highs[i].Index != want.Index {
+			t.Errorf("high %d index = %d, want %d", i, pivots.Highs[i].Index, want.Index)
+		}
+		assertClose(t, "high price", pivots.Highs[i].Price, want.Price)
+	}
+
+	if len(pivots.Lows) != 1 || pivots.Lows[0].Index != 37 {
+		t.Errorf("lows = %+v, want one low at index 37", pivots.Lows)
+	}
+
+	if short := findPivots(ticksFromPrices(bearishDivergenceFixture()[:18]), 15, 40); len(short.Highs)+len(short.Lows) != 0 {
+		t.Errorf("short input produced pivots: %+v", short)
+	}
+}
+
+func TestFindRSIPivotsSkipsWarmup(t *testing.T) {
+	// Zeros mark bars before RSI is defined and must never become pivots
+	values := make([]float64, 40)
+	for i := 14; i < len(values); i++ {
+		values[i] = 50
+	}
+	values[25] = 80
+	values[32] = 20
+
+	pivots := findRSIPivots(values, 15, 40)
+	if len(pivots.Highs) != 1 || pivots.Highs[0].Index != 25 {
+		t.Errorf("highs = %+v, want one high at 25", pivots.Highs)
+	}
+	if len(pivots.Lows) != 1 || pivots.Lows[0].Index != 32 {
+		t.Errorf("lows = %+v, want one low at 32", pivots.Lows)
+	}
+}
+
+func TestDetectRSIDivergence(t *testing.T) {
+	config := types.StrategyConfig{RSIPeriod: 14}
+	tests := []struct {
+		name   string
+		prices []float64
+		want   DivergenceType
+	}{
+		{"bearish", bearishDivergenceFixture(), BearishDivergence},
+		{"bullish", mirror(bearishDivergenceFixture(), 200), BullishDivergence},
+		{"confirmed high", confirmedHighFixture(), NoDivergence},
+		{"too short", bearishDivergenceFixture()[:30], NoDivergence},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectRSIDivergence(ticksFromPrices(tt.prices), config)
+			if got.Type != tt.want {
+				t.Fatalf("type = %s, want %s", got.Type, tt.want)
+			}
+			if tt.want == NoDivergence {
+				return
+			}
+			if got.Confidence < 0.72 || got.Confidence > 0.82 {
+				t.Errorf("confidence = %.3f, want 0.72-0.82", got.Confidence)
+			}
+			if got.Lookback != 20 {
+				t.Errorf("lookback = %d, want 20", got.Lookback)
+			}
+		})
+	}
+}
@@ -0,0 +1,389 @@
+package indicators
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"otc-predictor/pkg/types"
+)
+
+// Reference values live in testdata/golden.json and are produced by
+// testdata/golden_gen.py, an independent implementation of each formula.
+// Regenerate with: cd testdata && python3 golden_gen.py > golden.json
+// Published values copied verbatim live in testdata/stockcharts_rsi.csv;
+// EMA and ATR are also checked against closed forms of their recursions.
+
+type goldenCase struct {
+	N      int                `json:"n"`
+	Values map[string]float64 `json:"values"`
+}
+
+type goldenCandle struct {
+	Open   float64 `json:"open"`
+	High   float64 `json:"high"`
+	Low    float64 `json:"low"`
+	Close  float64 `json:"close"`
+	Volume float64 `json:"volume"`
+}
+
+type goldenFile struct {
+	WilderRSI struct {
+		Closes []float64    `json:"closes"`
+		Cases  []goldenCase `json:"cases"`
+	} `json:"wilder_rsi"`
+	OHLC struct {
+		Candles []goldenCandle `json:"candles"`
+		Cases   []goldenCase   `json:"cases"`
+	} `json:"ohlc"`
+}
+
+// goldenFuncs maps each golden.json key to the package function under test
+var goldenFuncs = map[string]func([]types.Candle) float64{
+	"rsi_14":  func(c []types.Candle) float64 { return CalculateRSI(candleTicks(c), 14) },
+	"ema_9":   func(c []types.Candle) float64 { return CalculateEMA(candleTicks(c), 9) },
+	"ema_21":  func(c []types.Candle) float64 { return CalculateEMA(candleTicks(c), 21) },
+	"ema_50":  func(c []types.Candle) float64 { return CalculateEMA(candleTicks(c), 50) },
+	"sma_10":  func(c []types.Candle) float64 { return CalculateSMA(candleTicks(c), 10) },
+	"roc_12":  func(c []types.Candle) float64 { return CalculateROC(candleTicks(c), 12) },
+	"cci_20":  func(c []types.Candle) float64 { return CalculateCCI(c, 20) },
+	"atr_14":  func(c []types.Candle) float64 { return CalculateATR(c, 14) },
+	"vwap_20": func(c []types.Candle) float64 { return CalculateTickVWAP(c, 20) },
+
+	"bb_upper_20_2":  func(c []types.Candle) float64 { return CalculateBollingerBands(candleTicks(c), 20, 2).Upper },
+	"bb_middle_20_2": func(c []types.Candle) float64 { return CalculateBollingerBands(candleTicks(c), 20, 2).Middle },
+	"bb_lower_20_2":  func(c []types.Candle) float64 { return CalculateBollingerBands(candleTicks(c), 20, 2).Lower },
+	"bb_position_20_2": func(c []types.Candle) float64 {
+		return CalculateBBPosition(lastClose(c), CalculateBollingerBands(candleTicks(c), 20, 2))
+	},
+	"volatility_20": func(c []types.Candle) float64 { return CalculateVolatility(candleTicks(c), 20) },
+	"momentum_10":   func(c []types.Candle) float64 { return CalculateMomentum(candleTicks(c), 10) },
+	"trend_strength_9_21_50": func(c []types.Candle) float64 {
+		return CalculateTrendStrength(candleTicks(c), 9, 21, 50)
+	},
+
+	"macd_line":      func(c []types.Candle) float64 { return CalculateMACD(candleTicks(c), 12, 26, 9).Line },
+	"macd_signal":    func(c []types.Candle) float64 { return CalculateMACD(candleTicks(c), 12, 26, 9).Signal },
+	"macd_histogram": func(c []types.Candle) float64 { return CalculateMACD(candleTicks(c), 12, 26, 9).Histogram },
+	"stoch_k_14_3":   func(c []types.Candle) float64 { return CalculateStochastic(c, 14, 3).K },
+	"stoch_d_14_3":   func(c []types.Candle) float64 { return CalculateStochastic(c, 14, 3).D },
+	"stoch_rsi_k":    func(c []types.Candle) float64 { return CalculateStochasticRSI(candleTicks(c), 14, 14, 3, 3).K },
+	"stoch_rsi_d":    func(c []types.Candle) float64 { return CalculateStochasticRSI(candleTicks(c), 14, 14, 3, 3).D },
+	"williams_r_14":  func(c []types.Candle) float64 { return CalculateWilliamsR(c, 14) },
+
+	"keltner_upper":   func(c []types.Candle) float64 { return CalculateKeltnerChannel(c, 20, 10, 2).Upper },
+	"keltner_middle":  func(c []types.Candle) float64 { return CalculateKeltnerChannel(c, 20, 10, 2).Middle },
+	"keltner_lower":   func(c []types.Candle) float64 { return CalculateKeltnerChannel(c, 20, 10, 2).Lower },
+	"donchian_upper":  func(c []types.Candle) float64 { return CalculateDonchianChannel(c, 20).Upper },
+	"donchian_middle": func(c []types.Candle) float64 { return CalculateDonchianChannel(c, 20).Middle },
+	"donchian_lower":  func(c []types.Candle) float64 { return CalculateDonchianChannel(c, 20).Lower },
+	"psar_sar":        func(c []types.Candle) float64 { return CalculateParabolicSAR(c, 0.02, 0.2).SAR },
+	"psar_trend":      func(c []types.Candle) float64 { return float64(CalculateParabolicSAR(c, 0.02, 0.2).Trend) },
+
+	"adx_14":              func(c []types.Candle) float64 { return CalculateADX(c, 14).ADX },
+	"plus_di_14":          func(c []types.Candle) float64 { return CalculateADX(c, 14).PlusDI },
+	"minus_di_14":         func(c []types.Candle) float64 { return CalculateADX(c, 14).MinusDI },
+	"efficiency_ratio_20": func(c []types.Candle) float64 { return CalculateEfficiencyRatio(candleTicks(c), 20) },
+	"hurst_100":           func(c []types.Candle) float64 { return CalculateHurst(candleTicks(c), 100) },
+
+	"ichimoku_tenkan":   func(c []types.Candle) float64 { return CalculateIchimoku(c, 9, 26, 52, 26).Tenkan },
+	"ichimoku_kijun":    func(c []types.Candle) float64 { return CalculateIchimoku(c, 9, 26, 52, 26).Kijun },
+	"ichimoku_senkou_a": func(c []types.Candle) float64 { return CalculateIchimoku(c, 9, 26, 52, 26).SenkouA },
+	"ichimoku_senkou_b": func(c []types.Candle) float64 { return CalculateIchimoku(c, 9, 26, 52, 26).SenkouB },
+	"ichimoku_chikou":   func(c []types.Candle) float64 { return CalculateIchimoku(c, 9, 26, 52, 26).Chikou },
+}
+
+func loadGolden(t *testing.T) goldenFile {
+	t.Helper()
+	data, err := os.ReadFile("testdata/golden.json")
+	if err != nil {
+		t.Fatalf("read golden fixture: %v", err)
+	}
+
+	var golden goldenFile
+	if err := json.Unmarshal(data, &golden); err != nil {
+		t.Fatalf("parse golden fixture: %v", err)
+	}
+	return golden
+}
+
+// candlesFromGolden builds one-minute candles from fixture rows
+func candlesFromGolden(rows []goldenCandle) []types.Candle {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	out := make([]types.Candle, len(rows))
+	for i, r := range rows {
+		out[i] = types.Candle{
+			Market:    "fixture",
+			Open:      r.Open,
+			High:      r.High,
+			Low:       r.Low,
+			Close:     r.Close,
+			Volume:    r.Volume,
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+		}
+	}
+	return out
+}
+
+// candlesFromCloses builds flat candles (O=H=L=C) from closing prices
+func candlesFromCloses(closes []float64) []types.Candle {
+	rows := make([]goldenCandle, len(closes))
+	for i, c := range closes {
+		rows[i] = goldenCandle{Open: c, High: c, Low: c, Close: c, Volume: 1}
+	}
+	return candlesFromGolden(rows)
+}
+
+// candleTicks turns candle closes into ticks
+func candleTicks(candles []types.Candle) []types.Tick {
+	ticks := make([]types.Tick, len(candles))
+	for i, c := range candles {
+		ticks[i] = types.Tick{Market: c.Market, Price: c.Close, Timestamp: c.Timestamp}
+	}
+	return ticks
+}
+
+// lastClose returns the latest close, 0 for no candles
+func lastClose(candles []types.Candle) float64 {
+	if len(candles) == 0 {
+		return 0
+	}
+	return candles[len(candles)-1].Close
+}
+
+// assertClose compares with a relative tolerance suited to float64 round-off
+func assertClose(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-9*math.Max(1, math.Abs(want)) {
+		t.Errorf("%s = %.12f, want %.12f", name, got, want)
+	}
+}
+
+func runGoldenCases(t *testing.T, candles []types.Candle, cases []goldenCase) {
+	for _, tc := range cases {
+		window := candles[:tc.N]
+
+		keys := make([]string, 0, len(tc.Values))
+		for key := range tc.Values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			fn, ok := goldenFuncs[key]
+			if !ok {
+				t.Errorf("n=%d: no function registered for golden key %q", tc.N, key)
+				continue
+			}
+			assertClose(t, key, fn(window), tc.Values[key])
+		}
+	}
+}
+
+func TestGoldenWilderRSI(t *testing.T) {
+	golden := loadGolden(t)
+	runGoldenCases(t, candlesFromCloses(golden.WilderRSI.Closes), golden.WilderRSI.Cases)
+}
+
+// TestPublishedRSIWorksheet checks the RSI column of the published
+// StockCharts worksheet in testdata/stockcharts_rsi.csv, row by row, against
+// CalculateWilderRSI. CalculateRSI averages only the last period changes, so
+// it matches the first row alone, where both are a plain 14-change mean.
+func TestPublishedRSIWorksheet(t *testing.T) {
+	file, err := os.Open("testdata/stockcharts_rsi.csv")
+	if err != nil {
+		t.Fatalf("open worksheet: %v", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = '#'
+	rows, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("parse worksheet: %v", err)
+	}
+
+	ticks := make([]types.Tick, 0, len(rows))
+	checked := 0
+	for _, row := range rows[1:] {
+		price, err := strconv.ParseFloat(row[0], 64)
+		if err != nil {
+			t.Fatalf("close %q: %v", row[0], err)
+		}
+		ticks = append(ticks, types.Tick{Price: price})
+		if row[1] == "" {
+			continue
+		}
+		published, err := strconv.ParseFloat(row[1], 64)
+		if err != nil {
+			t.Fatalf("rsi %q: %v", row[1], err)
+		}
+
+		// The worksheet rounds RSI to two decimals
+		if got := CalculateWilderRSI(ticks, 14); math.Abs(got-published) > 0.005 {
+			t.Errorf("Wilder RSI after %d closes = %.4f, worksheet %.2f", len(ticks), got, published)
+		}
+		if checked == 0 {
+			if got := CalculateRSI(ticks, 14); math.Abs(got-published) > 0.005 {
+				t.Errorf("RSI after %d closes = %.4f, worksheet %.2f", len(ticks), got, published)
+			}
+		}
+		checked++
+	}
+	if checked != 19 {
+		t.Fatalf("checked %d worksheet RSI values, want 19", checked)
+	}
+}
+
+// TestEMAClosedForms checks CalculateEMA against textbook identities of an
+// SMA-seeded EMA with alpha = 2/(n+1): on a linear ramp it lags price by
+// exactly slope*(n-1)/2 from the seed on, and after a step it closes the gap
+// by a factor of (1-alpha) per tick.
+func TestEMAClosedForms(t *testing.T) {
+	for _, period := range []int{9, 21, 50} {
+		ramp := make([]types.Tick, 3*period)
+		for i := range ramp {
+			ramp[i].Price = 100 + 0.5*float64(i)
+		}
+		for n := period; n <= len(ramp); n += period / 2 {
+			want := ramp[n-1].Price - 0.5*float64(period-1)/2
+			assertClose(t, "ramp EMA", CalculateEMA(ramp[:n], period), want)
+		}
+
+		alpha := 2 / float64(period+1)
+		step := make([]types.Tick, period, 3*period)
+		for i := range step {
+			step[i].Price = 100
+		}
+		for k := 1; k <= 2*period; k++ {
+			step = append(step, types.Tick{Price: 110})
+			want := 110 - 10*math.Pow(1-alpha, float64(k))
+			assertClose(t, "step EMA", CalculateEMA(step, period), want)
+		}
+	}
+}
+
+// TestATRClosedForm checks CalculateATR against Wilder's smoothing: the
+// first ATR is the mean of the first n true ranges, and each later bar moves
+// it 1/n of the way to the new range, so a step from range a to range b
+// leaves b + (a-b)*((n-1)/n)^k after k bars
+func TestATRClosedForm(t *testing.T) {
+	const period = 14
+	bar := func(r float64) types.Candle {
+		// Closes stay at 100, so the true range is the bar's own range
+		return types.Candle{Open: 100, High: 100 + r/2, Low: 100 - r/2, Close: 100}
+	}
+
+	candles := []types.Candle{bar(2)}
+	for i := 0; i < period; i++ {
+		candles = append(candles, bar(2))
+	}
+	assertClose(t, "seed ATR", CalculateATR(candles, period), 2)
+
+	for k := 1; k <= 3*period; k++ {
+		candles = append(candles, bar(1))
+		want := 1 + math.Pow(float64(period-1)/period, float64(k))
+		assertClose(t, "step ATR", CalculateATR(candles, period), want)
+	}
+}
+
+func TestGoldenOHLC(t *testing.T) {
+	golden := loadGolden(t)
+	runGoldenCases(t, candlesFromGolden(golden.OHLC.Candles), golden.OHLC.Cases)
+}
+
+// TestGoldenCoverage makes sure every function in the table is checked
+// against at least one reference value
+func TestGoldenCoverage(t *testing.T) {
+	golden := loadGolden(t)
+	seen := map[string]bool{}
+	for _, tc := range append(golden.WilderRSI.Cases, golden.OHLC.Cases...) {
+		for key := range tc.Values {
+			seen[key] = true
+		}
+	}
+
+	for key := range goldenFuncs {
+		if !seen[key] {
+			t.Errorf("golden function %q has no reference value", key)
+		}
+	}
+}
+
+func TestCalculateAllIndicatorsMatchesParts(t *testing.T) {
+	golden := loadGolden(t)
+	candles := candlesFromGolden(golden.OHLC.Candles)
+	ticks := candleTicks(candles)
+	config := types.StrategyConfig{
+		RSIPeriod: 14, EMAFast: 9, EMASlow: 21, EMATrend: 50, BBPeriod: 20, BBStdDev: 2,
+	}
+
+	want := golden.OHLC.Cases[len(golden.OHLC.Cases)-1].Values
+	got := CalculateAllIndicators(ticks, config)
+
+	assertClose(t, "RSI", got.RSI, want["rsi_14"])
+	assertClose(t, "EMA9", got.EMA9, want["ema_9"])
+	assertClose(t, "EMA21", got.EMA21, want["ema_21"])
+	assertClose(t, "EMA50", got.EMA50, want["ema_50"])
+	assertClose(t, "BBUpper", got.BBUpper, want["bb_upper_20_2"])
+	assertClose(t, "BBMiddle", got.BBMiddle, want["bb_middle_20_2"])
+	assertClose(t, "BBLower", got.BBLower, want["bb_lower_20_2"])
+	assertClose(t, "BBPosition", got.BBPosition, want["bb_position_20_2"])
+	assertClose(t, "Volatility", got.Volatility, want["volatility_20"])
+	assertClose(t, "Momentum", got.Momentum, want["momentum_10"])
+	assertClose(t, "TrendStrength", got.TrendStrength, want["trend_strength_9_21_50"])
+}
+
+func TestCalculateBBPosition(t *testing.T) {
+	bb := BollingerBands{Upper: 110, Middle: 100, Lower: 90}
+	tests := []struct {
+		name  string
+		price float64
+		want  float64
+	}{
+		{"at middle", 100, 0},
+		{"at upper", 110, 1},
+		{"at lower", 90, -1},
+		{"halfway up", 105, 0.5},
+		{"above upper clamps", 130, 1},
+		{"below lower clamps", 70, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertClose(t, "position", CalculateBBPosition(tt.price, bb), tt.want)
+		})
+	}
+
+	if got := CalculateBBPosition(100, BollingerBands{Upper: 100, Middle: 100, Lower: 100}); got != 0 {
+		t.Errorf("collapsed bands: position = %v, want 0", got)
+	}
+}
+
+func TestDetectPattern(t *testing.T) {
+	tests := []struct {
+		name   string
+		prices []float64
+		want   string
+	}{
+		{"too short", []float64{1, 2, 3}, "insufficient_data"},
+		{"double bottom", []float64{100, 101, 100.05, 101, 100.5}, "double_bottom"},
+		{"double top", []float64{101, 100, 100.95, 100, 100.5}, "double_top"},
+		{"strong uptrend", []float64{100, 101, 102, 103, 104}, "strong_uptrend"},
+		{"strong downtrend", []float64{104, 103, 102, 101, 100}, "strong_downtrend"},
+		{"consolidation", []float64{100, 101, 101, 100.5, 101}, "consolidation"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectPattern(ticksFromPrices(tt.prices)); got != tt.want {
+				t.Errorf("DetectPattern = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
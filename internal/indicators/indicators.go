@@ -57,44 +57,17 @@ func CalculateRSI(ticks []types.Tick, period int) float64 {
 	return rsi
 }
 
-// CalculateEMA calculates Exponential Moving Average
-// ✅ RELAXED: Works with partial data
+// CalculateEMA calculates Exponential Moving Average over the whole series,
+// seeded with the SMA of the first period prices. It shares emaSeries with
+// MACD and Keltner, so EMA9/21/50 agree with the EMAs inside those indicators.
+// ✅ RELAXED: With less than period ticks the SMA of what we have is returned
 func CalculateEMA(ticks []types.Tick, period int) float64 {
 	if len(ticks) == 0 {
 		return 0
 	}
 
-	// ✅ RELAXED: Use what we have (was required full period)
-	if len(ticks) < period/3 {
-		// Not enough data, return simple average
-		sum := 0.0
-		for _, tick := range ticks {
-			sum += tick.Price
-		}
-		return sum / float64(len(ticks))
-	}
-
-	actualPeriod := period
-	if len(ticks) < period {
-		actualPeriod = len(ticks)
-	}
-
-	multiplier := 2.0 / float64(actualPeriod+1)
-
-	// Start with SMA
-	sum := 0.0
-	startIdx := len(ticks) - actualPeriod
-	for i := startIdx; i < len(ticks); i++ {
-		sum += ticks[i].Price
-	}
-	ema := sum / float64(actualPeriod)
-
-	// Calculate EMA
-	for i := startIdx + 1; i < len(ticks); i++ {
-		ema = (ticks[i].Price-ema)*multiplier + ema
-	}
-
-	return ema
+	series := emaSeries(tickPrices(ticks), period)
+	return series[len(series)-1]
 }
 
 // CalculateSMA calculates Simple Moving Average
@@ -0,0 +1,291 @@
+package indicators
+
+import (
+	"math"
+	"otc-predictor/pkg/types"
+)
+
+// MACD holds Moving Average Convergence Divergence values
+type MACD struct {
+	Line      float64
+	Signal    float64
+	Histogram float64
+}
+
+// CalculateMACD calculates MACD line, signal line and histogram
+func CalculateMACD(ticks []types.Tick, fastPeriod, slowPeriod, signalPeriod int) MACD {
+	prices := tickPrices(ticks)
+	if len(prices) < slowPeriod || fastPeriod >= slowPeriod {
+		return MACD{}
+	}
+
+	fast := emaSeries(prices, fastPeriod)
+	slow := emaSeries(prices, slowPeriod)
+
+	// MACD line only exists once the slow EMA is seeded
+	line := make([]float64, 0, len(prices)-slowPeriod+1)
+	for i := slowPeriod - 1; i < len(prices); i++ {
+		line = append(line, fast[i]-slow[i])
+	}
+
+	result := MACD{Line: line[len(line)-1]}
+	if len(line) < signalPeriod {
+		// Not enough history for the signal line - use what we have
+		result.Signal = mean(line)
+	} else {
+		signal := emaSeries(line, signalPeriod)
+		result.Signal = signal[len(signal)-1]
+	}
+	result.Histogram = result.Line - result.Signal
+
+	return result
+}
+
+// Stochastic holds %K and %D values (0-100)
+type Stochastic struct {
+	K float64
+	D float64
+}
+
+// CalculateStochastic calculates the stochastic oscillator on OHLC candles
+func CalculateStochastic(candles []types.Candle, kPeriod, dPeriod int) Stochastic {
+	if len(candles) < kPeriod || kPeriod < 1 {
+		return Stochastic{K: 50, D: 50}
+	}
+
+	if dPeriod < 1 {
+		dPeriod = 1
+	}
+
+	// %K for the last dPeriod bars (or as many as we have)
+	kValues := []float64{}
+	for end := len(candles) - dPeriod + 1; end <= len(candles); end++ {
+		if end < kPeriod {
+			continue
+		}
+		window := candles[end-kPeriod : end]
+		highest, lowest := highLow(window)
+		kValues = append(kValues, percentInRange(window[len(window)-1].Close, lowest, highest))
+	}
+
+	return Stochastic{
+		K: kValues[len(kValues)-1],
+		D: mean(kValues),
+	}
+}
+
+// CalculateWilderRSI calculates RSI the way Wilder published it: the first
+// average gain and loss are plain means of the first period changes, later
+// ones are smoothed as (previous*(period-1) + change) / period. Unlike
+// CalculateRSI every change in the series counts. Needs period+1 prices;
+// returns 50 with fewer.
+func CalculateWilderRSI(ticks []types.Tick, period int) float64 {
+	prices := finitePrices(tickPrices(ticks))
+	if period < 1 || len(prices) < period+1 {
+		return 50.0
+	}
+
+	avgGain, avgLoss := 0.0, 0.0
+	for i := 1; i < len(prices); i++ {
+		change := prices[i] - prices[i-1]
+		gain, loss := math.Max(change, 0), math.Max(-change, 0)
+		if i <= period {
+			avgGain += gain / float64(period)
+			avgLoss += loss / float64(period)
+			continue
+		}
+		avgGain = (avgGain*float64(period-1) + gain) / float64(period)
+		avgLoss = (avgLoss*float64(period-1) + loss) / float64(period)
+	}
+
+	if avgLoss == 0 {
+		if avgGain > 0 {
+			return 100.0
+		}
+		return 50.0
+	}
+	return 100 - 100/(1+avgGain/avgLoss)
+}
+
+// CalculateStochasticRSI applies the stochastic formula to RSI values
+func CalculateStochasticRSI(ticks []types.Tick, rsiPeriod, stochPeriod, kSmooth, dSmooth int) Stochastic {
+	if kSmooth < 1 {
+		kSmooth = 1
+	}
+	if dSmooth < 1 {
+		dSmooth = 1
+	}
+
+	needed := stochPeriod + kSmooth + dSmooth - 2
+	if len(ticks) < rsiPeriod+needed || stochPeriod < 1 {
+		return Stochastic{K: 50, D: 50}
+	}
+
+	// RSI series for the bars we need
+	rsiValues := make([]float64, needed)
+	for i := range rsiValues {
+		end := len(ticks) - needed + 1 + i
+		rsiValues[i] = CalculateRSI(ticks[:end], rsiPeriod)
+	}
+
+	// Raw stochastic of RSI
+	raw := []float64{}
+	for end := stochPeriod; end <= len(rsiValues); end++ {
+		window := rsiValues[end-stochPeriod : end]
+		lowest, highest := window[0], window[0]
+		for _, v := range window {
+			lowest = math.Min(lowest, v)
+			highest = math.Max(highest, v)
+		}
+		raw = append(raw, percentInRange(window[len(window)-1], lowest, highest))
+	}
+
+	// %K is an SMA of the raw value, %D an SMA of %K
+	kValues := []float64{}
+	for end := kSmooth; end <= len(raw); end++ {
+		kValues = append(kValues, mean(raw[end-kSmooth:end]))
+	}
+
+	return Stochastic{
+		K: kValues[len(kValues)-1],
+		D: mean(kValues[len(kValues)-dSmooth:]),
+	}
+}
+
+// CalculateCCI calculates the Commodity Channel Index
+func CalculateCCI(candles []types.Candle, period int) float64 {
+	if len(candles) < period || period < 2 {
+		return 0
+	}
+
+	window := candles[len(candles)-period:]
+	typical := make([]float64, len(window))
+	for i, c := range window {
+		typical[i] = (c.High + c.Low + c.Close) / 3
+	}
+
+	avg := mean(typical)
+	meanDeviation := 0.0
+	for _, tp := range typical {
+		meanDeviation += math.Abs(tp - avg)
+	}
+	meanDeviation /= float64(period)
+
+	if meanDeviation == 0 {
+		return 0
+	}
+
+	return (typical[len(typical)-1] - avg) / (0.015 * meanDeviation)
+}
+
+// CalculateWilliamsR calculates Williams %R (-100 to 0)
+func CalculateWilliamsR(candles []types.Candle, period int) float64 {
+	if len(candles) < period || period < 1 {
+		return -50
+	}
+
+	window := candles[len(candles)-period:]
+	highest, lowest := highLow(window)
+	if highest == lowest {
+		return -50
+	}
+
+	return (highest - window[len(window)-1].Close) / (highest - lowest) * -100
+}
+
+// CalculateROC calculates Rate of Change in percent
+func CalculateROC(ticks []types.Tick, period int) float64 {
+	if len(ticks) <= period || period < 1 {
+		return 0
+	}
+
+	pastPrice := ticks[len(ticks)-period-1].Price
+	if pastPrice == 0 {
+		return 0
+	}
+
+	return (ticks[len(ticks)-1].Price - pastPrice) / pastPrice * 100
+}
+
+// Helper functions
+
+// tickPrices extracts prices from ticks
+func tickPrices(ticks []types.Tick) []float64 {
+	prices := make([]float64, len(ticks))
+	for i, tick := range ticks {
+		prices[i] = tick.Price
+	}
+	return prices
+}
+
+// emaSeries returns an EMA series seeded with the SMA of the first period values.
+// Entries before the seed hold the running SMA so the slice aligns with values.
+// Non-finite values carry the previous entry: the recursion feeds every output
+// into the next, so one NaN print would otherwise make the rest of the series NaN.
+func emaSeries(values []float64, period int) []float64 {
+	series := make([]float64, len(values))
+	if len(values) == 0 || period < 1 {
+		return series
+	}
+
+	multiplier := 2.0 / float64(period+1)
+	sum := 0.0
+	count := 0
+	for i, v := range values {
+		if !isFinite(v) {
+			if i > 0 {
+				series[i] = series[i-1]
+			}
+			continue
+		}
+		if count < period {
+			sum += v
+			count++
+			series[i] = sum / float64(count)
+			continue
+		}
+		series[i] = (v-series[i-1])*multiplier + series[i-1]
+	}
+
+	return series
+}
+
+// isFinite reports whether v is neither NaN nor infinite
+func isFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+// mean returns the arithmetic mean of values
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// highLow returns the highest high and lowest low of candles
+func highLow(candles []types.Candle) (float64, float64) {
+	highest := candles[0].High
+	lowest := candles[0].Low
+	for _, c := range candles {
+		if c.High > highest {
+			highest = c.High
+		}
+		if c.Low < lowest {
+			lowest = c.Low
+		}
+	}
+	return highest, lowest
+}
+
+// percentInRange maps value into 0-100 within [low, high]
+func percentInRange(value, low, high float64) float64 {
+	if high == low {
+		return 50
+	}
+	return (value - low) / (high - low) * 100
+}
@@ -354,21 +354,278 @@ func detectTriangles(ticks []types.Tick) Pattern {
 }
 
 // detectWedges detects wedge patterns (reversal)
+// Rising Wedge (bearish): Both highs and lows rising, but converging
+// Falling Wedge (bullish): Both highs and lows falling, but converging
 func detectWedges(ticks []types.Tick) Pattern {
-	// Rising Wedge (bearish): Both highs and lows rising, but converging
-	// Falling Wedge (bullish): Both highs and lows falling, but converging
+	if len(ticks) < 40 {
+		return Pattern{Type: NoPattern}
+	}
+
+	peaks := findSwingHighs(ticks, 60)
+	troughs := findSwingLows(ticks, 60)
+
+	// Need at least 3 touches on each trendline
+	if len(peaks) < 3 || len(troughs) < 3 {
+		return Pattern{Type: NoPattern}
+	}
+
+	upper := fitPivotLine(peaks)
+	lower := fitPivotLine(troughs)
+
+	// Both trendlines must actually be respected
+	if upper.R2 < 0.6 || lower.R2 < 0.6 {
+		return Pattern{Type: NoPattern}
+	}
+
+	startIdx := peaks[0].Index
+	if troughs[0].Index < startIdx {
+		startIdx = troughs[0].Index
+	}
+	endIdx := len(ticks) - 1
+
+	widthStart := upper.At(startIdx) - lower.At(startIdx)
+	widthEnd := upper.At(endIdx) - lower.At(endIdx)
+	if widthStart <= 0 || widthEnd <= 0 {
+		return Pattern{Type: NoPattern}
+	}
+
+	// Lines must converge by at least 25%
+	convergence := 1 - widthEnd/widthStart
+	if convergence < 0.25 {
+		return Pattern{Type: NoPattern}
+	}
+
+	currentPrice := ticks[endIdx].Price
+	midline := (upper.At(endIdx) + lower.At(endIdx)) / 2
+	fitQuality := (upper.R2 + lower.R2) / 2
+	strength := math.Min(1.0, convergence*0.6+fitQuality*0.4)
+
+	// Rising wedge: lows climbing faster than highs, price losing the upper half
+	if upper.Slope > 0 && lower.Slope > upper.Slope && currentPrice < midline {
+		// Don't signal once price has already fallen far through support
+		if (lower.At(endIdx)-currentPrice)/currentPrice > 0.015 {
+			return Pattern{Type: NoPattern}
+		}
+
+		return Pattern{
+			Type:       RisingWedge,
+			Direction:  "DOWN",
+			Confidence: 0.68 + (strength * 0.06),
+			Strength:   strength,
+		}
+	}
+
+	// Falling wedge: highs dropping faster than lows, price holding the upper half
+	if lower.Slope < 0 && upper.Slope < lower.Slope && currentPrice > midline {
+		if (currentPrice-upper.At(endIdx))/currentPrice > 0.015 {
+			return Pattern{Type: NoPattern}
+		}
+
+		return Pattern{
+			Type:       FallingWedge,
+			Direction:  "UP",
+			Confidence: 0.68 + (strength * 0.06),
+			Strength:   strength,
+		}
+	}
 
-	// Simplified detection - would need trendline analysis for full implementation
 	return Pattern{Type: NoPattern}
 }
 
 // detectFlags detects flag patterns (continuation)
+// Bull/Bear flags are small parallel consolidations after strong impulse moves
 func detectFlags(ticks []types.Tick) Pattern {
-	// Bull/Bear flags are small consolidations after strong moves
-	// Would need to detect strong prior move + small consolidation rectangle
+	if len(ticks) < 30 {
+		return Pattern{Type: NoPattern}
+	}
 
-	// Simplified detection
-	return Pattern{Type: NoPattern}
+	best := Pattern{Type: NoPattern}
+
+	// Try a few consolidation lengths and keep the cleanest flag
+	for _, flagLen := range []int{8, 12, 16} {
+		flag := detectFlag(ticks, flagLen, 10)
+		if flag.Type != NoPattern && flag.Strength > best.Strength {
+			best = flag
+		}
+	}
+
+	return best
+}
+
+// detectFlag checks for an impulse leg of poleLen bars followed by a
+// consolidation channel of flagLen bars at the end of ticks
+func detectFlag(ticks []types.Tick, flagLen, poleLen int) Pattern {
+	if len(ticks) < flagLen+poleLen+1 {
+		return Pattern{Type: NoPattern}
+	}
+
+	flagStart := len(ticks) - flagLen
+	poleStart := ticks[flagStart-poleLen-1].Price
+	poleEnd := ticks[flagStart-1].Price
+	poleMove := poleEnd - poleStart
+
+	// Impulse leg must be at least 0.4%
+	if math.Abs(poleMove)/poleStart < 0.004 {
+		return Pattern{Type: NoPattern}
+	}
+
+	// ...and mostly one-directional
+	withPole := 0
+	for i := flagStart - poleLen; i < flagStart; i++ {
+		change := ticks[i].Price - ticks[i-1].Price
+		if change*poleMove > 0 {
+			withPole++
+		}
+	}
+	directionality := float64(withPole) / float64(poleLen)
+	if directionality < 0.7 {
+		return Pattern{Type: NoPattern}
+	}
+
+	// Fit the consolidation channel
+	xs := make([]float64, flagLen)
+	ys := make([]float64, flagLen)
+	for i := 0; i < flagLen; i++ {
+		xs[i] = float64(i)
+		ys[i] = ticks[flagStart+i].Price
+	}
+	line := linearRegression(xs, ys)
+
+	residuals := make([]float64, flagLen)
+	for i := range residuals {
+		residuals[i] = ys[i] - line.At(i)
+	}
+	channelHeight := residualRange(residuals)
+	if channelHeight == 0 || channelHeight > math.Abs(poleMove)*0.5 {
+		return Pattern{Type: NoPattern}
+	}
+
+	// Channel should be parallel, not a pennant
+	half := flagLen / 2
+	firstHalf := residualRange(residuals[:half])
+	secondHalf := residualRange(residuals[half:])
+	if firstHalf == 0 || secondHalf == 0 {
+		return Pattern{Type: NoPattern}
+	}
+	ratio := secondHalf / firstHalf
+	if ratio < 0.5 || ratio > 2.0 {
+		return Pattern{Type: NoPattern}
+	}
+
+	// Channel drifts flat or against the pole, never steeply
+	channelDrift := line.Slope * float64(flagLen-1)
+	driftWithPole := channelDrift
+	if poleMove < 0 {
+		driftWithPole = -channelDrift
+	}
+	if driftWithPole > math.Abs(poleMove)*0.1 || math.Abs(channelDrift) > math.Abs(poleMove)*0.5 {
+		return Pattern{Type: NoPattern}
+	}
+
+	strength := math.Min(1.0, (math.Abs(poleMove)/channelHeight)/6)*0.6 + directionality*0.4
+	strength = math.Min(1.0, strength)
+
+	if poleMove > 0 {
+		lowest := ys[0]
+		for _, y := range ys {
+			lowest = math.Min(lowest, y)
+		}
+		// Shallow retracement of the impulse leg
+		if (poleEnd-lowest)/poleMove > 0.5 {
+			return Pattern{Type: NoPattern}
+		}
+
+		return Pattern{
+			Type:       BullFlag,
+			Direction:  "UP",
+			Confidence: 0.68 + (strength * 0.06),
+			Strength:   strength,
+		}
+	}
+
+	highest := ys[0]
+	for _, y := range ys {
+		highest = math.Max(highest, y)
+	}
+	if (highest-poleEnd)/-poleMove > 0.5 {
+		return Pattern{Type: NoPattern}
+	}
+
+	return Pattern{
+		Type:       BearFlag,
+		Direction:  "DOWN",
+		Confidence: 0.68 + (strength * 0.06),
+		Strength:   strength,
+	}
+}
+
+// TrendLine is a least-squares line fitted over bar indices
+type TrendLine struct {
+	Slope     float64
+	Intercept float64
+	R2        float64
+}
+
+// At returns the line value at bar index idx
+func (l TrendLine) At(idx int) float64 {
+	return l.Intercept + l.Slope*float64(idx)
+}
+
+// fitPivotLine fits a trendline through swing points
+func fitPivotLine(pivots []PricePivot) TrendLine {
+	xs := make([]float64, len(pivots))
+	ys := make([]float64, len(pivots))
+	for i, p := range pivots {
+		xs[i] = float64(p.Index)
+		ys[i] = p.Price
+	}
+	return linearRegression(xs, ys)
+}
+
+// linearRegression fits y = intercept + slope*x by least squares
+func linearRegression(xs, ys []float64) TrendLine {
+	if len(xs) < 2 {
+		return TrendLine{}
+	}
+
+	meanX, meanY := mean(xs), mean(ys)
+	sxx, sxy, syy := 0.0, 0.0, 0.0
+	for i := range xs {
+		dx := xs[i] - meanX
+		dy := ys[i] - meanY
+		sxx += dx * dx
+		sxy += dx * dy
+		syy += dy * dy
+	}
+
+	if sxx == 0 {
+		return TrendLine{Intercept: meanY}
+	}
+
+	slope := sxy / sxx
+	r2 := 1.0 // A perfectly flat series is perfectly explained
+	if syy > 0 {
+		r2 = (sxy * sxy) / (sxx * syy)
+	}
+
+	return TrendLine{
+		Slope:     slope,
+		Intercept: meanY - slope*meanX,
+		R2:        r2,
+	}
+}
+
+// residualRange returns max - min of residuals
+func residualRange(residuals []float64) float64 {
+	if len(residuals) == 0 {
+		return 0
+	}
+	lowest, highest := residuals[0], residuals[0]
+	for _, r := range residuals {
+		lowest = math.Min(lowest, r)
+		highest = math.Max(highest, r)
+	}
+	return highest - lowest
 }
 
 // Helper functions
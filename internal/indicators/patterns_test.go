@@ -0,0 +1,342 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"otc-predictor/pkg/types"
+)
+
+// ticksFromPrices builds one-second ticks from a price series
+func ticksFromPrices(prices []float64) []types.Tick {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ticks := make([]types.Tick, len(prices))
+	for i, p := range prices {
+		ticks[i] = types.Tick{
+			Market:    "fixture",
+			Price:     p,
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+			Epoch:     start.Unix() + int64(i),
+		}
+	}
+	return ticks
+}
+
+// mirror reflects prices around a level, turning bullish fixtures bearish
+func mirror(prices []float64, level float64) []float64 {
+	out := make([]float64, len(prices))
+	for i, p := range prices {
+		out[i] = level - p
+	}
+	return out
+}
+
+// zigzagBetween oscillates between two lines with a 10-bar cycle,
+// touching the upper line on multiples of 10 and the lower line on 5s
+func zigzagBetween(n int, upper, lower func(i int) float64) []float64 {
+	prices := make([]float64, n)
+	for i := range prices {
+		phase := math.Abs(float64(i%10)-5) / 5
+		prices[i] = lower(i) + (upper(i)-lower(i))*phase
+	}
+	return prices
+}
+
+// risingWedgeFixture: highs +0.04/bar, lows +0.05/bar, ending on the lower line
+func risingWedgeFixture() []float64 {
+	return zigzagBetween(66,
+		func(i int) float64 { return 100 + 0.04*float64(i) },
+		func(i int) float64 { return 99 + 0.05*float64(i) },
+	)
+}
+
+// risingChannelFixture: parallel lines, no convergence
+func risingChannelFixture() []float64 {
+	return zigzagBetween(66,
+		func(i int) float64 { return 100 + 0.05*float64(i) },
+		func(i int) float64 { return 99 + 0.05*float64(i) },
+	)
+}
+
+// bullFlagFixture: quiet base, 1% impulse leg, then a gently falling channel
+func bullFlagFixture() []float64 {
+	prices := []float64{}
+	for i := 0; i < 12; i++ {
+		prices = append(prices, 100+0.02*float64(i%2))
+	}
+	for i := 1; i <= 10; i++ {
+		prices = append(prices, 100+0.1*float64(i))
+	}
+	for j := 0; j < 12; j++ {
+		offset := 0.05
+		if j%2 == 1 {
+			offset = -0.05
+		}
+		prices = append(prices, 100.95-0.01*float64(j)+offset)
+	}
+	return prices
+}
+
+// noPoleFixture: the same consolidation without an impulse leg
+func noPoleFixture() []float64 {
+	prices := []float64{}
+	for j := 0; j < 34; j++ {
+		offset := 0.05
+		if j%2 == 1 {
+			offset = -0.05
+		}
+		prices = append(prices, 100-0.01*float64(j%12)+offset)
+	}
+	return prices
+}
+
+func TestDetectWedges(t *testing.T) {
+	tests := []struct {
+		name      string
+		prices    []float64
+		want      PatternType
+		direction string
+	}{
+		{"rising wedge", risingWedgeFixture(), RisingWedge, "DOWN"},
+		{"falling wedge", mirror(risingWedgeFixture(), 200), FallingWedge, "UP"},
+		{"parallel rising channel", risingChannelFixture(), NoPattern, ""},
+		{"parallel falling channel", mirror(risingChannelFixture(), 200), NoPattern, ""},
+		{"too short", risingWedgeFixture()[:30], NoPattern, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := detectWedges(ticksFromPrices(tt.prices))
+			if got.Type != tt.want {
+				t.Fatalf("type = %s, want %s", got.Type, tt.want)
+			}
+			if tt.want == NoPattern {
+				return
+			}
+			if got.Direction != tt.direction {
+				t.Errorf("direction = %s, want %s", got.Direction, tt.direction)
+			}
+			assertPatternScores(t, got)
+		})
+	}
+}
+
+func TestDetectFlags(t *testing.T) {
+	tests := []struct {
+		name      string
+		prices    []float64
+		want      PatternType
+		direction string
+	}{
+		{"bull flag", bullFlagFixture(), BullFlag, "UP"},
+		{"bear flag", mirror(bullFlagFixture(), 200), BearFlag, "DOWN"},
+		{"consolidation without pole", noPoleFixture(), NoPattern, ""},
+		{"too short", bullFlagFixture()[:20], NoPattern, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := detectFlags(ticksFromPrices(tt.prices))
+			if got.Type != tt.want {
+				t.Fatalf("type = %s, want %s", got.Type, tt.want)
+			}
+			if tt.want == NoPattern {
+				return
+			}
+			if got.Direction != tt.direction {
+				t.Errorf("direction = %s, want %s", got.Direction, tt.direction)
+			}
+			assertPatternScores(t, got)
+		})
+	}
+}
+
+func TestDetectFlagsRejectsDeepRetracement(t *testing.T) {
+	prices := bullFlagFixture()
+	// Drag the consolidation down to give back most of the impulse leg
+	for i := len(prices) - 12; i < len(prices); i++ {
+		prices[i] -= 0.7
+	}
+
+	if got := detectFlags(ticksFromPrices(prices)); got.Type != NoPattern {
+		t.Fatalf("type = %s, want none", got.Type)
+	}
+}
+
+// assertPatternScores checks scoring stays in the range used by the other detectors
+func assertPatternScores(t *testing.T, p Pattern) {
+	t.Helper()
+	if p.Strength < 0 || p.Strength > 1 {
+		t.Errorf("strength = %.3f, want 0-1", p.Strength)
+	}
+	if p.Confidence < 0.65 || p.Confidence > 0.85 {
+		t.Errorf("confidence = %.3f, want 0.65-0.85", p.Confidence)
+	}
+}
+
+// path linearly interpolates between (index, price) keypoints
+func path(points ...[2]float64) []float64 {
+	last := points[len(points)-1]
+	prices := make([]float64, int(last[0])+1)
+	for k := 1; k < len(points); k++ {
+		from, to := points[k-1], points[k]
+		for i := int(from[0]); i <= int(to[0]); i++ {
+			frac := (float64(i) - from[0]) / (to[0] - from[0])
+			prices[i] = from[1] + (to[1]-from[1])*frac
+		}
+	}
+	return prices
+}
+
+// headAndShouldersFixture: shoulders at 101, head at 102, neckline at 100,
+// price back at the neckline
+func headAndShouldersFixture() []float64 {
+	return path(
+		[2]float64{0, 100.2}, [2]float64{25, 101}, [2]float64{30, 100},
+		[2]float64{35, 102}, [2]float64{40, 100}, [2]float64{45, 101.05},
+		[2]float64{59, 100.1},
+	)
+}
+
+// unevenShouldersFixture: head and shoulders shape with shoulders 2% apart
+func unevenShouldersFixture() []float64 {
+	return path(
+		[2]float64{0, 100.2}, [2]float64{25, 101}, [2]float64{30, 100},
+		[2]float64{35, 104}, [2]float64{40, 100}, [2]float64{45, 103.1},
+		[2]float64{59, 100.1},
+	)
+}
+
+// doubleTopFixture: two peaks 0.05% apart, price 0.7% off the second
+func doubleTopFixture() []float64 {
+	return path(
+		[2]float64{0, 100.5}, [2]float64{20, 100.2}, [2]float64{27, 101},
+		[2]float64{34, 100}, [2]float64{41, 101.05}, [2]float64{49, 100.3},
+	)
+}
+
+// tripleTopFixture: three equal-ish peaks, the middle one not the highest
+func tripleTopFixture() []float64 {
+	return path(
+		[2]float64{0, 100.3}, [2]float64{25, 101}, [2]float64{30, 100},
+		[2]float64{35, 100.95}, [2]float64{40, 100}, [2]float64{45, 101.02},
+		[2]float64{59, 100.2},
+	)
+}
+
+// ascendingTriangleFixture: flat highs at 101.5, lows rising 100 -> 100.6
+func ascendingTriangleFixture() []float64 {
+	return path(
+		[2]float64{0, 101.2}, [2]float64{18, 100}, [2]float64{26, 101.5},
+		[2]float64{34, 100.6}, [2]float64{42, 101.5}, [2]float64{49, 101},
+	)
+}
+
+func TestDetectHeadAndShoulders(t *testing.T) {
+	hs := detectHeadAndShoulders(ticksFromPrices(headAndShouldersFixture()))
+	if hs.Type != HeadAndShoulders || hs.Direction != "DOWN" {
+		t.Fatalf("got %s/%s, want head_and_shoulders/DOWN", hs.Type, hs.Direction)
+	}
+	assertPatternScores(t, hs)
+
+	ihs := detectInverseHeadAndShoulders(ticksFromPrices(mirror(headAndShouldersFixture(), 200)))
+	if ihs.Type != InverseHeadAndShoulders || ihs.Direction != "UP" {
+		t.Fatalf("got %s/%s, want inverse_head_and_shoulders/UP", ihs.Type, ihs.Direction)
+	}
+	assertPatternScores(t, ihs)
+
+	// Uneven shoulders (2% apart) are not a head and shoulders
+	if got := detectHeadAndShoulders(ticksFromPrices(unevenShouldersFixture())); got.Type != NoPattern {
+		t.Errorf("uneven shoulders: type = %s, want none", got.Type)
+	}
+
+	// Price still far above the neckline
+	early := headAndShouldersFixture()
+	for i := 50; i < len(early); i++ {
+		early[i] = 102
+	}
+	if got := detectHeadAndShoulders(ticksFromPrices(early)); got.Type != NoPattern {
+		t.Errorf("above neckline: type = %s, want none", got.Type)
+	}
+}
+
+func TestDetectDoubleTopBottom(t *testing.T) {
+	tests := []struct {
+		name      string
+		prices    []float64
+		want      PatternType
+		direction string
+	}{
+		{"double top", doubleTopFixture(), DoubleTop, "DOWN"},
+		{"double bottom", mirror(doubleTopFixture(), 200), DoubleBottom, "UP"},
+		{"too short", doubleTopFixture()[:25], NoPattern, ""},
+		{"peaks 2% apart", path(
+			[2]float64{0, 99.8}, [2]float64{27, 101},
+			[2]float64{34, 100}, [2]float64{41, 103}, [2]float64{49, 102},
+		), NoPattern, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := detectDoubleTopBottom(ticksFromPrices(tt.prices))
+			if got.Type != tt.want {
+				t.Fatalf("type = %s, want %s", got.Type, tt.want)
+			}
+			if tt.want != NoPattern {
+				if got.Direction != tt.direction {
+					t.Errorf("direction = %s, want %s", got.Direction, tt.direction)
+				}
+				assertPatternScores(t, got)
+			}
+		})
+	}
+}
+
+func TestDetectTripleTopBottom(t *testing.T) {
+	top := detectTripleTopBottom(ticksFromPrices(tripleTopFixture()))
+	if top.Type != TripleTop || top.Direction != "DOWN" {
+		t.Fatalf("got %s/%s, want triple_top/DOWN", top.Type, top.Direction)
+	}
+	assertPatternScores(t, top)
+
+	bottom := detectTripleTopBottom(ticksFromPrices(mirror(tripleTopFixture(), 200)))
+	if bottom.Type != TripleBottom || bottom.Direction != "UP" {
+		t.Fatalf("got %s/%s, want triple_bottom/UP", bottom.Type, bottom.Direction)
+	}
+	assertPatternScores(t, bottom)
+
+	// Peaks spread wider than 1% of their average
+	if got := detectTripleTopBottom(ticksFromPrices(unevenShouldersFixture())); got.Type != NoPattern {
+		t.Errorf("uneven peaks: type = %s, want none", got.Type)
+	}
+}
+
+func TestDetectTriangles(t *testing.T) {
+	asc := detectTriangles(ticksFromPrices(ascendingTriangleFixture()))
+	if asc.Type != AscendingTriangle || asc.Direction != "UP" {
+		t.Fatalf("got %s/%s, want ascending_triangle/UP", asc.Type, asc.Direction)
+	}
+	assertPatternScores(t, asc)
+
+	desc := detectTriangles(ticksFromPrices(mirror(ascendingTriangleFixture(), 200)))
+	if desc.Type != DescendingTriangle || desc.Direction != "DOWN" {
+		t.Fatalf("got %s/%s, want descending_triangle/DOWN", desc.Type, desc.Direction)
+	}
+	assertPatternScores(t, desc)
+
+	if got := detectTriangles(ticksFromPrices(risingChannelFixture())); got.Type != NoPattern {
+		t.Errorf("rising channel: type = %s, want none", got.Type)
+	}
+}
+
+func TestDetectAdvancedPatternsPriority(t *testing.T) {
+	if got := DetectAdvancedPatterns(ticksFromPrices(headAndShouldersFixture()[:40])); got.Type != NoPattern {
+		t.Errorf("under 50 ticks: type = %s, want none", got.Type)
+	}
+
+	// Head and shoulders is checked before the double/triple top it also contains
+	if got := DetectAdvancedPatterns(ticksFromPrices(headAndShouldersFixture())); got.Type != HeadAndShoulders {
+		t.Errorf("type = %s, want head_and_shoulders", got.Type)
+	}
+}
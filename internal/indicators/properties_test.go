@@ -0,0 +1,240 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+
+	"otc-predictor/pkg/types"
+)
+
+// Property tests: whatever the input, indicators must not panic, must stay in
+// their documented range and must not invent extremes from bad data.
+
+// boundedOutputs lists the golden keys with a fixed valid range
+var boundedOutputs = map[string][2]float64{
+	"rsi_14":                 {0, 100},
+	"stoch_k_14_3":           {0, 100},
+	"stoch_d_14_3":           {0, 100},
+	"stoch_rsi_k":            {0, 100},
+	"stoch_rsi_d":            {0, 100},
+	"williams_r_14":          {-100, 0},
+	"bb_position_20_2":       {-1, 1},
+	"trend_strength_9_21_50": {0, 1},
+	"psar_trend":             {-1, 1},
+	"volatility_20":          {0, math.Inf(1)},
+	"atr_14":                 {0, math.Inf(1)},
+	"adx_14":                 {0, 100},
+	"plus_di_14":             {0, 100},
+	"minus_di_14":            {0, 100},
+	"efficiency_ratio_20":    {0, 1},
+	"hurst_100":              {0, 1},
+}
+
+// unboundedOutputs are oscillators without a fixed range; they only need to be finite
+var unboundedOutputs = map[string]bool{
+	"roc_12":         true,
+	"momentum_10":    true,
+	"cci_20":         true,
+	"macd_line":      true,
+	"macd_signal":    true,
+	"macd_histogram": true,
+}
+
+// checkOutput validates a value against its key's range. Price-level outputs
+// (averages, bands, channels, SAR) must stay within a generous envelope of
+// the input prices.
+func checkOutput(t *testing.T, label, key string, value float64, candles []types.Candle) {
+	t.Helper()
+
+	if math.IsInf(value, 0) {
+		t.Errorf("%s: %s = %v", label, key, value)
+		return
+	}
+	if math.IsNaN(value) {
+		return
+	}
+
+	if bounds, ok := boundedOutputs[key]; ok {
+		if value < bounds[0] || value > bounds[1] {
+			t.Errorf("%s: %s = %v, outside [%v, %v]", label, key, value, bounds[0], bounds[1])
+		}
+		return
+	}
+	if unboundedOutputs[key] || len(candles) == 0 {
+		return
+	}
+
+	lowest, highest := math.Inf(1), math.Inf(-1)
+	for _, c := range candles {
+		for _, p := range []float64{c.Open, c.High, c.Low, c.Close} {
+			if !math.IsNaN(p) {
+				lowest = math.Min(lowest, p)
+				highest = math.Max(highest, p)
+			}
+		}
+	}
+	if math.IsInf(lowest, 0) {
+		return
+	}
+	span := math.Max(highest-lowest, highest*0.01)
+	if value < lowest-span*5 || value > highest+span*5 {
+		t.Errorf("%s: %s = %v, far outside price range [%v, %v]", label, key, value, lowest, highest)
+	}
+}
+
+func TestIndicatorsFlatPrices(t *testing.T) {
+	flat := make([]float64, 80)
+	for i := range flat {
+		flat[i] = 100
+	}
+	candles := candlesFromCloses(flat)
+
+	want := map[string]float64{
+		"rsi_14": 50, "stoch_k_14_3": 50, "stoch_d_14_3": 50, "stoch_rsi_k": 50, "stoch_rsi_d": 50,
+		"williams_r_14": -50, "bb_position_20_2": 0, "volatility_20": 0, "atr_14": 0,
+		"momentum_10": 0, "roc_12": 0, "cci_20": 0,
+		"macd_line": 0, "macd_signal": 0, "macd_histogram": 0,
+		"trend_strength_9_21_50": 0.3, // No EMA alignment
+		"psar_trend":             1,
+		"adx_14":                 0, "plus_di_14": 0, "minus_di_14": 0,
+		"efficiency_ratio_20": 0, "hurst_100": 0.5, // No movement, no persistence
+	}
+
+	for key, fn := range goldenFuncs {
+		got := fn(candles)
+		expected, ok := want[key]
+		if !ok {
+			expected = 100 // Every price-level output collapses onto the price
+		}
+		assertClose(t, key, got, expected)
+	}
+}
+
+func TestIndicatorsShortSlices(t *testing.T) {
+	prices := []float64{100, 100.4, 99.7, 100.9, 100.2, 99.8}
+
+	for n := 0; n <= len(prices); n++ {
+		candles := candlesFromCloses(prices[:n])
+		for key, fn := range goldenFuncs {
+			got := fn(candles)
+			if math.IsNaN(got) {
+				t.Errorf("n=%d: %s = NaN", n, key)
+				continue
+			}
+			checkOutput(t, "short", key, got, candles)
+		}
+	}
+}
+
+func TestIndicatorsNaNInput(t *testing.T) {
+	golden := loadGolden(t)
+	base := candlesFromGolden(golden.OHLC.Candles)[:60]
+
+	variants := map[string]func([]types.Candle){
+		"last close":   func(c []types.Candle) { c[len(c)-1].Close = math.NaN() },
+		"middle close": func(c []types.Candle) { c[30].Close = math.NaN() },
+		"last high":    func(c []types.Candle) { c[len(c)-1].High = math.NaN() },
+		"first candle": func(c []types.Candle) {
+			c[0] = types.Candle{Open: math.NaN(), High: math.NaN(), Low: math.NaN(), Close: math.NaN()}
+		},
+	}
+
+	for name, corrupt := range variants {
+		t.Run(name, func(t *testing.T) {
+			candles := append([]types.Candle{}, base...)
+			corrupt(candles)
+
+			for key, fn := range goldenFuncs {
+				checkOutput(t, name, key, fn(candles), candles)
+			}
+		})
+	}
+}
+
+func TestPatternDetectorsNaNInput(t *testing.T) {
+	prices := risingWedgeFixture()
+	config := types.StrategyConfig{RSIPeriod: 14}
+
+	for _, idx := range []int{0, len(prices) / 2, len(prices) - 1} {
+		corrupted := append([]float64{}, prices...)
+		corrupted[idx] = math.NaN()
+		ticks := ticksFromPrices(corrupted)
+
+		detectors := map[string]func() Pattern{
+			"advanced":       func() Pattern { return DetectAdvancedPatterns(ticks) },
+			"head_shoulders": func() Pattern { return detectHeadAndShoulders(ticks) },
+			"inverse_hs":     func() Pattern { return detectInverseHeadAndShoulders(ticks) },
+			"double":         func() Pattern { return detectDoubleTopBottom(ticks) },
+			"triple":         func() Pattern { return detectTripleTopBottom(ticks) },
+			"triangles":      func() Pattern { return detectTriangles(ticks) },
+			"wedges":         func() Pattern { return detectWedges(ticks) },
+			"flags":          func() Pattern { return detectFlags(ticks) },
+		}
+		for name, detect := range detectors {
+			if p := detect(); p.Type != NoPattern {
+				if math.IsNaN(p.Confidence) || math.IsNaN(p.Strength) {
+					t.Errorf("NaN at %d: %s reported %s with NaN scores", idx, name, p.Type)
+				}
+			}
+		}
+
+		if d := DetectRSIDivergence(ticks, config); d.Type != NoDivergence {
+			if math.IsNaN(d.Confidence) || d.Confidence < 0.6 || d.Confidence > 0.85 {
+				t.Errorf("NaN at %d: divergence %s confidence %v", idx, d.Type, d.Confidence)
+			}
+		}
+
+		candles := candlesFromCloses(corrupted)
+		for _, cp := range DetectCandlestickPatterns(candles) {
+			if math.IsNaN(cp.Confidence) || math.IsNaN(cp.Strength) {
+				t.Errorf("NaN at %d: candlestick %s with NaN scores", idx, cp.Type)
+			}
+		}
+	}
+}
+
+// recursiveOutputs carry state through the whole series (or, for hurst_100,
+// have a window longer than the fixture), so a bad print must be skipped
+// rather than turning every later value into NaN
+var recursiveOutputs = []string{
+	"hurst_100",
+	"ema_9", "ema_21", "ema_50", "trend_strength_9_21_50",
+	"macd_line", "macd_signal", "macd_histogram",
+	"atr_14", "adx_14", "plus_di_14", "minus_di_14", "keltner_upper", "keltner_middle", "keltner_lower",
+	"psar_sar", "psar_trend",
+}
+
+func TestIndicatorsRecoverFromOldNaN(t *testing.T) {
+	golden := loadGolden(t)
+	clean := candlesFromGolden(golden.OHLC.Candles)
+
+	corrupted := append([]types.Candle{}, clean...)
+	corrupted[5].Close = math.NaN()
+	corrupted[5].High = math.NaN()
+
+	// Windowed indicators never see the bad candle
+	for key, fn := range goldenFuncs {
+		isRecursive := false
+		for _, r := range recursiveOutputs {
+			isRecursive = isRecursive || r == key
+		}
+		if isRecursive {
+			continue
+		}
+		assertClose(t, key, fn(corrupted), fn(clean))
+	}
+
+	// Recursive indicators skip it and stay close to the clean series
+	for _, key := range recursiveOutputs {
+		got := goldenFuncs[key](corrupted)
+		if math.IsNaN(got) {
+			t.Errorf("%s stayed NaN 85 bars after a bad print", key)
+			continue
+		}
+		checkOutput(t, "recovered", key, got, clean)
+	}
+
+	if got, want := goldenFuncs["psar_trend"](corrupted), goldenFuncs["psar_trend"](clean); got != want {
+		t.Errorf("psar_trend = %v, want %v", got, want)
+	}
+}
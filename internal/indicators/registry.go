@@ -0,0 +1,475 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"otc-predictor/internal/candles"
+	"otc-predictor/pkg/types"
+)
+
+// ParamType describes how an indicator parameter is interpreted
+type ParamType string
+
+const (
+	IntParam   ParamType = "int"
+	FloatParam ParamType = "float"
+)
+
+// ParamSpec describes a single typed indicator parameter
+type ParamSpec struct {
+	Name        string    `json:"name"`
+	Type        ParamType `json:"type"`
+	Default     float64   `json:"default"`
+	Min         float64   `json:"min"`
+	Max         float64   `json:"max"`
+	Description string    `json:"description"`
+}
+
+// Params holds resolved parameter values by name
+type Params map[string]float64
+
+// Int returns an integer parameter
+func (p Params) Int(name string) int {
+	return int(math.Round(p[name]))
+}
+
+// Float returns a float parameter
+func (p Params) Float(name string) float64 {
+	return p[name]
+}
+
+// Values holds indicator outputs by name
+type Values map[string]float64
+
+// ComputeFunc calculates an indicator from candles with resolved params
+type ComputeFunc func(candles []types.Candle, params Params) Values
+
+// Definition describes a registered indicator. Validate, when set, checks
+// rules across parameters after each is in range.
+type Definition struct {
+	Key         string               `json:"key"`
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	Params      []ParamSpec          `json:"params"`
+	Outputs     []string             `json:"outputs"`
+	Compute     ComputeFunc          `json:"-"`
+	Validate    func(p Params) error `json:"-"`
+}
+
+var (
+	registry   = make(map[string]Definition)
+	registryMu sync.RWMutex
+)
+
+// Register adds an indicator definition to the registry
+func Register(def Definition) error {
+	if def.Key == "" {
+		return fmt.Errorf("indicator key is required")
+	}
+	if def.Compute == nil {
+		return fmt.Errorf("indicator %s has no compute function", def.Key)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[def.Key]; exists {
+		return fmt.Errorf("indicator %s already registered", def.Key)
+	}
+	registry[def.Key] = def
+	return nil
+}
+
+// MustRegister registers an indicator and panics on error
+func MustRegister(def Definition) {
+	if err := Register(def); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns a registered indicator definition
+func Lookup(key string) (Definition, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	def, exists := registry[key]
+	return def, exists
+}
+
+// Registered returns all registered indicators sorted by key
+func Registered() []Definition {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	defs := make([]Definition, 0, len(registry))
+	for _, def := range registry {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool {
+		return defs[i].Key < defs[j].Key
+	})
+	return defs
+}
+
+// Resolve fills in defaults and validates parameter overrides
+func (d Definition) Resolve(overrides Params) (Params, error) {
+	resolved := make(Params, len(d.Params))
+	known := make(map[string]bool, len(d.Params))
+
+	for _, spec := range d.Params {
+		known[spec.Name] = true
+		value := spec.Default
+		if v, ok := overrides[spec.Name]; ok {
+			value = v
+		}
+
+		if math.IsNaN(value) || value < spec.Min || value > spec.Max {
+			return nil, fmt.Errorf("%s.%s must be between %g and %g", d.Key, spec.Name, spec.Min, spec.Max)
+		}
+		if spec.Type == IntParam && value != math.Trunc(value) {
+			return nil, fmt.Errorf("%s.%s must be an integer", d.Key, spec.Name)
+		}
+		resolved[spec.Name] = value
+	}
+
+	for name := range overrides {
+		if !known[name] {
+			return nil, fmt.Errorf("%s has no parameter %q", d.Key, name)
+		}
+	}
+
+	if d.Validate != nil {
+		if err := d.Validate(resolved); err != nil {
+			return nil, fmt.Errorf("%s: %w", d.Key, err)
+		}
+	}
+
+	return resolved, nil
+}
+
+// Compute calculates a registered indicator by key
+func Compute(key string, candleData []types.Candle, overrides Params) (Values, error) {
+	def, exists := Lookup(key)
+	if !exists {
+		return nil, fmt.Errorf("unknown indicator %q", key)
+	}
+	if len(candleData) == 0 {
+		return nil, fmt.Errorf("no candles to compute %s", key)
+	}
+
+	params, err := def.Resolve(overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	return def.Compute(candleData, params), nil
+}
+
+// ComputeExtra calculates configured indicators and flattens them to "key.output".
+// Invalid specs are skipped so a bad config entry cannot block predictions.
+func ComputeExtra(candleData []types.Candle, specs []types.IndicatorSpec) map[string]float64 {
+	if len(specs) == 0 || len(candleData) == 0 {
+		return nil
+	}
+
+	extra := make(map[string]float64)
+	for _, spec := range specs {
+		values, err := Compute(spec.Key, candleData, spec.Params)
+		if err != nil {
+			continue
+		}
+		for output, value := range values {
+			extra[spec.Key+"."+output] = value
+		}
+	}
+
+	return extra
+}
+
+func init() {
+	closeTicks := candles.CandlesToTicks
+
+	// Core indicators (also exposed as fixed fields on types.Indicators)
+	MustRegister(Definition{
+		Key:         "rsi",
+		Name:        "Relative Strength Index",
+		Description: "Average gain vs average loss over the period (0-100)",
+		Params:      []ParamSpec{periodParam(14, 2, 200)},
+		Outputs:     []string{"value"},
+		Compute: func(c []types.Candle, p Params) Values {
+			return Values{"value": CalculateRSI(closeTicks(c), p.Int("period"))}
+		},
+	})
+	MustRegister(Definition{
+		Key:         "ema",
+		Name:        "Exponential Moving Average",
+		Description: "Exponentially weighted average of closes",
+		Params:      []ParamSpec{periodParam(21, 1, 500)},
+		Outputs:     []string{"value"},
+		Compute: func(c []types.Candle, p Params) Values {
+			return Values{"value": CalculateEMA(closeTicks(c), p.Int("period"))}
+		},
+	})
+	MustRegister(Definition{
+		Key:         "sma",
+		Name:        "Simple Moving Average",
+		Description: "Arithmetic mean of closes",
+		Params:      []ParamSpec{periodParam(20, 1, 500)},
+		Outputs:     []string{"value"},
+		Compute: func(c []types.Candle, p Params) Values {
+			return Values{"value": CalculateSMA(closeTicks(c), p.Int("period"))}
+		},
+	})
+	MustRegister(Definition{
+		Key:         "bb",
+		Name:        "Bollinger Bands",
+		Description: "SMA +/- standard deviations, with position scaled to -1..1",
+		Params: []ParamSpec{
+			periodParam(20, 2, 500),
+			{Name: "std_dev", Type: FloatParam, Default: 2.0, Min: 0.1, Max: 5, Description: "Band width in standard deviations"},
+		},
+		Outputs: []string{"upper", "middle", "lower", "position"},
+		Compute: func(c []types.Candle, p Params) Values {
+			bb := CalculateBollingerBands(closeTicks(c), p.Int("period"), p.Float("std_dev"))
+			return Values{
+				"upper":    bb.Upper,
+				"middle":   bb.Middle,
+				"lower":    bb.Lower,
+				"position": CalculateBBPosition(c[len(c)-1].Close, bb),
+			}
+		},
+	})
+	MustRegister(Definition{
+		Key:         "momentum",
+		Name:        "Momentum",
+		Description: "Percent change over the period",
+		Params:      []ParamSpec{periodParam(10, 1, 500)},
+		Outputs:     []string{"value"},
+		Compute: func(c []types.Candle, p Params) Values {
+			return Values{"value": CalculateMomentum(closeTicks(c), p.Int("period"))}
+		},
+	})
+	MustRegister(Definition{
+		Key:         "atr",
+		Name:        "Average True Range",
+		Description: "Wilder-smoothed true range",
+		Params:      []ParamSpec{periodParam(14, 1, 500)},
+		Outputs:     []string{"value"},
+		Compute: func(c []types.Candle, p Params) Values {
+			return Values{"value": CalculateATR(c, p.Int("period"))}
+		},
+	})
+
+	// Extended library
+	MustRegister(Definition{
+		Key:         "macd",
+		Name:        "MACD",
+		Description: "Fast EMA minus slow EMA, with signal line and histogram",
+		Params: []ParamSpec{
+			{Name: "fast", Type: IntParam, Default: 12, Min: 1, Max: 200, Description: "Fast EMA period"},
+			{Name: "slow", Type: IntParam, Default: 26, Min: 2, Max: 400, Description: "Slow EMA period"},
+			{Name: "signal", Type: IntParam, Default: 9, Min: 1, Max: 200, Description: "Signal EMA period"},
+		},
+		Outputs: []string{"line", "signal", "histogram"},
+		Compute: func(c []types.Candle, p Params) Values {
+			m := CalculateMACD(closeTicks(c), p.Int("fast"), p.Int("slow"), p.Int("signal"))
+			return Values{"line": m.Line, "signal": m.Signal, "histogram": m.Histogram}
+		},
+		Validate: func(p Params) error {
+			if p.Int("fast") >= p.Int("slow") {
+				return fmt.Errorf("fast (%d) must be shorter than slow (%d)", p.Int("fast"), p.Int("slow"))
+			}
+			return nil
+		},
+	})
+	MustRegister(Definition{
+		Key:         "wilder_rsi",
+		Name:        "Wilder RSI",
+		Description: "RSI with Wilder-smoothed average gain and loss over the whole series (0-100)",
+		Params:      []ParamSpec{periodParam(14, 2, 200)},
+		Outputs:     []string{"value"},
+		Compute: func(c []types.Candle, p Params) Values {
+			return Values{"value": CalculateWilderRSI(closeTicks(c), p.Int("period"))}
+		},
+	})
+	MustRegister(Definition{
+		Key:         "stochastic",
+		Name:        "Stochastic Oscillator",
+		Description: "Close position within the high/low range (0-100)",
+		Params: []ParamSpec{
+			{Name: "k_period", Type: IntParam, Default: 14, Min: 1, Max: 200, Description: "%K lookback"},
+			{Name: "d_period", Type: IntParam, Default: 3, Min: 1, Max: 50, Description: "%D smoothing"},
+		},
+		Outputs: []string{"k", "d"},
+		Compute: func(c []types.Candle, p Params) Values {
+			s := CalculateStochastic(c, p.Int("k_period"), p.Int("d_period"))
+			return Values{"k": s.K, "d": s.D}
+		},
+	})
+	MustRegister(Definition{
+		Key:         "stoch_rsi",
+		Name:        "Stochastic RSI",
+		Description: "Stochastic oscillator applied to RSI (0-100)",
+		Params: []ParamSpec{
+			{Name: "rsi_period", Type: IntParam, Default: 14, Min: 2, Max: 200, Description: "RSI period"},
+			{Name: "stoch_period", Type: IntParam, Default: 14, Min: 1, Max: 200, Description: "Stochastic lookback"},
+			{Name: "k_smooth", Type: IntParam, Default: 3, Min: 1, Max: 50, Description: "%K smoothing"},
+			{Name: "d_smooth", Type: IntParam, Default: 3, Min: 1, Max: 50, Description: "%D smoothing"},
+		},
+		Outputs: []string{"k", "d"},
+		Compute: func(c []types.Candle, p Params) Values {
+			s := CalculateStochasticRSI(closeTicks(c), p.Int("rsi_period"), p.Int("stoch_period"), p.Int("k_smooth"), p.Int("d_smooth"))
+			return Values{"k": s.K, "d": s.D}
+		},
+	})
+	MustRegister(Definition{
+		Key:         "cci",
+		Name:        "Commodity Channel Index",
+		Description: "Typical price deviation from its mean",
+		Params:      []ParamSpec{periodParam(20, 2, 500)},
+		Outputs:     []string{"value"},
+		Compute: func(c []types.Candle, p Params) Values {
+			return Values{"value": CalculateCCI(c, p.Int("period"))}
+		},
+	})
+	MustRegister(Definition{
+		Key:         "williams_r",
+		Name:        "Williams %R",
+		Description: "Close position below the period high (-100 to 0)",
+		Params:      []ParamSpec{periodParam(14, 1, 500)},
+		Outputs:     []string{"value"},
+		Compute: func(c []types.Candle, p Params) Values {
+			return Values{"value": CalculateWilliamsR(c, p.Int("period"))}
+		},
+	})
+	MustRegister(Definition{
+		Key:         "psar",
+		Name:        "Parabolic SAR",
+		Description: "Trailing stop-and-reverse level",
+		Params: []ParamSpec{
+			{Name: "step", Type: FloatParam, Default: 0.02, Min: 0.001, Max: 0.5, Description: "Acceleration step"},
+			{Name: "max_step", Type: FloatParam, Default: 0.2, Min: 0.01, Max: 1, Description: "Maximum acceleration"},
+		},
+		Outputs: []string{"sar", "trend"},
+		Compute: func(c []types.Candle, p Params) Values {
+			sar := CalculateParabolicSAR(c, p.Float("step"), p.Float("max_step"))
+			return Values{"sar": sar.SAR, "trend": float64(sar.Trend)}
+		},
+	})
+	MustRegister(Definition{
+		Key:         "keltner",
+		Name:        "Keltner Channels",
+		Description: "EMA +/- ATR multiple",
+		Params: []ParamSpec{
+			{Name: "ema_period", Type: IntParam, Default: 20, Min: 1, Max: 500, Description: "Middle line EMA period"},
+			{Name: "atr_period", Type: IntParam, Default: 10, Min: 1, Max: 500, Description: "ATR period"},
+			{Name: "multiplier", Type: FloatParam, Default: 2.0, Min: 0.1, Max: 10, Description: "ATR multiple"},
+		},
+		Outputs: []string{"upper", "middle", "lower"},
+		Compute: func(c []types.Candle, p Params) Values {
+			ch := CalculateKeltnerChannel(c, p.Int("ema_period"), p.Int("atr_period"), p.Float("multiplier"))
+			return Values{"upper": ch.Upper, "middle": ch.Middle, "lower": ch.Lower}
+		},
+	})
+	MustRegister(Definition{
+		Key:         "donchian",
+		Name:        "Donchian Channels",
+		Description: "Highest high and lowest low over the period",
+		Params:      []ParamSpec{periodParam(20, 1, 500)},
+		Outputs:     []string{"upper", "middle", "lower"},
+		Compute: func(c []types.Candle, p Params) Values {
+			ch := CalculateDonchianChannel(c, p.Int("period"))
+			return Values{"upper": ch.Upper, "middle": ch.Middle, "lower": ch.Lower}
+		},
+	})
+	MustRegister(Definition{
+		Key:         "ichimoku",
+		Name:        "Ichimoku Cloud",
+		Description: "Conversion/base lines and leading spans",
+		Params: []ParamSpec{
+			{Name: "tenkan", Type: IntParam, Default: 9, Min: 1, Max: 200, Description: "Conversion line period"},
+			{Name: "kijun", Type: IntParam, Default: 26, Min: 1, Max: 200, Description: "Base line period"},
+			{Name: "senkou_b", Type: IntParam, Default: 52, Min: 1, Max: 400, Description: "Leading span B period"},
+			{Name: "displacement", Type: IntParam, Default: 26, Min: 0, Max: 200, Description: "Cloud displacement"},
+		},
+		Outputs: []string{"tenkan", "kijun", "senkou_a", "senkou_b", "chikou"},
+		Compute: func(c []types.Candle, p Params) Values {
+			ich := CalculateIchimoku(c, p.Int("tenkan"), p.Int("kijun"), p.Int("senkou_b"), p.Int("displacement"))
+			return Values{
+				"tenkan":   ich.Tenkan,
+				"kijun":    ich.Kijun,
+				"senkou_a": ich.SenkouA,
+				"senkou_b": ich.SenkouB,
+				"chikou":   ich.Chikou,
+			}
+		},
+	})
+	MustRegister(Definition{
+		Key:         "vwap",
+		Name:        "Tick-Count VWAP",
+		Description: "Typical price weighted by ticks per candle",
+		Params:      []ParamSpec{periodParam(20, 1, 1000)},
+		Outputs:     []string{"value"},
+		Compute: func(c []types.Candle, p Params) Values {
+			return Values{"value": CalculateTickVWAP(c, p.Int("period"))}
+		},
+	})
+	MustRegister(Definition{
+		Key:         "roc",
+		Name:        "Rate of Change",
+		Description: "Percent change versus period bars ago",
+		Params:      []ParamSpec{periodParam(12, 1, 500)},
+		Outputs:     []string{"value"},
+		Compute: func(c []types.Candle, p Params) Values {
+			return Values{"value": CalculateROC(closeTicks(c), p.Int("period"))}
+		},
+	})
+	MustRegister(Definition{
+		Key:         "adx",
+		Name:        "Average Directional Index",
+		Description: "Wilder's trend strength (0-100) with +DI and -DI",
+		Params:      []ParamSpec{periodParam(14, 2, 200)},
+		Outputs:     []string{"adx", "plus_di", "minus_di"},
+		Compute: func(c []types.Candle, p Params) Values {
+			adx := CalculateADX(c, p.Int("period"))
+			return Values{"adx": adx.ADX, "plus_di": adx.PlusDI, "minus_di": adx.MinusDI}
+		},
+	})
+	MustRegister(Definition{
+		Key:         "efficiency_ratio",
+		Name:        "Kaufman Efficiency Ratio",
+		Description: "Net move over path length (0 = noise, 1 = straight line)",
+		Params:      []ParamSpec{periodParam(20, 2, 500)},
+		Outputs:     []string{"value"},
+		Compute: func(c []types.Candle, p Params) Values {
+			return Values{"value": CalculateEfficiencyRatio(closeTicks(c), p.Int("period"))}
+		},
+	})
+	MustRegister(Definition{
+		Key:         "hurst",
+		Name:        "Hurst Exponent",
+		Description: "Rescaled-range persistence of returns (<0.5 reverting, >0.5 trending)",
+		Params: []ParamSpec{
+			{Name: "window", Type: IntParam, Default: 100, Min: 32, Max: 5000, Description: "Closes analysed"},
+		},
+		Outputs: []string{"value"},
+		Compute: func(c []types.Candle, p Params) Values {
+			return Values{"value": CalculateHurst(closeTicks(c), p.Int("window"))}
+		},
+	})
+}
+
+// periodParam builds the common integer "period" parameter
+func periodParam(def, min, max float64) ParamSpec {
+	return ParamSpec{
+		Name:        "period",
+		Type:        IntParam,
+		Default:     def,
+		Min:         min,
+		Max:         max,
+		Description: "Lookback period in candles",
+	}
+}
@@ -0,0 +1,125 @@
+package indicators
+
+import (
+	"testing"
+
+	"otc-predictor/pkg/types"
+)
+
+func TestRegistryResolve(t *testing.T) {
+	def, ok := Lookup("rsi")
+	if !ok {
+		t.Fatal("rsi not registered")
+	}
+
+	params, err := def.Resolve(nil)
+	if err != nil || params.Int("period") != 14 {
+		t.Fatalf("defaults = %v, %v; want period 14", params, err)
+	}
+
+	invalid := map[string]Params{
+		"out of range": {"period": 1},
+		"not integer":  {"period": 14.5},
+		"unknown":      {"length": 14},
+	}
+	for name, overrides := range invalid {
+		if _, err := def.Resolve(overrides); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestRegistryResolveCrossParams(t *testing.T) {
+	def, ok := Lookup("macd")
+	if !ok {
+		t.Fatal("macd not registered")
+	}
+
+	tests := []struct {
+		name    string
+		params  Params
+		wantErr bool
+	}{
+		{"defaults", nil, false},
+		{"fast below slow", Params{"fast": 5, "slow": 35}, false},
+		{"fast equals slow", Params{"fast": 26, "slow": 26}, true},
+		{"fast above slow", Params{"fast": 30, "slow": 26}, true},
+		{"fast above default slow", Params{"fast": 40}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := def.Resolve(tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Resolve(%v) error = %v, wantErr %v", tt.params, err, tt.wantErr)
+			}
+		})
+	}
+
+	golden := loadGolden(t)
+	if _, err := Compute("macd", candlesFromGolden(golden.OHLC.Candles), Params{"fast": 26, "slow": 12}); err == nil {
+		t.Error("Compute with fast >= slow: expected an error")
+	}
+}
+
+func TestRegistryOutputsMatchDefinitions(t *testing.T) {
+	golden := loadGolden(t)
+	candles := candlesFromGolden(golden.OHLC.Candles)
+
+	for _, def := range Registered() {
+		values, err := Compute(def.Key, candles, nil)
+		if err != nil {
+			t.Errorf("%s: %v", def.Key, err)
+			continue
+		}
+		for _, output := range def.Outputs {
+			if _, ok := values[output]; !ok {
+				t.Errorf("%s: missing output %q", def.Key, output)
+			}
+		}
+		if len(values) != len(def.Outputs) {
+			t.Errorf("%s: %d values for %d declared outputs", def.Key, len(values), len(def.Outputs))
+		}
+	}
+}
+
+func TestRegistryCompute(t *testing.T) {
+	golden := loadGolden(t)
+	candles := candlesFromGolden(golden.OHLC.Candles)
+
+	if _, err := Compute("nope", candles, nil); err == nil {
+		t.Error("unknown key: expected an error")
+	}
+	if _, err := Compute("rsi", nil, nil); err == nil {
+		t.Error("no candles: expected an error")
+	}
+
+	values, err := Compute("rsi", candles, Params{"period": 14})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "rsi", values["value"], CalculateRSI(candleTicks(candles), 14))
+}
+
+func TestComputeExtra(t *testing.T) {
+	golden := loadGolden(t)
+	candles := candlesFromGolden(golden.OHLC.Candles)
+
+	extra := ComputeExtra(candles, []types.IndicatorSpec{
+		{Key: "rsi", Params: map[string]float64{"period": 7}},
+		{Key: "rsi", Params: map[string]float64{"period": 0}}, // Invalid, skipped
+		{Key: "missing"},
+		{Key: "macd"},
+	})
+
+	for _, key := range []string{"rsi.value", "macd.line", "macd.signal", "macd.histogram"} {
+		if _, ok := extra[key]; !ok {
+			t.Errorf("missing %s in %v", key, extra)
+		}
+	}
+	assertClose(t, "rsi.value", extra["rsi.value"], CalculateRSI(candleTicks(candles), 7))
+
+	if got := ComputeExtra(nil, []types.IndicatorSpec{{Key: "rsi"}}); got != nil {
+		t.Errorf("no candles: got %v", got)
+	}
+}
@@ -0,0 +1,359 @@
+#!/usr/bin/env python3
+"""Generates golden.json, the reference values for golden_test.go.
+
+The formulas below are written from the textbook definitions, independently
+of the Go code, using the conventions the package documents:
+
+  * RSI: average gain / average loss over the last `period` changes
+    (Cutler's RSI; equals Wilder's first RSI value)
+  * EMA: seeded with the SMA of the first `period` values
+  * Bollinger Bands / volatility: population standard deviation
+  * ATR: Wilder smoothing seeded with the SMA of the first `period` true ranges
+  * Stochastic %D and Stochastic RSI smoothing: simple moving averages
+  * ADX: Wilder smoothing of TR, +DM, -DM and DX, each seeded with an SMA
+  * Hurst: rescaled range of log returns over chunks of 8, 16, 32...
+
+Run from this directory:  python3 golden_gen.py > golden.json
+"""
+
+import json
+import math
+
+# Closes of the StockCharts RSI worksheet rounded to cents. Expected values
+# are this package's RSI formula, not the worksheet's published Wilder RSI;
+# those are checked in verbatim in stockcharts_rsi.csv.
+WILDER_CLOSES = [
+    44.34, 44.09, 44.15, 43.61, 44.33, 44.83, 45.10, 45.42, 45.84, 46.08,
+    45.89, 46.03, 45.61, 46.28, 46.28, 46.00, 46.03, 46.41, 46.22, 45.64,
+    46.21, 46.25, 45.71, 46.45, 45.78, 45.35, 44.03, 44.18, 44.22, 44.57,
+    43.42, 42.66, 43.13,
+]
+
+
+def lcg(seed):
+    """Deterministic pseudo-random stream in [0, 1), stable across platforms."""
+    state = seed
+    while True:
+        state = (state * 6364136223846793005 + 1442695040888963407) % (1 << 64)
+        yield (state >> 11) / float(1 << 53)
+
+
+def ohlc_series(n, seed=7):
+    rnd = lcg(seed)
+    candles = []
+    close = 100.0
+    for i in range(n):
+        drift = 0.15 * math.sin(i / 9.0)
+        open_ = close
+        close = round(open_ + drift + (next(rnd) - 0.5) * 1.2, 4)
+        high = round(max(open_, close) + next(rnd) * 0.5, 4)
+        low = round(min(open_, close) - next(rnd) * 0.5, 4)
+        volume = 1 + int(next(rnd) * 20)
+        candles.append({"open": open_, "high": high, "low": low, "close": close, "volume": volume})
+    return candles
+
+
+# --- reference formulas ---
+
+def mean(xs):
+    return sum(xs) / len(xs)
+
+
+def pstdev(xs):
+    m = mean(xs)
+    return math.sqrt(sum((x - m) ** 2 for x in xs) / len(xs))
+
+
+def rsi(closes, period):
+    changes = [closes[i] - closes[i - 1] for i in range(len(closes) - period, len(closes))]
+    gain = sum(c for c in changes if c > 0) / period
+    loss = sum(-c for c in changes if c < 0) / period
+    if loss == 0:
+        return 100.0 if gain > 0 else 50.0
+    return 100 - 100 / (1 + gain / loss)
+
+
+def ema_series(values, period):
+    out = []
+    k = 2.0 / (period + 1)
+    for i, v in enumerate(values):
+        if i < period:
+            out.append(mean(values[: i + 1]))
+        else:
+            out.append(out[-1] + k * (v - out[-1]))
+    return out
+
+
+def ema(values, period):
+    return ema_series(values, period)[-1]
+
+
+def bollinger(closes, period, width):
+    window = closes[-period:]
+    mid = mean(window)
+    sd = pstdev(window)
+    return mid + width * sd, mid, mid - width * sd
+
+
+def bb_position(price, upper, middle):
+    if upper == middle:
+        return 0.0
+    return max(-1.0, min(1.0, (price - middle) / (upper - middle)))
+
+
+def pct_change(closes, period):
+    return (closes[-1] - closes[-period - 1]) / closes[-period - 1] * 100
+
+
+def trend_strength(closes, fast, slow, trend):
+    f, s, t = ema(closes, fast), ema(closes, slow), ema(closes, trend)
+    if (f > s > t) or (f < s < t):
+        return min(abs(f - t) / closes[-1] * 100, 1.0)
+    return 0.3
+
+
+def macd(closes, fast, slow, signal):
+    f = ema_series(closes, fast)
+    s = ema_series(closes, slow)
+    line = [f[i] - s[i] for i in range(slow - 1, len(closes))]
+    sig = ema(line, signal)
+    return line[-1], sig, line[-1] - sig
+
+
+def stochastic(candles, k, d):
+    ks = []
+    for end in range(len(candles) - d + 1, len(candles) + 1):
+        window = candles[end - k:end]
+        hh = max(c["high"] for c in window)
+        ll = min(c["low"] for c in window)
+        ks.append((window[-1]["close"] - ll) / (hh - ll) * 100)
+    return ks[-1], mean(ks)
+
+
+def stoch_rsi(closes, rsi_period, stoch_period, k_smooth, d_smooth):
+    needed = stoch_period + k_smooth + d_smooth - 2
+    rsis = [rsi(closes[: len(closes) - needed + 1 + i], rsi_period) for i in range(needed)]
+    raw = []
+    for end in range(stoch_period, len(rsis) + 1):
+        window = rsis[end - stoch_period:end]
+        lo, hi = min(window), max(window)
+        raw.append(50.0 if hi == lo else (window[-1] - lo) / (hi - lo) * 100)
+    ks = [mean(raw[end - k_smooth:end]) for end in range(k_smooth, len(raw) + 1)]
+    return ks[-1], mean(ks[-d_smooth:])
+
+
+def cci(candles, period):
+    tp = [(c["high"] + c["low"] + c["close"]) / 3 for c in candles[-period:]]
+    m = mean(tp)
+    md = mean([abs(x - m) for x in tp])
+    return (tp[-1] - m) / (0.015 * md)
+
+
+def williams_r(candles, period):
+    window = candles[-period:]
+    hh = max(c["high"] for c in window)
+    ll = min(c["low"] for c in window)
+    return (hh - window[-1]["close"]) / (hh - ll) * -100
+
+
+def atr(candles, period):
+    trs = []
+    for i in range(1, len(candles)):
+        c, pc = candles[i], candles[i - 1]["close"]
+        trs.append(max(c["high"] - c["low"], abs(c["high"] - pc), abs(c["low"] - pc)))
+    value = mean(trs[:period])
+    for tr in trs[period:]:
+        value = (value * (period - 1) + tr) / period
+    return value
+
+
+def keltner(candles, ema_period, atr_period, mult):
+    mid = ema([c["close"] for c in candles], ema_period)
+    a = atr(candles, atr_period)
+    return mid + mult * a, mid, mid - mult * a
+
+
+def donchian(candles, period):
+    window = candles[-period:]
+    hh = max(c["high"] for c in window)
+    ll = min(c["low"] for c in window)
+    return hh, (hh + ll) / 2, ll
+
+
+def vwap(candles, period):
+    window = candles[-period:]
+    num = sum((c["high"] + c["low"] + c["close"]) / 3 * c["volume"] for c in window)
+    return num / sum(c["volume"] for c in window)
+
+
+def psar(candles, step, max_step):
+    # Wilder's rules; initial trend from the first two closes
+    trend = -1 if candles[1]["close"] < candles[0]["close"] else 1
+    sar = candles[0]["low"] if trend == 1 else candles[0]["high"]
+    ep = candles[0]["high"] if trend == 1 else candles[0]["low"]
+    af = step
+    for i in range(1, len(candles)):
+        c = candles[i]
+        sar = sar + af * (ep - sar)
+        if trend == 1:
+            sar = min([sar, candles[i - 1]["low"]] + ([candles[i - 2]["low"]] if i >= 2 else []))
+            if c["low"] < sar:
+                trend, sar, ep, af = -1, ep, c["low"], step
+                continue
+            if c["high"] > ep:
+                ep, af = c["high"], min(af + step, max_step)
+        else:
+            sar = max([sar, candles[i - 1]["high"]] + ([candles[i - 2]["high"]] if i >= 2 else []))
+            if c["high"] > sar:
+                trend, sar, ep, af = 1, ep, c["high"], step
+                continue
+            if c["low"] < ep:
+                ep, af = c["low"], min(af + step, max_step)
+    return sar, trend
+
+
+def adx(candles, period):
+    trs, pdm, mdm = [], [], []
+    for i in range(1, len(candles)):
+        c, p = candles[i], candles[i - 1]
+        up, down = c["high"] - p["high"], p["low"] - c["low"]
+        pdm.append(up if up > down and up > 0 else 0.0)
+        mdm.append(down if down > up and down > 0 else 0.0)
+        trs.append(max(c["high"] - c["low"], abs(c["high"] - p["close"]), abs(c["low"] - p["close"])))
+
+    def wilder(values):
+        out = [mean(values[:period])]
+        for v in values[period:]:
+            out.append((out[-1] * (period - 1) + v) / period)
+        return out
+
+    tr, p, m = wilder(trs), wilder(pdm), wilder(mdm)
+    dxs = []
+    for t, a, b in zip(tr, p, m):
+        plus, minus = a / t * 100, b / t * 100
+        dxs.append(abs(plus - minus) / (plus + minus) * 100)
+    return wilder(dxs)[-1], p[-1] / tr[-1] * 100, m[-1] / tr[-1] * 100
+
+
+def efficiency_ratio(closes, period):
+    window = closes[-period - 1:]
+    path = sum(abs(window[i] - window[i - 1]) for i in range(1, len(window)))
+    return abs(window[-1] - window[0]) / path
+
+
+def hurst(closes, window):
+    prices = closes[-window:]
+    returns = [math.log(prices[i] / prices[i - 1]) for i in range(1, len(prices))]
+    xs, ys = [], []
+    size = 8
+    while size <= len(returns) // 2:
+        ratios = []
+        for start in range(0, len(returns) - size + 1, size):
+            chunk = returns[start:start + size]
+            m = mean(chunk)
+            cumulative, path = 0.0, []
+            for r in chunk:
+                cumulative += r - m
+                path.append(cumulative)
+            ratios.append((max(path) - min(path)) / pstdev(chunk))
+        xs.append(math.log(size))
+        ys.append(math.log(mean(ratios)))
+        size *= 2
+    mx, my = mean(xs), mean(ys)
+    return sum((x - mx) * (y - my) for x, y in zip(xs, ys)) / sum((x - mx) ** 2 for x in xs)
+
+
+def midpoint(candles, period):
+    window = candles[-period:]
+    return (max(c["high"] for c in window) + min(c["low"] for c in window)) / 2
+
+
+def ichimoku(candles, tenkan, kijun, senkou_b, displacement):
+    base = candles[:-displacement] if len(candles) > displacement + kijun else candles
+    return {
+        "tenkan": midpoint(candles, tenkan),
+        "kijun": midpoint(candles, kijun),
+        "senkou_a": (midpoint(base, tenkan) + midpoint(base, kijun)) / 2,
+        "senkou_b": midpoint(base, senkou_b),
+        "chikou": candles[-1]["close"],
+    }
+
+
+def tick_values(closes):
+    upper, middle, lower = bollinger(closes, 20, 2.0)
+    values = {
+        "rsi_14": rsi(closes, 14),
+        "ema_9": ema(closes, 9),
+        "ema_21": ema(closes, 21),
+        "sma_10": mean(closes[-10:]),
+        "bb_upper_20_2": upper,
+        "bb_middle_20_2": middle,
+        "bb_lower_20_2": lower,
+        "bb_position_20_2": bb_position(closes[-1], upper, middle),
+        "volatility_20": pstdev(closes[-20:]),
+        "momentum_10": pct_change(closes, 10),
+        "roc_12": pct_change(closes, 12),
+        "efficiency_ratio_20": efficiency_ratio(closes, 20),
+    }
+    if len(closes) >= 33:
+        values["hurst_100"] = hurst(closes, 100)
+    if len(closes) >= 50:
+        values["ema_50"] = ema(closes, 50)
+        values["trend_strength_9_21_50"] = trend_strength(closes, 9, 21, 50)
+    if len(closes) >= 34:
+        line, sig, hist = macd(closes, 12, 26, 9)
+        values.update({"macd_line": line, "macd_signal": sig, "macd_histogram": hist})
+    if len(closes) >= 33:
+        k, d = stoch_rsi(closes, 14, 14, 3, 3)
+        values.update({"stoch_rsi_k": k, "stoch_rsi_d": d})
+    return values
+
+
+def candle_values(candles):
+    values = {}
+    k, d = stochastic(candles, 14, 3)
+    values.update({"stoch_k_14_3": k, "stoch_d_14_3": d})
+    values["cci_20"] = cci(candles, 20)
+    values["williams_r_14"] = williams_r(candles, 14)
+    values["atr_14"] = atr(candles, 14)
+    if len(candles) > 28:
+        values["adx_14"], values["plus_di_14"], values["minus_di_14"] = adx(candles, 14)
+    upper, middle, lower = keltner(candles, 20, 10, 2.0)
+    values.update({"keltner_upper": upper, "keltner_middle": middle, "keltner_lower": lower})
+    upper, middle, lower = donchian(candles, 20)
+    values.update({"donchian_upper": upper, "donchian_middle": middle, "donchian_lower": lower})
+    values["vwap_20"] = vwap(candles, 20)
+    sar, trend = psar(candles, 0.02, 0.2)
+    values.update({"psar_sar": sar, "psar_trend": float(trend)})
+    for name, value in ichimoku(candles, 9, 26, 52, 26).items():
+        values["ichimoku_" + name] = value
+    return values
+
+
+def main():
+    wilder = {
+        "description": "RSI worksheet closes rounded to cents; expected values computed by golden_gen.py",
+        "closes": WILDER_CLOSES,
+        "cases": [
+            {"n": n, "values": {"rsi_14": rsi(WILDER_CLOSES[:n], 14)}}
+            for n in range(15, len(WILDER_CLOSES) + 1)
+        ],
+    }
+
+    candles = ohlc_series(90)
+    ohlc = {
+        "description": "Seeded OHLC random walk (golden_gen.py, seed 7)",
+        "candles": candles,
+        "cases": [],
+    }
+    for n in (30, 60, 90):
+        window = candles[:n]
+        values = tick_values([c["close"] for c in window])
+        values.update(candle_values(window))
+        ohlc["cases"].append({"n": n, "values": values})
+
+    print(json.dumps({"wilder_rsi": wilder, "ohlc": ohlc}, indent=1, sort_keys=True))
+
+
+if __name__ == "__main__":
+    main()
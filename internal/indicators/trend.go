@@ -0,0 +1,179 @@
+package indicators
+
+import (
+	"math"
+	"otc-predictor/pkg/types"
+)
+
+// ADX holds Wilder's Average Directional Index with its directional indicators
+type ADX struct {
+	ADX     float64
+	PlusDI  float64
+	MinusDI float64
+}
+
+// CalculateADX calculates Wilder's ADX, +DI and -DI (0-100).
+// Smoothed values are seeded with the mean of the first period entries;
+// with less data the mean of what we have is used.
+func CalculateADX(candles []types.Candle, period int) ADX {
+	candles = finiteCandles(candles)
+	if len(candles) < 2 || period < 1 {
+		return ADX{}
+	}
+
+	n := len(candles) - 1
+	trs := make([]float64, n)
+	plusDM := make([]float64, n)
+	minusDM := make([]float64, n)
+	for i := 1; i < len(candles); i++ {
+		up := candles[i].High - candles[i-1].High
+		down := candles[i-1].Low - candles[i].Low
+		if up > down && up > 0 {
+			plusDM[i-1] = up
+		}
+		if down > up && down > 0 {
+			minusDM[i-1] = down
+		}
+		trs[i-1] = trueRange(candles[i], candles[i-1].Close)
+	}
+
+	seed := period
+	if n < seed {
+		seed = n
+	}
+	tr := mean(trs[:seed])
+	pdm := mean(plusDM[:seed])
+	mdm := mean(minusDM[:seed])
+
+	result := ADX{}
+	dxs := []float64{}
+	for i := seed - 1; i < n; i++ {
+		if i >= seed {
+			tr = (tr*float64(period-1) + trs[i]) / float64(period)
+			pdm = (pdm*float64(period-1) + plusDM[i]) / float64(period)
+			mdm = (mdm*float64(period-1) + minusDM[i]) / float64(period)
+		}
+
+		result.PlusDI, result.MinusDI = 0, 0
+		if tr > 0 {
+			result.PlusDI = pdm / tr * 100
+			result.MinusDI = mdm / tr * 100
+		}
+		dx := 0.0
+		if sum := result.PlusDI + result.MinusDI; sum > 0 {
+			dx = math.Abs(result.PlusDI-result.MinusDI) / sum * 100
+		}
+		dxs = append(dxs, dx)
+	}
+
+	if len(dxs) < period {
+		result.ADX = mean(dxs)
+		return result
+	}
+
+	adx := mean(dxs[:period])
+	for _, dx := range dxs[period:] {
+		adx = (adx*float64(period-1) + dx) / float64(period)
+	}
+	result.ADX = adx
+
+	return result
+}
+
+// CalculateEfficiencyRatio calculates Kaufman's efficiency ratio: net move over
+// the period divided by the sum of absolute moves (0 = noise, 1 = straight line)
+func CalculateEfficiencyRatio(ticks []types.Tick, period int) float64 {
+	prices := finitePrices(tickPrices(ticks))
+	if len(prices) < 2 || period < 1 {
+		return 0
+	}
+
+	if len(prices) <= period {
+		period = len(prices) - 1
+	}
+	window := prices[len(prices)-period-1:]
+
+	path := 0.0
+	for i := 1; i < len(window); i++ {
+		path += math.Abs(window[i] - window[i-1])
+	}
+	if path == 0 {
+		return 0
+	}
+
+	return math.Abs(window[len(window)-1]-window[0]) / path
+}
+
+// CalculateHurst estimates the Hurst exponent of log returns over the last
+// window prices with rescaled-range analysis. Chunks of 8, 16, 32... returns
+// are used; the slope of log(R/S) against log(size) is the exponent.
+// ~0.5 random walk, above trending, below mean reverting. Small chunks bias
+// R/S estimates upward, so a pure random walk typically reads 0.55-0.6.
+// Returns 0.5 when there is too little data.
+func CalculateHurst(ticks []types.Tick, window int) float64 {
+	prices := finitePrices(tickPrices(ticks))
+	if window > 0 && len(prices) > window {
+		prices = prices[len(prices)-window:]
+	}
+
+	returns := make([]float64, 0, len(prices))
+	for i := 1; i < len(prices); i++ {
+		if prices[i] > 0 && prices[i-1] > 0 {
+			returns = append(returns, math.Log(prices[i]/prices[i-1]))
+		}
+	}
+
+	xs := []float64{}
+	ys := []float64{}
+	for size := 8; size <= len(returns)/2; size *= 2 {
+		total := 0.0
+		count := 0
+		for start := 0; start+size <= len(returns); start += size {
+			if rs := rescaledRange(returns[start : start+size]); rs > 0 {
+				total += rs
+				count++
+			}
+		}
+		if count > 0 {
+			xs = append(xs, math.Log(float64(size)))
+			ys = append(ys, math.Log(total/float64(count)))
+		}
+	}
+
+	if len(xs) < 2 {
+		return 0.5
+	}
+
+	return math.Max(0, math.Min(1, linearRegression(xs, ys).Slope))
+}
+
+// rescaledRange returns the range of cumulative deviations over the
+// standard deviation of a chunk, or 0 for a flat chunk
+func rescaledRange(chunk []float64) float64 {
+	m := mean(chunk)
+
+	cumulative, highest, lowest, variance := 0.0, math.Inf(-1), math.Inf(1), 0.0
+	for _, v := range chunk {
+		cumulative += v - m
+		highest = math.Max(highest, cumulative)
+		lowest = math.Min(lowest, cumulative)
+		variance += (v - m) * (v - m)
+	}
+
+	std := math.Sqrt(variance / float64(len(chunk)))
+	if std == 0 {
+		return 0
+	}
+	return (highest - lowest) / std
+}
+
+// finitePrices drops NaN and infinite prices
+func finitePrices(prices []float64) []float64 {
+	out := make([]float64, 0, len(prices))
+	for _, p := range prices {
+		if isFinite(p) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,170 @@
+package kalman
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"otc-predictor/pkg/types"
+)
+
+// Service runs a local-linear-trend Kalman filter per market and candle
+// period. Each closed candle updates a hidden level and slope:
+//
+//	close[t] = level[t] + noise            (observation noise R)
+//	level[t] = level[t-1] + slope[t-1] + w (level noise LevelNoise·R)
+//	slope[t] = slope[t-1] + u              (slope noise SlopeNoise·R)
+//
+// Unlike an EMA crossover the slope is estimated directly, with its variance,
+// so a new trend shows up within a few candles and its significance is known.
+// R is rescaled from the candle window on every update so that the implied
+// variance of close-to-close changes matches the observed one.
+type Service struct {
+	config  types.KalmanConfig
+	filters map[string]*filter
+	mu      sync.Mutex
+}
+
+// Estimate is a filter's view after the latest candle
+type Estimate struct {
+	Market     string    `json:"market"`
+	Period     int       `json:"period"`  // Candle seconds
+	Candles    int       `json:"candles"` // Closed candles filtered since the last reset
+	Level      float64   `json:"level"`
+	Slope      float64   `json:"slope"` // Price change per candle
+	LevelSigma float64   `json:"level_sigma"`
+	SlopeSigma float64   `json:"slope_sigma"`
+	LevelNoise float64   `json:"level_noise"` // Level variance added per candle
+	Time       time.Time `json:"time"`        // Latest candle
+}
+
+// Projection is the trend carried over a contract duration
+type Projection struct {
+	Duration      int     `json:"duration"`
+	Candles       float64 `json:"candles"`
+	Move          float64 `json:"move"`  // Slope times candles
+	Sigma         float64 `json:"sigma"` // Deviation of the move from slope and level uncertainty
+	ZScore        float64 `json:"z_score"`
+	ProbabilityUp float64 `json:"probability_up"`
+}
+
+// filter is the state of one market and period
+type filter struct {
+	level, slope  float64
+	p00, p01, p11 float64 // State covariance
+	last          time.Time
+	candles       int
+}
+
+// NewService creates a Kalman trend service
+func NewService(config types.KalmanConfig) *Service {
+	return &Service{
+		config:  config,
+		filters: make(map[string]*filter),
+	}
+}
+
+// Update feeds the closed candles newer than the last one seen, all but the
+// final candle, which is still forming, and returns the estimate including
+// the forming candle. The filter restarts from the window when candles were
+// missed. Returns false with fewer than three candles.
+func (s *Service) Update(market string, period time.Duration, candleData []types.Candle) (Estimate, bool) {
+	if len(candleData) < 3 || period <= 0 {
+		return Estimate{}, false
+	}
+
+	r, qLevel, qSlope := s.noise(candleData)
+	closed := candleData[:len(candleData)-1]
+	key := market + "|" + period.String()
+
+	s.mu.Lock()
+	f := s.filters[key]
+	if f == nil || f.last.Before(closed[0].Timestamp.Add(-period)) {
+		f = &filter{
+			level: closed[0].Close,
+			p00:   r * 100,
+			p11:   r * 100,
+			last:  closed[0].Timestamp,
+		}
+		s.filters[key] = f
+	}
+	for _, candle := range closed {
+		if candle.Timestamp.After(f.last) {
+			f.step(candle.Close, r, qLevel, qSlope)
+			f.last = candle.Timestamp
+			f.candles++
+		}
+	}
+	live := *f
+	s.mu.Unlock()
+
+	current := candleData[len(candleData)-1]
+	live.step(current.Close, r, qLevel, qSlope)
+
+	return Estimate{
+		Market:     market,
+		Period:     int(period / time.Second),
+		Candles:    live.candles,
+		Level:      live.level,
+		Slope:      live.slope,
+		LevelSigma: math.Sqrt(live.p00),
+		SlopeSigma: math.Sqrt(live.p11),
+		LevelNoise: qLevel,
+		Time:       current.Timestamp,
+	}, true
+}
+
+// Project carries the slope over a duration in seconds. The move's deviation
+// combines the slope's uncertainty, growing with the candle count, and the
+// level noise accumulated over the same candles.
+func (e Estimate) Project(duration int) Projection {
+	h := float64(duration) / float64(e.Period)
+	projection := Projection{
+		Duration: duration,
+		Candles:  h,
+		Move:     h * e.Slope,
+		Sigma:    math.Sqrt(h*h*e.SlopeSigma*e.SlopeSigma + h*e.LevelNoise),
+	}
+	if projection.Sigma > 0 {
+		projection.ZScore = projection.Move / projection.Sigma
+	}
+	projection.ProbabilityUp = 0.5 * math.Erfc(-projection.ZScore/math.Sqrt2)
+	return projection
+}
+
+// noise returns the observation, level and slope noise variances scaled so
+// that the close-to-close variance LevelNoise·R + 2R matches the window
+func (s *Service) noise(candleData []types.Candle) (float64, float64, float64) {
+	var sum, sumSq float64
+	n := 0
+	for i := 1; i < len(candleData); i++ {
+		d := candleData[i].Close - candleData[i-1].Close
+		sum += d
+		sumSq += d * d
+		n++
+	}
+	mean := sum / float64(n)
+	variance := math.Max(sumSq/float64(n)-mean*mean, 1e-18)
+
+	r := variance / (s.config.LevelNoise + 2)
+	return r, s.config.LevelNoise * r, s.config.SlopeNoise * r
+}
+
+// step predicts one candle ahead and corrects with the observed close
+func (f *filter) step(observed, r, qLevel, qSlope float64) {
+	// Predict: level += slope, P = F·P·Fᵀ + Q
+	f.level += f.slope
+	p00 := f.p00 + 2*f.p01 + f.p11 + qLevel
+	p01 := f.p01 + f.p11
+	p11 := f.p11 + qSlope
+
+	// Correct with the observation of the level
+	innovation := observed - f.level
+	s := p00 + r
+	k0, k1 := p00/s, p01/s
+	f.level += k0 * innovation
+	f.slope += k1 * innovation
+	f.p00 = p00 - k0*p00
+	f.p01 = p01 - k0*p01
+	f.p11 = p11 - k1*p01
+}
@@ -0,0 +1,223 @@
+package levels
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"otc-predictor/pkg/types"
+)
+
+// Kind is the role a level currently plays relative to price
+type Kind string
+
+const (
+	Support    Kind = "support"
+	Resistance Kind = "resistance"
+)
+
+// Source tells where a level came from
+type Source string
+
+const (
+	SourceSwing     Source = "swing"
+	SourceClassic   Source = "pivot_classic"
+	SourceCamarilla Source = "pivot_camarilla"
+	SourceFibonacci Source = "pivot_fibonacci"
+	SourceRound     Source = "round_number"
+)
+
+// Level is a support/resistance level with its interaction history
+type Level struct {
+	Price       float64    `json:"price"`
+	Kind        Kind       `json:"kind"`
+	Source      Source     `json:"source"`
+	Label       string     `json:"label,omitempty"`  // PP, R1, S3...
+	Swings      int        `json:"swings,omitempty"` // Swing points merged into the level
+	Touches     int        `json:"touches"`
+	Breaks      int        `json:"breaks"`
+	Flips       int        `json:"flips"` // Broken, then held from the other side
+	Strength    float64    `json:"strength"`
+	Distance    float64    `json:"distance"` // (price - level) / level, positive above the level
+	FirstSeen   time.Time  `json:"first_seen"`
+	LastTouched *time.Time `json:"last_touched,omitempty"`
+}
+
+// sourceBase is the starting strength of each level source
+var sourceBase = map[Source]float64{
+	SourceSwing:     0.20,
+	SourceClassic:   0.25,
+	SourceCamarilla: 0.20,
+	SourceFibonacci: 0.20,
+	SourceRound:     0.15,
+}
+
+// score rates a level from its source and history
+func (l Level) score() float64 {
+	strength := sourceBase[l.Source]
+	if l.Label == "PP" {
+		strength += 0.10
+	}
+
+	strength += 0.10 * math.Min(float64(l.Swings), 4)
+	strength += 0.08 * math.Min(float64(l.Touches), 5)
+	strength += 0.10 * float64(l.Flips)
+	strength -= 0.05 * float64(l.Breaks)
+
+	return math.Max(0, math.Min(1, strength))
+}
+
+// swingPoint is a local high or low
+type swingPoint struct {
+	price float64
+	time  time.Time
+}
+
+// cluster is a group of swing points at roughly the same price
+type cluster struct {
+	price   float64
+	members int
+	last    time.Time
+}
+
+// findSwings returns candle highs and lows that are extremes of a
+// window candles on either side
+func findSwings(candles []types.Candle, window int) []swingPoint {
+	points := []swingPoint{}
+	if window < 1 {
+		return points
+	}
+
+	for i := window; i < len(candles)-window; i++ {
+		isHigh := true
+		isLow := true
+		for j := i - window; j <= i+window; j++ {
+			if j == i {
+				continue
+			}
+			if candles[j].High > candles[i].High {
+				isHigh = false
+			}
+			if candles[j].Low < candles[i].Low {
+				isLow = false
+			}
+		}
+
+		if isHigh {
+			points = append(points, swingPoint{price: candles[i].High, time: candles[i].Timestamp})
+		}
+		if isLow {
+			points = append(points, swingPoint{price: candles[i].Low, time: candles[i].Timestamp})
+		}
+	}
+
+	return points
+}
+
+// clusterSwings merges swing points lying within tolerance (fraction of price)
+// of a running cluster mean, keeping the maxLevels best-supported clusters
+func clusterSwings(points []swingPoint, tolerance float64, maxLevels int) []cluster {
+	if len(points) == 0 {
+		return nil
+	}
+
+	sorted := append([]swingPoint{}, points...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].price < sorted[j].price })
+
+	clusters := []cluster{}
+	sum := 0.0
+	for _, p := range sorted {
+		if n := len(clusters); n > 0 {
+			c := &clusters[n-1]
+			if math.Abs(p.price-c.price)/c.price <= tolerance {
+				sum += p.price
+				c.members++
+				c.price = sum / float64(c.members)
+				if p.time.After(c.last) {
+					c.last = p.time
+				}
+				continue
+			}
+		}
+
+		sum = p.price
+		clusters = append(clusters, cluster{price: p.price, members: 1, last: p.time})
+	}
+
+	// Most touched first, most recent breaks ties
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].members != clusters[j].members {
+			return clusters[i].members > clusters[j].members
+		}
+		return clusters[i].last.After(clusters[j].last)
+	})
+
+	if maxLevels > 0 && len(clusters) > maxLevels {
+		clusters = clusters[:maxLevels]
+	}
+
+	return clusters
+}
+
+// tracker follows price around one level and records touches, breaks and flips.
+// Price enters the zone within tolerance and leaves it beyond 1.5x the
+// tolerance, so chop around a level counts as one touch.
+type tracker struct {
+	Level
+	side        int // +1 price above the level, -1 below
+	inZone      bool
+	pendingFlip bool // broken and not yet retested from the new side
+}
+
+// observe feeds one price into the tracker
+func (t *tracker) observe(price float64, at time.Time, tolerance float64) {
+	distance := (price - t.Price) / t.Price
+
+	if math.Abs(distance) <= tolerance {
+		if !t.inZone {
+			t.inZone = true
+			t.Touches++
+			touched := at
+			t.LastTouched = &touched
+		}
+		return
+	}
+
+	if t.inZone && math.Abs(distance) <= tolerance*1.5 {
+		return
+	}
+
+	wasInZone := t.inZone
+	t.inZone = false
+
+	side := 1
+	if distance < 0 {
+		side = -1
+	}
+
+	switch {
+	case t.side == 0:
+		t.side = side
+	case side != t.side:
+		t.Breaks++
+		t.side = side
+		t.pendingFlip = true
+	case wasInZone && t.pendingFlip:
+		t.Flips++
+		t.pendingFlip = false
+	}
+}
+
+// snapshot returns the level as seen from the current price
+func (t *tracker) snapshot(price float64) Level {
+	level := t.Level
+	level.Kind = Support
+	if t.side < 0 || (t.side == 0 && price < t.Price) {
+		level.Kind = Resistance
+	}
+	level.Strength = level.score()
+	if t.Price != 0 {
+		level.Distance = (price - t.Price) / t.Price
+	}
+	return level
+}
@@ -0,0 +1,143 @@
+package levels
+
+import (
+	"math"
+	"time"
+
+	"otc-predictor/pkg/types"
+)
+
+// SessionRange is the high/low/close of the session pivots are built from
+type SessionRange struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	High  float64   `json:"high"`
+	Low   float64   `json:"low"`
+	Close float64   `json:"close"`
+}
+
+// pivotLevel is a pivot price before it gets a tracker
+type pivotLevel struct {
+	source Source
+	label  string
+	price  float64
+}
+
+// previousSession returns the last complete session of the given length.
+// With a short archive the part before the current session is used instead.
+func previousSession(ticks []types.Tick, period time.Duration) (SessionRange, bool) {
+	if len(ticks) == 0 || period <= 0 {
+		return SessionRange{}, false
+	}
+
+	currentStart := ticks[len(ticks)-1].Timestamp.Truncate(period)
+	previousStart := currentStart.Add(-period)
+
+	session := SessionRange{Start: previousStart, End: currentStart}
+	found := false
+	for _, tick := range ticks {
+		if tick.Timestamp.Before(previousStart) || !tick.Timestamp.Before(currentStart) {
+			continue
+		}
+		session.include(tick.Price, found)
+		found = true
+	}
+
+	if !found {
+		for _, tick := range ticks {
+			if !tick.Timestamp.Before(currentStart) {
+				break
+			}
+			if !found {
+				session.Start = tick.Timestamp
+			}
+			session.include(tick.Price, found)
+			found = true
+		}
+	}
+
+	return session, found
+}
+
+// include extends the session with a price
+func (r *SessionRange) include(price float64, started bool) {
+	if !started {
+		r.High, r.Low = price, price
+	}
+	r.High = math.Max(r.High, price)
+	r.Low = math.Min(r.Low, price)
+	r.Close = price
+}
+
+// classicPivots returns floor-trader pivot, three resistances and three supports
+func classicPivots(r SessionRange) []pivotLevel {
+	pp := (r.High + r.Low + r.Close) / 3
+	span := r.High - r.Low
+
+	return []pivotLevel{
+		{SourceClassic, "PP", pp},
+		{SourceClassic, "R1", 2*pp - r.Low},
+		{SourceClassic, "S1", 2*pp - r.High},
+		{SourceClassic, "R2", pp + span},
+		{SourceClassic, "S2", pp - span},
+		{SourceClassic, "R3", r.High + 2*(pp-r.Low)},
+		{SourceClassic, "S3", r.Low - 2*(r.High-pp)},
+	}
+}
+
+// camarillaPivots returns Camarilla levels, tightly spaced around the close
+func camarillaPivots(r SessionRange) []pivotLevel {
+	span := (r.High - r.Low) * 1.1
+
+	return []pivotLevel{
+		{SourceCamarilla, "R1", r.Close + span/12},
+		{SourceCamarilla, "S1", r.Close - span/12},
+		{SourceCamarilla, "R2", r.Close + span/6},
+		{SourceCamarilla, "S2", r.Close - span/6},
+		{SourceCamarilla, "R3", r.Close + span/4},
+		{SourceCamarilla, "S3", r.Close - span/4},
+		{SourceCamarilla, "R4", r.Close + span/2},
+		{SourceCamarilla, "S4", r.Close - span/2},
+	}
+}
+
+// fibonacciPivots returns pivot +/- Fibonacci fractions of the session range
+func fibonacciPivots(r SessionRange) []pivotLevel {
+	pp := (r.High + r.Low + r.Close) / 3
+	span := r.High - r.Low
+
+	return []pivotLevel{
+		{SourceFibonacci, "R1", pp + 0.382*span},
+		{SourceFibonacci, "S1", pp - 0.382*span},
+		{SourceFibonacci, "R2", pp + 0.618*span},
+		{SourceFibonacci, "S2", pp - 0.618*span},
+		{SourceFibonacci, "R3", pp + span},
+		{SourceFibonacci, "S3", pp - span},
+	}
+}
+
+// roundNumbers returns the nearest count round prices on each side.
+// The step is two orders of magnitude below the price (1.0834 -> 0.01,
+// 152.3 -> 1, 452310 -> 1000), i.e. the 100-pip figures on forex majors.
+func roundNumbers(price float64, count int) []float64 {
+	if price <= 0 || count < 1 {
+		return nil
+	}
+
+	step := math.Pow(10, math.Floor(math.Log10(price))-2)
+	base := math.Floor(price / step)
+
+	rounds := make([]float64, 0, count*2)
+	for i := 0; i < count; i++ {
+		below := (base - float64(i)) * step
+		above := (base + float64(i) + 1) * step
+		rounds = append(rounds, roundTo(below, step), roundTo(above, step))
+	}
+
+	return rounds
+}
+
+// roundTo removes float noise from a multiple of step
+func roundTo(value, step float64) float64 {
+	return math.Round(value/step) * step
+}
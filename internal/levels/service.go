@@ -0,0 +1,285 @@
+package levels
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"otc-predictor/internal/candles"
+	"otc-predictor/internal/indicators"
+	"otc-predictor/internal/storage"
+	"otc-predictor/pkg/types"
+)
+
+// Service maintains support/resistance levels per market from the tick archive.
+// Levels persist between refreshes so touches, breaks and flips accumulate
+// over time instead of being rebuilt from a short lookback on every call.
+type Service struct {
+	storage    *storage.MemoryStorage
+	config     types.LevelsConfig
+	marketType func(string) string
+	markets    map[string]*marketLevels
+	mu         sync.RWMutex
+}
+
+// marketLevels is the tracked state of one market
+type marketLevels struct {
+	trackers   map[string]*tracker
+	session    SessionRange
+	hasSession bool
+	tolerance  float64
+	price      float64
+	lastTick   time.Time
+	updated    time.Time
+}
+
+// Snapshot is the current set of levels for a market
+type Snapshot struct {
+	Market    string        `json:"market"`
+	Price     float64       `json:"price"`
+	Tolerance float64       `json:"tolerance"` // Zone half-width as a fraction of price
+	Session   *SessionRange `json:"pivot_session,omitempty"`
+	Levels    []Level       `json:"levels"`
+	Updated   time.Time     `json:"updated"`
+}
+
+// candidate is a level wanted by the current refresh
+type candidate struct {
+	id     string
+	source Source
+	label  string
+	price  float64
+	swings int
+	since  time.Time
+}
+
+// NewService creates a level service. marketType maps a market symbol to
+// "forex", "volatility" or "crash_boom".
+func NewService(store *storage.MemoryStorage, config types.LevelsConfig, marketType func(string) string) *Service {
+	return &Service{
+		storage:    store,
+		config:     config,
+		marketType: marketType,
+		markets:    make(map[string]*marketLevels),
+	}
+}
+
+// Refresh updates levels for all active markets
+func (s *Service) Refresh() {
+	for _, market := range s.storage.GetActiveMarkets() {
+		s.RefreshMarket(market)
+	}
+}
+
+// RefreshMarket rebuilds the candidate levels of a market and feeds new ticks
+// to every tracked level
+func (s *Service) RefreshMarket(market string) {
+	ticks := s.storage.GetArchiveTicks(market)
+	if len(ticks) == 0 {
+		return
+	}
+
+	// Swings come from the longest timeframe's candles
+	tfConfig := candles.GetTimeframeConfig(3600, s.marketType(market))
+	candleData := candles.TicksToCandles(ticks, tfConfig.CandlePeriod)
+	price := ticks[len(ticks)-1].Price
+	tolerance := s.zoneTolerance(candleData, price)
+
+	session, hasSession := previousSession(ticks, time.Duration(s.config.PivotPeriodMinutes)*time.Minute)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, exists := s.markets[market]
+	if !exists {
+		state = &marketLevels{trackers: make(map[string]*tracker)}
+		s.markets[market] = state
+	}
+
+	// Advance existing levels with ticks since the last refresh
+	newTicks := ticksAfter(ticks, state.lastTick)
+	for _, t := range state.trackers {
+		for _, tick := range newTicks {
+			t.observe(tick.Price, tick.Timestamp, tolerance)
+		}
+	}
+
+	wanted := s.candidates(state, candleData, ticks, session, hasSession, price, tolerance)
+
+	keep := make(map[string]bool, len(wanted))
+	for _, c := range wanted {
+		keep[c.id] = true
+
+		if t, ok := state.trackers[c.id]; ok && (c.source == SourceSwing || t.Price == c.price) {
+			t.Price = c.price
+			t.Swings = c.swings
+			continue
+		}
+
+		// New level: replay the archive so it starts with its real history
+		t := &tracker{Level: Level{
+			Price:     c.price,
+			Source:    c.source,
+			Label:     c.label,
+			Swings:    c.swings,
+			FirstSeen: c.since,
+		}}
+		for _, tick := range ticksAfter(ticks, c.since.Add(-time.Nanosecond)) {
+			t.observe(tick.Price, tick.Timestamp, tolerance)
+		}
+		state.trackers[c.id] = t
+	}
+
+	for id := range state.trackers {
+		if !keep[id] {
+			delete(state.trackers, id)
+		}
+	}
+
+	state.session = session
+	state.hasSession = hasSession
+	state.tolerance = tolerance
+	state.price = price
+	state.lastTick = ticks[len(ticks)-1].Timestamp
+	state.updated = time.Now()
+}
+
+// candidates lists the swing clusters, pivots and round numbers for a refresh.
+// Swing clusters reuse the id of a tracked swing level within tolerance so
+// their history survives small shifts of the cluster mean.
+func (s *Service) candidates(state *marketLevels, candleData []types.Candle, ticks []types.Tick, session SessionRange, hasSession bool, price, tolerance float64) []candidate {
+	wanted := []candidate{}
+	archiveStart := ticks[0].Timestamp
+
+	claimed := make(map[string]bool)
+	swings := findSwings(candleData, s.config.SwingWindow)
+	for _, c := range clusterSwings(swings, tolerance, s.config.MaxLevels) {
+		id := ""
+		for existingID, t := range state.trackers {
+			if t.Source == SourceSwing && !claimed[existingID] && math.Abs(t.Price-c.price)/c.price <= tolerance {
+				id = existingID
+				break
+			}
+		}
+		if id == "" {
+			id = fmt.Sprintf("swing:%g", c.price)
+		}
+		claimed[id] = true
+
+		wanted = append(wanted, candidate{
+			id:     id,
+			source: SourceSwing,
+			price:  c.price,
+			swings: c.members,
+			since:  archiveStart,
+		})
+	}
+
+	if hasSession && session.High > session.Low {
+		pivots := append(classicPivots(session), camarillaPivots(session)...)
+		pivots = append(pivots, fibonacciPivots(session)...)
+		for _, p := range pivots {
+			if p.price <= 0 {
+				continue
+			}
+			wanted = append(wanted, candidate{
+				id:     string(p.source) + ":" + p.label,
+				source: p.source,
+				label:  p.label,
+				price:  p.price,
+				since:  session.End,
+			})
+		}
+	}
+
+	if s.config.RoundNumbers {
+		for _, r := range roundNumbers(price, 2) {
+			wanted = append(wanted, candidate{
+				id:     fmt.Sprintf("round:%g", r),
+				source: SourceRound,
+				price:  r,
+				since:  archiveStart,
+			})
+		}
+	}
+
+	return wanted
+}
+
+// zoneTolerance widens the configured tolerance to a quarter ATR on fast markets
+func (s *Service) zoneTolerance(candleData []types.Candle, price float64) float64 {
+	tolerance := s.config.ClusterTolerance
+	if price > 0 {
+		atr := indicators.CalculateATR(candleData, 14)
+		tolerance = math.Max(tolerance, 0.25*atr/price)
+	}
+	return tolerance
+}
+
+// GetLevels returns the levels of a market sorted by price
+func (s *Service) GetLevels(market string) (Snapshot, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	state, exists := s.markets[market]
+	if !exists {
+		return Snapshot{Market: market, Levels: []Level{}}, false
+	}
+
+	snapshot := Snapshot{
+		Market:    market,
+		Price:     state.price,
+		Tolerance: state.tolerance,
+		Levels:    make([]Level, 0, len(state.trackers)),
+		Updated:   state.updated,
+	}
+	if state.hasSession {
+		session := state.session
+		snapshot.Session = &session
+	}
+
+	for _, t := range state.trackers {
+		snapshot.Levels = append(snapshot.Levels, t.snapshot(state.price))
+	}
+	sort.Slice(snapshot.Levels, func(i, j int) bool {
+		return snapshot.Levels[i].Price < snapshot.Levels[j].Price
+	})
+
+	return snapshot, true
+}
+
+// Near returns the supports and resistances whose zone contains price,
+// strongest first
+func (snap Snapshot) Near(price float64) ([]Level, []Level) {
+	support := []Level{}
+	resistance := []Level{}
+
+	for _, level := range snap.Levels {
+		if math.Abs(price-level.Price)/level.Price > snap.Tolerance {
+			continue
+		}
+		if level.Kind == Support {
+			support = append(support, level)
+		} else {
+			resistance = append(resistance, level)
+		}
+	}
+
+	byStrength := func(levels []Level) {
+		sort.Slice(levels, func(i, j int) bool { return levels[i].Strength > levels[j].Strength })
+	}
+	byStrength(support)
+	byStrength(resistance)
+
+	return support, resistance
+}
+
+// ticksAfter returns the ticks strictly after t (ticks are time ordered)
+func ticksAfter(ticks []types.Tick, t time.Time) []types.Tick {
+	idx := sort.Search(len(ticks), func(i int) bool {
+		return ticks[i].Timestamp.After(t)
+	})
+	return ticks[idx:]
+}
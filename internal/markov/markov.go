@@ -0,0 +1,379 @@
+package markov
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"otc-predictor/internal/candles"
+	"otc-predictor/internal/storage"
+	"otc-predictor/pkg/types"
+)
+
+// Service learns how the direction of each synthetic tick depends on the
+// directions of the ticks before it. For every order k up to MaxOrder the
+// archive is tallied into 2^k contexts (the last k directions) by next
+// direction, and a chi-square test of independence checks the tallies against
+// an i.i.d. coin. Only an order that rejects independence at Significance,
+// Bonferroni-corrected over the orders tried, is used for forecasts.
+// Flat ticks are skipped.
+type Service struct {
+	storage    *storage.MemoryStorage
+	config     types.MarkovConfig
+	marketType func(string) string
+	models     map[string]Model
+	mu         sync.RWMutex
+}
+
+// Model is the fitted chain of one market
+type Model struct {
+	Market      string    `json:"market"`
+	Ticks       int       `json:"ticks"` // Up or down moves tallied
+	UpRate      float64   `json:"up_rate"`
+	TickSeconds float64   `json:"tick_seconds"` // Feed seconds per up or down move
+	Orders      []Order   `json:"orders"`
+	Selected    int       `json:"selected_order"` // 0 when no order is significant
+	Runs        []Run     `json:"runs"`
+	Updated     time.Time `json:"updated"`
+}
+
+// Order is the chain of one order and its test against independence
+type Order struct {
+	Order       int                   `json:"order"`
+	Transitions map[string]Transition `json:"transitions"` // By context, oldest first, e.g. "UUD"
+	ChiSquare   float64               `json:"chi_square"`
+	DF          int                   `json:"df"`
+	PValue      float64               `json:"p_value"`
+	Significant bool                  `json:"significant"`
+}
+
+// Transition is the next-tick tally after one context
+type Transition struct {
+	Up            int     `json:"up"`
+	Down          int     `json:"down"`
+	ProbabilityUp float64 `json:"probability_up"`
+}
+
+// Run is how often a run of same-direction ticks of a length continues
+type Run struct {
+	Length   int     `json:"length"`
+	Count    int     `json:"count"`    // Runs that reached this length
+	Continue float64 `json:"continue"` // Share that went on to Length+1
+}
+
+// Forecast is the chain's view of the next duration from the latest ticks
+type Forecast struct {
+	Order         int     `json:"order"`
+	Context       string  `json:"context"`
+	NextUp        float64 `json:"next_up"`        // Probability the next tick rises
+	Ticks         int     `json:"ticks"`          // Ticks in the duration
+	ExpectedSteps float64 `json:"expected_steps"` // Expected up minus down ticks
+	ProbabilityUp float64 `json:"probability_up"` // Normal approximation of ending higher
+	ChiSquare     float64 `json:"chi_square"`
+	DF            int     `json:"df"`
+	PValue        float64 `json:"p_value"`
+	Actionable    bool    `json:"actionable"` // ProbabilityUp is at least MinEffect from 0.5
+}
+
+// NewService creates a Markov service. marketType maps a market symbol to
+// "forex", "volatility" or "crash_boom".
+func NewService(store *storage.MemoryStorage, config types.MarkovConfig, marketType func(string) string) *Service {
+	return &Service{
+		storage:    store,
+		config:     config,
+		marketType: marketType,
+		models:     make(map[string]Model),
+	}
+}
+
+// Refresh refits the chain of every active synthetic index
+func (s *Service) Refresh() {
+	for _, market := range s.storage.GetActiveMarkets() {
+		if s.marketType(market) != "forex" {
+			s.RefreshMarket(market)
+		}
+	}
+}
+
+// RefreshMarket refits one market from its archive. Markets with fewer than
+// MinTicks archived ticks keep their previous model.
+func (s *Service) RefreshMarket(market string) {
+	ticks := s.storage.GetArchiveTicks(market)
+	if len(ticks) < s.config.MinTicks || len(ticks) < 2 {
+		return
+	}
+
+	model := fit(directions(ticks), s.config)
+	model.Market = market
+	model.TickSeconds = moveSeconds(ticks)
+	model.Updated = time.Now()
+
+	s.mu.Lock()
+	s.models[market] = model
+	s.mu.Unlock()
+}
+
+// Model returns the fitted model of a market
+func (s *Service) Model(market string) (Model, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	model, ok := s.models[market]
+	return model, ok
+}
+
+// Forecast applies the selected order to the latest live ticks over a
+// duration in seconds. Returns false when no order is significant. On a long
+// archive a negligible dependence still tests significant, so the forecast
+// is only actionable when it moves P(up) at least MinEffect from a coin flip.
+func (s *Service) Forecast(market string, duration int) (Forecast, bool) {
+	model, ok := s.Model(market)
+	if !ok || model.Selected == 0 {
+		return Forecast{}, false
+	}
+	forecast, ok := model.Forecast(directions(s.storage.GetAllTicks(market)), duration)
+	forecast.Actionable = ok && math.Abs(forecast.ProbabilityUp-0.5) >= s.config.MinEffect
+	return forecast, ok
+}
+
+// Forecast applies the selected order to the latest directions. The
+// distribution over contexts is stepped forward one tick at a time to get
+// the expected up minus down count, and ending higher is approximated as
+// normal around it with the i.i.d. spread √n.
+func (m Model) Forecast(recent []int, duration int) (Forecast, bool) {
+	if m.Selected == 0 || len(recent) < m.Selected || m.TickSeconds <= 0 {
+		return Forecast{}, false
+	}
+	order := m.Orders[m.Selected-1]
+	n := int(math.Max(1, math.Round(float64(duration)/m.TickSeconds)))
+
+	context := contextKey(recent[len(recent)-m.Selected:])
+	forecast := Forecast{
+		Order:     order.Order,
+		Context:   context,
+		NextUp:    order.probabilityUp(context, m.UpRate),
+		Ticks:     n,
+		ChiSquare: order.ChiSquare,
+		DF:        order.DF,
+		PValue:    order.PValue,
+	}
+
+	dist := map[string]float64{context: 1}
+	for step := 0; step < n; step++ {
+		next := make(map[string]float64, len(dist)*2)
+		drift := 0.0
+		for ctx, weight := range dist {
+			up := order.probabilityUp(ctx, m.UpRate)
+			drift += weight * (2*up - 1)
+			next[ctx[1:]+"U"] += weight * up
+			next[ctx[1:]+"D"] += weight * (1 - up)
+		}
+		forecast.ExpectedSteps += drift
+		dist = next
+	}
+
+	forecast.ProbabilityUp = 0.5 * math.Erfc(-forecast.ExpectedSteps/math.Sqrt(float64(n))/math.Sqrt2)
+	return forecast, true
+}
+
+// probabilityUp returns the chance of an up tick after a context, or the
+// overall rate for a context never seen
+func (o Order) probabilityUp(context string, fallback float64) float64 {
+	if t, ok := o.Transitions[context]; ok && t.Up+t.Down > 0 {
+		return t.ProbabilityUp
+	}
+	return fallback
+}
+
+// fit tallies every order and the run lengths
+func fit(dirs []int, config types.MarkovConfig) Model {
+	model := Model{Ticks: len(dirs), Orders: []Order{}, Runs: runs(dirs, config.MaxOrder+2)}
+	ups := 0
+	for _, d := range dirs {
+		if d > 0 {
+			ups++
+		}
+	}
+	if len(dirs) > 0 {
+		model.UpRate = float64(ups) / float64(len(dirs))
+	}
+
+	alpha := config.Significance / float64(config.MaxOrder)
+	best := 1.0
+	for k := 1; k <= config.MaxOrder; k++ {
+		order := tally(dirs, k)
+		order.Significant = order.DF > 0 && order.PValue < alpha
+		if order.Significant && order.PValue < best {
+			best = order.PValue
+			model.Selected = k
+		}
+		model.Orders = append(model.Orders, order)
+	}
+	return model
+}
+
+// tally counts next directions by the previous k and runs the chi-square
+// test of independence of the 2^k x 2 table
+func tally(dirs []int, k int) Order {
+	order := Order{Order: k, Transitions: make(map[string]Transition)}
+	for i := k; i < len(dirs); i++ {
+		key := contextKey(dirs[i-k : i])
+		t := order.Transitions[key]
+		if dirs[i] > 0 {
+			t.Up++
+		} else {
+			t.Down++
+		}
+		order.Transitions[key] = t
+	}
+
+	var total, ups float64
+	for key, t := range order.Transitions {
+		t.ProbabilityUp = float64(t.Up) / float64(t.Up+t.Down)
+		order.Transitions[key] = t
+		total += float64(t.Up + t.Down)
+		ups += float64(t.Up)
+	}
+	if total == 0 || ups == 0 || ups == total {
+		order.PValue = 1
+		return order
+	}
+
+	for _, t := range order.Transitions {
+		row := float64(t.Up + t.Down)
+		expectedUp := row * ups / total
+		expectedDown := row * (total - ups) / total
+		order.ChiSquare += sq(float64(t.Up)-expectedUp)/expectedUp + sq(float64(t.Down)-expectedDown)/expectedDown
+	}
+	order.DF = len(order.Transitions) - 1
+	order.PValue = chiSquareSurvival(order.ChiSquare, order.DF)
+	return order
+}
+
+// runs measures, for run lengths 1..maxLength, how often a run that reached
+// the length went on to the next one
+func runs(dirs []int, maxLength int) []Run {
+	reached := make([]int, maxLength+2)
+	length := 0
+	for i, d := range dirs {
+		if i > 0 && d == dirs[i-1] {
+			length++
+		} else {
+			length = 1
+		}
+		if length <= maxLength+1 {
+			reached[length]++
+		}
+	}
+
+	stats := make([]Run, 0, maxLength)
+	for l := 1; l <= maxLength; l++ {
+		run := Run{Length: l, Count: reached[l]}
+		if reached[l] > 0 {
+			run.Continue = float64(reached[l+1]) / float64(reached[l])
+		}
+		stats = append(stats, run)
+	}
+	return stats
+}
+
+// directions maps ticks to +1 (up) and -1 (down), skipping flat ticks
+func directions(ticks []types.Tick) []int {
+	dirs := make([]int, 0, len(ticks))
+	for i := 1; i < len(ticks); i++ {
+		switch {
+		case ticks[i].Price > ticks[i-1].Price:
+			dirs = append(dirs, 1)
+		case ticks[i].Price < ticks[i-1].Price:
+			dirs = append(dirs, -1)
+		}
+	}
+	return dirs
+}
+
+// moveSeconds is the feed time per up or down move: the seconds between
+// ticks inside the feed over the moves among them. Feed gaps would stretch
+// it, and flat ticks take time without a move the chain counts.
+func moveSeconds(ticks []types.Tick) float64 {
+	inFeed, _ := candles.FeedSteps(ticks)
+	var seconds float64
+	moves := 0
+	for i := 1; i < len(ticks); i++ {
+		if !inFeed[i] {
+			continue
+		}
+		seconds += ticks[i].Timestamp.Sub(ticks[i-1].Timestamp).Seconds()
+		if ticks[i].Price != ticks[i-1].Price {
+			moves++
+		}
+	}
+	if moves == 0 {
+		return 0
+	}
+	return seconds / float64(moves)
+}
+
+// contextKey spells directions as U and D, oldest first
+func contextKey(dirs []int) string {
+	var b strings.Builder
+	for _, d := range dirs {
+		if d > 0 {
+			b.WriteByte('U')
+		} else {
+			b.WriteByte('D')
+		}
+	}
+	return b.String()
+}
+
+func sq(x float64) float64 { return x * x }
+
+// chiSquareSurvival is P(X > x) for a chi-square variable with df degrees
+// of freedom, the regularized upper incomplete gamma Q(df/2, x/2)
+func chiSquareSurvival(x float64, df int) float64 {
+	if x <= 0 || df <= 0 {
+		return 1
+	}
+	a, z := float64(df)/2, x/2
+	lgamma, _ := math.Lgamma(a)
+
+	// Series for the lower function below a+1, continued fraction above
+	if z < a+1 {
+		sum, term := 1/a, 1/a
+		for n := 1; n < 500; n++ {
+			term *= z / (a + float64(n))
+			sum += term
+			if term < sum*1e-15 {
+				break
+			}
+		}
+		return math.Max(0, 1-sum*math.Exp(-z+a*math.Log(z)-lgamma))
+	}
+
+	// Lentz's method
+	const tiny = 1e-300
+	b := z + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for n := 1; n < 500; n++ {
+		an := -float64(n) * (float64(n) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < 1e-15 {
+			break
+		}
+	}
+	return math.Exp(-z+a*math.Log(z)-lgamma) * h
+}
@@ -0,0 +1,156 @@
+package markov
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"otc-predictor/pkg/types"
+)
+
+func TestChiSquareSurvival(t *testing.T) {
+	// Upper-tail critical values from standard χ² tables
+	tests := []struct {
+		x    float64
+		df   int
+		want float64
+	}{
+		{3.841459, 1, 0.05},
+		{6.634897, 1, 0.01},
+		{10.827566, 1, 0.001},
+		{5.991465, 2, 0.05},
+		{9.210340, 2, 0.01},
+		{7.814728, 3, 0.05},
+		{11.344867, 3, 0.01},
+		{14.067140, 7, 0.05},
+		{18.475307, 7, 0.01},
+		{24.995790, 15, 0.05},
+		{1.063623, 4, 0.90}, // Series branch, below a+1
+		{0, 3, 1},
+		{-1, 3, 1},
+		{5, 0, 1},
+	}
+
+	for _, tt := range tests {
+		if got := chiSquareSurvival(tt.x, tt.df); math.Abs(got-tt.want) > 1e-6 {
+			t.Errorf("chiSquareSurvival(%v, %d) = %v, want %v", tt.x, tt.df, got, tt.want)
+		}
+	}
+
+	// With two degrees of freedom the survival is exactly exp(-x/2)
+	for _, x := range []float64{0.5, 2, 8, 40, 100} {
+		want := math.Exp(-x / 2)
+		if got := chiSquareSurvival(x, 2); math.Abs(got-want) > want*1e-9 {
+			t.Errorf("chiSquareSurvival(%v, 2) = %v, want %v", x, got, want)
+		}
+	}
+}
+
+// persistent is an order-1 chain where a tick repeats the last direction 60%
+// of the time
+func persistent() Model {
+	return Model{
+		UpRate:      0.5,
+		TickSeconds: 1,
+		Selected:    1,
+		Orders: []Order{{
+			Order: 1,
+			Transitions: map[string]Transition{
+				"U": {Up: 60, Down: 40, ProbabilityUp: 0.6},
+				"D": {Up: 40, Down: 60, ProbabilityUp: 0.4},
+			},
+		}},
+	}
+}
+
+func TestModelForecast(t *testing.T) {
+	// After an up tick, two ticks ahead:
+	//   tick 1: P(up) = 0.6, drift 2·0.6-1 = 0.2
+	//   tick 2: 0.6·(2·0.6-1) + 0.4·(2·0.4-1) = 0.04
+	// Expected steps 0.24, P(end higher) = Φ(0.24/√2)
+	forecast, ok := persistent().Forecast([]int{-1, 1}, 2)
+	if !ok {
+		t.Fatal("expected a forecast")
+	}
+	if forecast.Context != "U" || forecast.Ticks != 2 || forecast.NextUp != 0.6 {
+		t.Errorf("forecast = %+v, want context U, 2 ticks, next up 0.6", forecast)
+	}
+	if math.Abs(forecast.ExpectedSteps-0.24) > 1e-12 {
+		t.Errorf("ExpectedSteps = %v, want 0.24", forecast.ExpectedSteps)
+	}
+	if math.Abs(forecast.ProbabilityUp-0.56737917590996) > 1e-9 {
+		t.Errorf("ProbabilityUp = %v, want 0.567379", forecast.ProbabilityUp)
+	}
+
+	// The chain is symmetric, so after a down tick the forecast mirrors
+	down, _ := persistent().Forecast([]int{1, -1}, 2)
+	if math.Abs(down.ExpectedSteps+0.24) > 1e-12 || math.Abs(down.ProbabilityUp+forecast.ProbabilityUp-1) > 1e-12 {
+		t.Errorf("down forecast = %+v, want the mirror of %+v", down, forecast)
+	}
+
+	// Over many ticks the chain forgets its start: drift decays by 0.2 a tick
+	long, _ := persistent().Forecast([]int{1}, 100)
+	if math.Abs(long.ExpectedSteps-0.25) > 1e-9 {
+		t.Errorf("ExpectedSteps over 100 ticks = %v, want 0.2/(1-0.2) = 0.25", long.ExpectedSteps)
+	}
+}
+
+func TestModelForecastUnavailable(t *testing.T) {
+	independent := persistent()
+	independent.Selected = 0
+
+	noClock := persistent()
+	noClock.TickSeconds = 0
+
+	tests := []struct {
+		name   string
+		model  Model
+		recent []int
+	}{
+		{"no significant order", independent, []int{1}},
+		{"too few recent ticks", persistent(), nil},
+		{"unknown tick rate", noClock, []int{1}},
+	}
+	for _, tt := range tests {
+		if _, ok := tt.model.Forecast(tt.recent, 60); ok {
+			t.Errorf("%s: expected no forecast", tt.name)
+		}
+	}
+}
+
+func TestFit(t *testing.T) {
+	// Strictly alternating ticks: order 1 explains everything
+	dirs := make([]int, 400)
+	for i := range dirs {
+		dirs[i] = 1 - 2*(i%2)
+	}
+
+	model := fit(dirs, types.MarkovConfig{MaxOrder: 3, Significance: 0.01})
+	if model.Selected == 0 {
+		t.Fatalf("no order selected: %+v", model.Orders)
+	}
+	order := model.Orders[0]
+	if order.Transitions["U"].ProbabilityUp != 0 || order.Transitions["D"].ProbabilityUp != 1 {
+		t.Errorf("order-1 transitions = %+v, want U→D and D→U always", order.Transitions)
+	}
+	if order.DF != 1 || !order.Significant {
+		t.Errorf("order 1: df %d, significant %v", order.DF, order.Significant)
+	}
+}
+
+func TestMoveSeconds(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// One-second ticks with one flat tick and a ten-minute reconnect
+	offsets := []int{0, 1, 2, 3, 4, 604, 605, 606, 607, 608}
+	prices := []float64{100, 101, 101, 102, 101, 103, 104, 103, 104, 105}
+
+	ticks := make([]types.Tick, len(offsets))
+	for i := range ticks {
+		ticks[i] = types.Tick{Price: prices[i], Timestamp: start.Add(time.Duration(offsets[i]) * time.Second)}
+	}
+
+	// 8 in-feed seconds over 7 moves; the gap and the flat tick add no move
+	if got := moveSeconds(ticks); math.Abs(got-8.0/7) > 1e-12 {
+		t.Errorf("moveSeconds = %v, want %v", got, 8.0/7)
+	}
+}
@@ -0,0 +1,108 @@
+package meanreversion
+
+import (
+	"math"
+
+	"otc-predictor/pkg/types"
+)
+
+// DFCritical is the 5% Dickey-Fuller critical value for a regression with a
+// constant; AR(1) slopes whose unit-root t-statistic is above it can't be
+// told apart from a random walk
+const DFCritical = -2.86
+
+// MinZScore is how many equilibrium deviations price must sit from the
+// fitted mean before a reversion towards it is worth trading
+const MinZScore = 1.0
+
+// MaxSamples bounds how many recent candles a fit uses
+const MaxSamples = 200
+
+// Fit is an Ornstein-Uhlenbeck process fitted to candle closes through its
+// discrete form, the AR(1) regression x[t] = a + b*x[t-1] + e:
+// speed = -ln(b)/dt, mean = a/(1-b), equilibrium sigma = sd(e)/sqrt(1-b²)
+type Fit struct {
+	Period        int     `json:"period"` // Candle seconds
+	Samples       int     `json:"samples"`
+	AR            float64 `json:"ar"`        // b
+	DFStat        float64 `json:"df_stat"`   // (b-1)/se(b)
+	Mean          float64 `json:"mean"`      // Level price reverts to
+	Speed         float64 `json:"speed"`     // Reversion rate per second
+	HalfLife      float64 `json:"half_life"` // Seconds to close half the gap to the mean, 0 if not reverting
+	Sigma         float64 `json:"sigma"`     // Equilibrium deviation around the mean
+	ZScore        float64 `json:"z_score"`   // Latest close in equilibrium deviations from the mean
+	MeanReverting bool    `json:"mean_reverting"`
+}
+
+// FitCandles fits the closes of the last MaxSamples candles
+func FitCandles(candles []types.Candle, period int) Fit {
+	if len(candles) > MaxSamples {
+		candles = candles[len(candles)-MaxSamples:]
+	}
+	closes := make([]float64, len(candles))
+	for i, candle := range candles {
+		closes[i] = candle.Close
+	}
+	return FitSeries(closes, period)
+}
+
+// FitSeries fits evenly spaced values period seconds apart. The series is
+// mean reverting when 0 < b < 1 and the Dickey-Fuller statistic rejects a
+// unit root.
+func FitSeries(values []float64, period int) Fit {
+	fit := Fit{Period: period, Samples: len(values) - 1}
+	if fit.Samples < 10 || period <= 0 {
+		fit.Samples = max(0, fit.Samples)
+		return fit
+	}
+
+	// OLS of values[1:] on values[:-1]
+	n := float64(fit.Samples)
+	var sx, sy, sxx, sxy float64
+	for i := 1; i < len(values); i++ {
+		x, y := values[i-1], values[i]
+		sx += x
+		sy += y
+		sxx += x * x
+		sxy += x * y
+	}
+	varX := sxx - sx*sx/n
+	if varX <= 0 {
+		return fit
+	}
+	b := (sxy - sx*sy/n) / varX
+	a := (sy - b*sx) / n
+
+	var sse float64
+	for i := 1; i < len(values); i++ {
+		e := values[i] - a - b*values[i-1]
+		sse += e * e
+	}
+	residual := math.Sqrt(sse / (n - 2))
+
+	fit.AR = b
+	if se := residual / math.Sqrt(varX); se > 0 {
+		fit.DFStat = (b - 1) / se
+	}
+
+	if b <= 0 || b >= 1 {
+		return fit
+	}
+
+	fit.Mean = a / (1 - b)
+	fit.Speed = -math.Log(b) / float64(period)
+	fit.HalfLife = math.Ln2 / fit.Speed
+	fit.Sigma = residual / math.Sqrt(1-b*b)
+	if fit.Sigma > 0 {
+		fit.ZScore = (values[len(values)-1] - fit.Mean) / fit.Sigma
+	}
+	fit.MeanReverting = fit.DFStat < DFCritical
+
+	return fit
+}
+
+// Within reports whether the series is mean reverting with a half-life no
+// longer than duration seconds
+func (f Fit) Within(duration int) bool {
+	return f.MeanReverting && f.HalfLife > 0 && f.HalfLife <= float64(duration)
+}
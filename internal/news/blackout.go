@@ -0,0 +1,197 @@
+package news
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"otc-predictor/internal/sessions"
+	"otc-predictor/pkg/types"
+)
+
+// ReasonCode marks predictions blocked by a release
+const ReasonCode = "news_blackout"
+
+// Service holds the economic calendar and answers blackout queries.
+// The file is reloaded when it changes.
+type Service struct {
+	config  types.NewsConfig
+	events  []types.NewsEvent // Sorted by time
+	modTime time.Time
+	mu      sync.RWMutex
+}
+
+// NewService creates a blackout service and loads the calendar file.
+// A missing file leaves the calendar empty.
+func NewService(config types.NewsConfig) (*Service, error) {
+	s := &Service{config: config}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reload re-reads the calendar file if it changed since the last load
+func (s *Service) Reload() error {
+	if s.config.File == "" {
+		return nil
+	}
+
+	info, err := os.Stat(s.config.File)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("news calendar: %w", err)
+	}
+
+	s.mu.RLock()
+	unchanged := info.ModTime().Equal(s.modTime)
+	s.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	events, err := Load(s.config.File)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.events = events
+	s.modTime = info.ModTime()
+	s.mu.Unlock()
+	return nil
+}
+
+// Load reads a calendar from a .json file (array of events) or a CSV file
+// with columns time,currency,impact,title
+func Load(filename string) ([]types.NewsEvent, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("news calendar: %w", err)
+	}
+	defer file.Close()
+
+	var events []types.NewsEvent
+	if strings.EqualFold(filepath.Ext(filename), ".json") {
+		if err := json.NewDecoder(file).Decode(&events); err != nil {
+			return nil, fmt.Errorf("news calendar %s: %w", filename, err)
+		}
+	} else if events, err = readCSV(file); err != nil {
+		return nil, fmt.Errorf("news calendar %s: %w", filename, err)
+	}
+
+	for i := range events {
+		events[i].Currency = strings.ToUpper(strings.TrimSpace(events[i].Currency))
+		events[i].Impact = strings.ToLower(strings.TrimSpace(events[i].Impact))
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	return events, nil
+}
+
+// readCSV parses calendar rows. Times are RFC 3339 or "2006-01-02 15:04"
+// in UTC; # comments and a "time" header are skipped.
+func readCSV(r io.Reader) ([]types.NewsEvent, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var events []types.NewsEvent
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return events, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) < 3 {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("line %d: want time,currency,impact,title", line)
+		}
+		if strings.EqualFold(record[0], "time") {
+			continue
+		}
+
+		t, err := parseTime(record[0])
+		if err != nil {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		event := types.NewsEvent{Time: t, Currency: record[1], Impact: record[2]}
+		if len(record) > 3 {
+			event.Title = record[3]
+		}
+		events = append(events, event)
+	}
+}
+
+func parseTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02 15:04", value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("bad time %q (want RFC 3339 or 2006-01-02 15:04 UTC)", value)
+}
+
+// Blackout returns the release blocking a forex pair for a contract opened at
+// t and expiring duration later, if any. A release blocks pairs containing
+// its currency from Before minutes ahead of it until After minutes past it,
+// with windows set per impact; a contract is blocked when any part of its
+// life falls in a window.
+func (s *Service) Blackout(market string, t time.Time, duration time.Duration) (*types.NewsEvent, bool) {
+	pair := sessions.PairCurrencies(market)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, event := range s.events {
+		window, tracked := s.config.Windows[event.Impact]
+		if !tracked || (event.Currency != pair[0] && event.Currency != pair[1]) {
+			continue
+		}
+
+		start := event.Time.Add(-time.Duration(window.Before) * time.Minute)
+		end := event.Time.Add(time.Duration(window.After) * time.Minute)
+		if !t.Add(duration).Before(start) && t.Before(end) {
+			blocking := event
+			return &blocking, true
+		}
+	}
+	return nil, false
+}
+
+// Upcoming returns events from now until now+horizon, optionally for one
+// currency (empty matches all)
+func (s *Service) Upcoming(now time.Time, horizon time.Duration, currency string) []types.NewsEvent {
+	currency = strings.ToUpper(currency)
+	end := now.Add(horizon)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	upcoming := []types.NewsEvent{}
+	for _, event := range s.events {
+		if event.Time.Before(now) || event.Time.After(end) {
+			continue
+		}
+		if currency != "" && event.Currency != currency {
+			continue
+		}
+		upcoming = append(upcoming, event)
+	}
+	return upcoming
+}
@@ -0,0 +1,50 @@
+package payout
+
+import "otc-predictor/pkg/types"
+
+// Table resolves the payout for a contract from payout.rules
+type Table struct {
+	config types.PayoutConfig
+}
+
+// NewTable creates a payout table
+func NewTable(config types.PayoutConfig) *Table {
+	return &Table{config: config}
+}
+
+// Payout returns the profit per unit stake on a win for a market and
+// duration. The first matching rule wins; no match uses the default.
+func (t *Table) Payout(market, marketType string, duration int) float64 {
+	for _, rule := range t.config.Rules {
+		if rule.Market != "" && rule.Market != market {
+			continue
+		}
+		if rule.MarketType != "" && rule.MarketType != marketType {
+			continue
+		}
+		if rule.MinDuration > 0 && duration < rule.MinDuration {
+			continue
+		}
+		if rule.MaxDuration > 0 && duration > rule.MaxDuration {
+			continue
+		}
+		return rule.Payout
+	}
+	return t.config.Default
+}
+
+// MinEV is the expected value a prediction needs to be worth trading
+func (t *Table) MinEV() float64 {
+	return t.config.MinEV
+}
+
+// ExpectedValue is the expected profit per unit stake when a win pays
+// payout and a loss costs the stake
+func ExpectedValue(probability, payout float64) float64 {
+	return probability*payout - (1 - probability)
+}
+
+// BreakEven is the win probability at which expected value is zero
+func BreakEven(payout float64) float64 {
+	return 1 / (1 + payout)
+}
@@ -2,13 +2,29 @@ package predictor
 
 import (
 	"fmt"
+	"log"
 	"strings"
 	"sync"
 	"time"
 
+	"otc-predictor/internal/adaptive"
+	"otc-predictor/internal/calibration"
 	"otc-predictor/internal/candles"
+	"otc-predictor/internal/correlation"
+	"otc-predictor/internal/garch"
+	"otc-predictor/internal/hazard"
+	"otc-predictor/internal/indicators"
+	"otc-predictor/internal/kalman"
+	"otc-predictor/internal/levels"
+	"otc-predictor/internal/markov"
+	"otc-predictor/internal/meanreversion"
+	"otc-predictor/internal/news"
+	"otc-predictor/internal/payout"
+	"otc-predictor/internal/randomwalk"
+	"otc-predictor/internal/sessions"
 	"otc-predictor/internal/storage"
 	"otc-predictor/internal/strategy"
+	"otc-predictor/internal/strength"
 	"otc-predictor/internal/tracker"
 	"otc-predictor/pkg/types"
 
@@ -20,12 +36,27 @@ type Engine struct {
 	storage          *storage.MemoryStorage
 	strategy         *strategy.CombinedStrategy
 	tracker          *tracker.ResultTracker
+	levels           *levels.Service
+	hazard           *hazard.Service
+	randomWalk       *randomwalk.Service
+	strength         *strength.Meter
+	correlation      *correlation.Analyzer
+	garch            *garch.Service
+	kalman           *kalman.Service
+	markov           *markov.Service
+	adaptive         *adaptive.Weights
+	calibration      *calibration.Calibrator
+	payouts          *payout.Table
+	sessions         *sessions.Calendar
+	news             *news.Service
 	config           types.Config
 	cache            map[string]*CachedPrediction
 	cacheMu          sync.RWMutex
 	requestCounter   map[string]int
 	counterMu        sync.Mutex
 	lastCounterReset time.Time
+	awaiting         map[string]types.Prediction // Tracked predictions by ID, for learned consensus
+	awaitingMu       sync.Mutex
 }
 
 // CachedPrediction stores a recent prediction
@@ -36,15 +67,64 @@ type CachedPrediction struct {
 
 // NewEngine creates a new prediction engine
 func NewEngine(storage *storage.MemoryStorage, config types.Config, tracker *tracker.ResultTracker) *Engine {
-	return &Engine{
-		storage:          storage,
-		strategy:         strategy.NewCombinedStrategy(config.Strategy),
+	levelService := levels.NewService(storage, config.Levels, getMarketTypeHelper)
+	hazardService := hazard.NewService(storage, config.Hazard, getMarketTypeHelper)
+	strengthMeter := strength.NewMeter(storage, config.Strength)
+	correlationAnalyzer := correlation.NewAnalyzer(storage, config.Correlation)
+	kalmanService := kalman.NewService(config.Strategy.Kalman)
+	markovService := markov.NewService(storage, config.Markov, getMarketTypeHelper)
+
+	calendar, err := sessions.NewCalendar(config.Sessions, config.Risk.Forex)
+	if err != nil {
+		log.Fatalf("❌ Invalid forex session calendar: %v", err)
+	}
+
+	newsService, err := news.NewService(config.News)
+	if err != nil {
+		log.Fatalf("❌ Invalid news calendar: %v", err)
+	}
+
+	adaptiveWeights := adaptive.NewWeights(config.Strategy.Adaptive)
+	if err := adaptiveWeights.Load(); err != nil {
+		log.Printf("⚠️  Starting with fresh adaptive weights: %v", err)
+	}
+
+	engine := &Engine{
+		storage: storage,
+		strategy: strategy.NewCombinedStrategy(strategy.Dependencies{
+			Config:      config.Strategy,
+			Levels:      levelService,
+			Sessions:    calendar,
+			News:        newsService,
+			Hazard:      hazardService,
+			Strength:    strengthMeter,
+			Correlation: correlationAnalyzer,
+			Kalman:      kalmanService,
+			Markov:      markovService,
+		}, adaptiveWeights),
 		tracker:          tracker,
+		levels:           levelService,
+		hazard:           hazardService,
+		randomWalk:       randomwalk.NewService(storage, getMarketTypeHelper),
+		strength:         strengthMeter,
+		correlation:      correlationAnalyzer,
+		garch:            garch.NewService(storage, config.GARCH),
+		kalman:           kalmanService,
+		markov:           markovService,
+		adaptive:         adaptiveWeights,
+		calibration:      calibration.NewCalibrator(config.Calibration),
+		payouts:          payout.NewTable(config.Payout),
+		sessions:         calendar,
+		news:             newsService,
 		config:           config,
 		cache:            make(map[string]*CachedPrediction),
 		requestCounter:   make(map[string]int),
 		lastCounterReset: time.Now(),
+		awaiting:         make(map[string]types.Prediction),
 	}
+	tracker.OnResult(engine.learnFromResult)
+
+	return engine
 }
 
 // Predict generates a timeframe-aware prediction
@@ -54,19 +134,39 @@ func (e *Engine) Predict(market string, duration int) (types.Prediction, error)
 		return types.Prediction{}, fmt.Errorf("rate limit exceeded for %s", market)
 	}
 
-	// Check cache (reuse if < duration-based cache time)
+	// Get market type
+	marketType := getMarketTypeHelper(market)
+
+	// Forex only trades while the market and its sessions are open;
+	// the last tick of a closed market is stale
+	var session *types.SessionStatus
+	if marketType == "forex" {
+		status := e.sessions.Status(market, time.Now())
+		session = &status
+		if !status.Open {
+			return types.Prediction{
+				Market:     market,
+				MarketType: marketType,
+				Direction:  "NONE",
+				Reason:     status.Reason,
+				Duration:   duration,
+				Timestamp:  time.Now(),
+				Session:    session,
+			}, nil
+		}
+	}
+
+	// Check cache (reuse if < duration-based cache time). Runs after the
+	// session gate so a cached entry can't outlive the session close
 	cacheKey := fmt.Sprintf("%s-%d", market, duration)
 	cacheTimeout := e.getCacheTimeout(duration)
 
 	if cached := e.getFromCache(cacheKey); cached != nil {
-		if time.Since(cached.Timestamp) < cacheTimeout {
+		if time.Since(cached.Timestamp) < cacheTimeout && !e.newsBlackout(market, duration) {
 			return cached.Prediction, nil
 		}
 	}
 
-	// Get market type
-	marketType := getMarketTypeHelper(market)
-
 	// Get timeframe configuration
 	tfConfig := candles.GetTimeframeConfig(duration, marketType)
 
@@ -114,24 +214,39 @@ func (e *Engine) Predict(market string, duration int) (types.Prediction, error)
 		}, nil
 	}
 
-	// Convert candles back to ticks for indicator calculation
-	candleTicks := candles.CandlesToTicks(candleData)
-
 	// Generate prediction with timeframe-aware config
 	prediction := e.strategy.GeneratePrediction(
 		market,
-		candleTicks,
+		candleData,
 		duration,
 	)
 	prediction.ID = uuid.New().String()
 	prediction.MarketType = marketType
+	prediction.Session = session
 
-	// Quality boost for good data
-	if len(candleData) >= tfConfig.MinCandles*2 {
-		prediction.Confidence *= 1.05 // 5% boost for abundant data
-		if prediction.Confidence > 0.95 {
-			prediction.Confidence = 0.95
-		}
+	// Higher-timeframe trends veto or boost the strategy's own confidence
+	e.confirmTimeframes(&prediction, market, tfConfig.CandlePeriod, candleData)
+
+	// Map that confidence to the realized win rate of past predictions. Nothing
+	// after this scales it, so a call's confidence is its calibrated probability.
+	prediction.RawConfidence = prediction.Confidence
+	if prediction.Direction != "NONE" {
+		e.calibrate(&prediction)
+	}
+
+	// Forecast volatility over the contract and skip entries into a storm
+	e.forecastVolatility(&prediction)
+
+	// Volatility indices are random walks by design; show what chance alone gives
+	if marketType == "volatility" && prediction.Direction != "NONE" {
+		e.attachBaseline(&prediction)
+	}
+
+	// Price the contract: a confident call can still lose money at a low payout
+	prediction.Payout = e.payouts.Payout(market, marketType, duration)
+	prediction.BreakEven = payout.BreakEven(prediction.Payout)
+	if prediction.Direction != "NONE" {
+		prediction.ExpectedValue = payout.ExpectedValue(winProbability(prediction), prediction.Payout)
 	}
 
 	// Cache it with longer timeout for longer durations
@@ -139,13 +254,461 @@ func (e *Engine) Predict(market string, duration int) (types.Prediction, error)
 
 	// Track if it's a real prediction
 	if prediction.Direction != "NONE" {
+		e.awaitingMu.Lock()
+		e.awaiting[prediction.ID] = prediction
+		e.awaitingMu.Unlock()
+
 		currentPrice := e.storage.GetLatestPrice(market)
 		e.tracker.TrackPrediction(prediction, currentPrice)
+	} else if len(prediction.Consensus) > 0 {
+		// Calls the selected method or a later gate passed on still score
+		// the other methods, so none is judged on the selected one's picks
+		go e.observeLater(prediction, e.storage.GetLatestPrice(market))
 	}
 
 	return prediction, nil
 }
 
+// observeLater waits out an untracked prediction's duration and feeds the
+// price move to the consensus methods
+func (e *Engine) observeLater(prediction types.Prediction, entryPrice float64) {
+	time.Sleep(time.Duration(prediction.Duration) * time.Second)
+
+	exitPrice := e.storage.GetLatestPrice(prediction.Market)
+	if entryPrice == 0 || exitPrice == 0 || exitPrice == entryPrice {
+		return
+	}
+	e.strategy.Observe(prediction, exitPrice > entryPrice)
+}
+
+// learnFromResult feeds a resolved prediction's signals to the adaptive
+// weights and the learned consensus methods. Untracked predictions reach the
+// consensus methods through observeLater.
+func (e *Engine) learnFromResult(result types.TradeResult) {
+	e.adaptive.Update(result)
+
+	e.awaitingMu.Lock()
+	prediction, ok := e.awaiting[result.PredictionID]
+	delete(e.awaiting, result.PredictionID)
+	e.awaitingMu.Unlock()
+
+	if !ok || result.PriceChange == 0 {
+		return
+	}
+
+	e.strategy.Observe(prediction, result.PriceChange > 0)
+}
+
+// ConsensusReport returns consensus settings and each method's track record
+func (e *Engine) ConsensusReport() map[string]strategy.ConsensusReport {
+	return e.strategy.ConsensusReport()
+}
+
+// calibrate replaces a prediction's confidence with its calibrated win
+// probability, and drops the call when that is no better than a coin flip or
+// falls under the market type's min_confidence
+func (e *Engine) calibrate(prediction *types.Prediction) {
+	calibrated, model := e.calibration.Calibrate(prediction.MarketType, prediction.Duration, prediction.RawConfidence)
+
+	info := &types.CalibrationInfo{Method: "none", Calibrated: calibrated}
+	if model != nil {
+		info.Method = model.Method
+		info.Samples = model.Samples
+	}
+
+	prediction.Confidence = calibrated
+	prediction.Calibration = info
+
+	minConfidence := e.config.Strategy.Consensus[prediction.MarketType].MinConfidence
+	switch {
+	case calibrated <= 0.5:
+		e.skipEntry(prediction, "calibrated_below_even", fmt.Sprintf("calibrated win probability %.1f%% (raw %.1f%%, %s) is no better than chance",
+			calibrated*100, prediction.RawConfidence*100, info.Method))
+	case calibrated < minConfidence:
+		e.skipEntry(prediction, "calibrated_below_min", fmt.Sprintf("calibrated win probability %.1f%% (raw %.1f%%, %s) below %.1f%% minimum",
+			calibrated*100, prediction.RawConfidence*100, info.Method, minConfidence*100))
+	}
+}
+
+// forecastVolatility attaches the volatility forecast over the contract and
+// vetoes the entry when the forecast runs more than the market type's
+// skip_high_volatility_threshold times its long-run level, or when a forex
+// pair's expected move over the contract doesn't cover max_spread_pips
+func (e *Engine) forecastVolatility(prediction *types.Prediction) {
+	forecast, ok := e.volatilityForecast(prediction.Market, prediction.Duration)
+	if !ok {
+		return
+	}
+	prediction.Volatility = &forecast
+
+	if prediction.Direction == "NONE" {
+		return
+	}
+
+	threshold := e.volatilityThreshold(prediction.MarketType)
+	if forecast.Ratio > threshold {
+		e.skipEntry(prediction, "high_volatility", fmt.Sprintf("forecast volatility %.1fx its long-run level over %ds exceeds %.1fx (%s)",
+			forecast.Ratio, prediction.Duration, threshold, forecast.Method))
+		return
+	}
+
+	if prediction.MarketType == "forex" {
+		pips := prediction.CurrentPrice * forecast.ExpectedMove / pipSize(prediction.Market)
+		if pips < e.config.Risk.MaxSpreadPips {
+			e.skipEntry(prediction, "move_below_spread", fmt.Sprintf("expected move %.1f pips over %ds doesn't cover the %.1f pip spread",
+				pips, prediction.Duration, e.config.Risk.MaxSpreadPips))
+		}
+	}
+}
+
+// winProbability is a directional prediction's calibrated win probability,
+// the number its expected value and random-walk edge are measured from
+func winProbability(prediction types.Prediction) float64 {
+	if prediction.Calibration != nil {
+		return prediction.Calibration.Calibrated
+	}
+	return prediction.Confidence
+}
+
+// skipEntry turns a directional prediction into NONE with a reason code
+func (e *Engine) skipEntry(prediction *types.Prediction, code, reason string) {
+	prediction.Reason = fmt.Sprintf("%s entry skipped: %s: %s", prediction.Direction, reason, prediction.Reason)
+	prediction.ReasonCode = code
+	prediction.Direction = "NONE"
+	prediction.Confidence = 0
+}
+
+// pipSize is the price of one pip: 0.01 for yen pairs, 0.0001 otherwise
+func pipSize(market string) float64 {
+	if strings.HasSuffix(market, "JPY") {
+		return 0.01
+	}
+	return 0.0001
+}
+
+// volatilityForecast forecasts a market over a duration, fitting its model
+// on demand if the background refresh hasn't run yet
+func (e *Engine) volatilityForecast(market string, duration int) (types.VolatilityForecast, bool) {
+	e.garch.Ensure(market)
+	return e.garch.Forecast(market, duration)
+}
+
+// volatilityThreshold is a market type's skip_high_volatility_threshold, a
+// multiple of the long-run volatility
+func (e *Engine) volatilityThreshold(marketType string) float64 {
+	if marketType == "forex" {
+		return e.config.Risk.Forex.SkipHighVolatilityThreshold
+	}
+	return e.config.Risk.Synthetics.SkipHighVolatilityThreshold
+}
+
+// attachBaseline reports the random-walk win probability of a prediction
+// and how far its calibrated probability beats it
+func (e *Engine) attachBaseline(prediction *types.Prediction) {
+	sigma := e.randomWalk.Sigma(prediction.Market)
+	if sigma <= 0 {
+		return
+	}
+
+	probability := randomwalk.DirectionProbability(prediction.Direction, sigma, prediction.Duration)
+	prediction.Baseline = &types.RandomWalkBaseline{
+		Sigma:        sigma,
+		NominalSigma: randomwalk.NominalSigma(prediction.Market),
+		Probability:  probability,
+		Edge:         winProbability(*prediction) - probability,
+	}
+}
+
+// VolatilityModel is the random-walk view of a Volatility index
+type VolatilityModel struct {
+	Model       *randomwalk.Model      `json:"model,omitempty"` // Nil until enough archived ticks
+	Sigma       float64                `json:"sigma"`           // Annualized sigma priced with
+	Duration    int                    `json:"duration"`
+	Price       float64                `json:"price"`
+	Rise        float64                `json:"rise"`
+	Fall        float64                `json:"fall"`
+	Barrier     *randomwalk.Barrier    `json:"barrier,omitempty"`
+	Performance randomwalk.Performance `json:"performance"` // Tracked predictions against the baseline
+}
+
+// VolatilityModel returns realized vs nominal sigma, the theoretical rise/fall
+// and optional barrier probabilities over a duration, and how tracked
+// predictions did against that baseline
+func (e *Engine) VolatilityModel(market string, duration int, barrierOffset *float64) (VolatilityModel, error) {
+	if getMarketTypeHelper(market) != "volatility" {
+		return VolatilityModel{}, fmt.Errorf("%s is not a Volatility index", market)
+	}
+	if e.storage.GetTickCount(market) == 0 {
+		return VolatilityModel{}, fmt.Errorf("no data for %s", market)
+	}
+
+	if _, ok := e.randomWalk.Model(market); !ok {
+		e.randomWalk.RefreshMarket(market)
+	}
+
+	report := VolatilityModel{
+		Sigma:       e.randomWalk.Sigma(market),
+		Duration:    duration,
+		Price:       e.storage.GetLatestPrice(market),
+		Performance: randomwalk.Measure(e.storage.GetResults(market)),
+	}
+	if model, ok := e.randomWalk.Model(market); ok {
+		report.Model = &model
+	}
+	if report.Sigma <= 0 {
+		return VolatilityModel{}, fmt.Errorf("no sigma for %s", market)
+	}
+
+	report.Rise = randomwalk.RiseProbability(report.Sigma, duration)
+	report.Fall = 1 - report.Rise
+	if barrierOffset != nil {
+		barrier := randomwalk.BarrierProbabilities(report.Price, *barrierOffset, report.Sigma, duration)
+		report.Barrier = &barrier
+	}
+
+	return report, nil
+}
+
+// CurrencyStrength returns the currency strength meter and triangulation
+// check across all subscribed forex pairs
+func (e *Engine) CurrencyStrength() strength.Snapshot {
+	return e.strength.Current()
+}
+
+// Correlations returns the cross-market correlation matrix and lead-lag pairs
+func (e *Engine) Correlations() correlation.Report {
+	return e.correlation.Report()
+}
+
+// newsBlackout reports whether a release now blocks a forex contract of
+// duration seconds, so a call cached before its window opened isn't served
+func (e *Engine) newsBlackout(market string, duration int) bool {
+	if getMarketTypeHelper(market) != "forex" {
+		return false
+	}
+	_, blocked := e.news.Blackout(market, time.Now(), time.Duration(duration)*time.Second)
+	return blocked
+}
+
+// UpcomingNews returns economic releases within horizon, optionally for one currency
+func (e *Engine) UpcomingNews(horizon time.Duration, currency string) []types.NewsEvent {
+	return e.news.Upcoming(time.Now(), horizon, currency)
+}
+
+// MinEV is the expected value a prediction needs to be worth trading
+func (e *Engine) MinEV() float64 {
+	return e.payouts.MinEV()
+}
+
+// Reliability returns the reliability diagram for a market type and duration
+func (e *Engine) Reliability(marketType string, duration int) calibration.Reliability {
+	return e.calibration.Reliability(marketType, duration)
+}
+
+// AllReliability returns reliability diagrams for every market type and duration with results
+func (e *Engine) AllReliability() []calibration.Reliability {
+	return e.calibration.All()
+}
+
+// ComputeIndicator calculates a registered indicator on the candles used for a duration
+func (e *Engine) ComputeIndicator(market string, duration int, key string, params indicators.Params) (indicators.Values, error) {
+	ticks := e.storage.GetAllTicks(market)
+	if len(ticks) == 0 {
+		return nil, fmt.Errorf("no data for %s", market)
+	}
+
+	tfConfig := candles.GetTimeframeConfig(duration, getMarketTypeHelper(market))
+	candleData := candles.TicksToCandles(ticks, tfConfig.CandlePeriod)
+
+	return indicators.Compute(key, candleData, params)
+}
+
+// MeanReversionFit is a market's OU fit on the candles used for a duration
+type MeanReversionFit struct {
+	Market   string `json:"market"`
+	Duration int    `json:"duration"`
+	meanreversion.Fit
+	Price  float64 `json:"price"`
+	Within bool    `json:"within_duration"` // Half-life fits inside the duration, so MeanReversion can fire
+}
+
+// MeanReversion fits an Ornstein-Uhlenbeck process to the candles used for a duration
+func (e *Engine) MeanReversion(market string, duration int) (MeanReversionFit, error) {
+	ticks := e.storage.GetAllTicks(market)
+	if len(ticks) == 0 {
+		return MeanReversionFit{}, fmt.Errorf("no data for %s", market)
+	}
+
+	tfConfig := candles.GetTimeframeConfig(duration, getMarketTypeHelper(market))
+	candleData := candles.TicksToCandles(ticks, tfConfig.CandlePeriod)
+	fit := meanreversion.FitCandles(candleData, int(tfConfig.CandlePeriod/time.Second))
+
+	return MeanReversionFit{
+		Market:   market,
+		Duration: duration,
+		Fit:      fit,
+		Price:    ticks[len(ticks)-1].Price,
+		Within:   fit.Within(duration),
+	}, nil
+}
+
+// KalmanTrend is a market's Kalman trend estimate on the candles used for a
+// duration and its projection over that duration
+type KalmanTrend struct {
+	kalman.Estimate
+	Projection kalman.Projection `json:"projection"`
+}
+
+// KalmanTrend updates the market's Kalman filter for a duration's candle
+// period and projects its slope over the duration
+func (e *Engine) KalmanTrend(market string, duration int) (KalmanTrend, error) {
+	ticks := e.storage.GetAllTicks(market)
+	if len(ticks) == 0 {
+		return KalmanTrend{}, fmt.Errorf("no data for %s", market)
+	}
+
+	tfConfig := candles.GetTimeframeConfig(duration, getMarketTypeHelper(market))
+	estimate, ok := e.kalman.Update(market, tfConfig.CandlePeriod, candles.TicksToCandles(ticks, tfConfig.CandlePeriod))
+	if !ok {
+		return KalmanTrend{}, fmt.Errorf("not enough candles for %s", market)
+	}
+
+	return KalmanTrend{Estimate: estimate, Projection: estimate.Project(duration)}, nil
+}
+
+// EnabledStrategies returns the strategies run for each market type
+func (e *Engine) EnabledStrategies() map[string][]string {
+	enabled := make(map[string][]string)
+	for marketType := range strategy.DefaultStrategies {
+		enabled[marketType] = e.strategy.Strategies(marketType)
+	}
+	return enabled
+}
+
+// RefreshAnalytics updates background market analysis from the tick archive,
+// reloads the news calendar, saves learned weights and refits confidence
+// calibration
+func (e *Engine) RefreshAnalytics() {
+	e.levels.Refresh()
+	e.strength.Refresh()
+	e.hazard.Refresh()
+	e.randomWalk.Refresh()
+	e.correlation.Refresh()
+	e.garch.Refresh()
+	e.markov.Refresh()
+	if err := e.news.Reload(); err != nil {
+		log.Printf("⚠️  Keeping previous news calendar: %v", err)
+	}
+	e.SaveLearnedState()
+	e.refitCalibration()
+}
+
+// refitCalibration fits confidence calibration on every stored result
+func (e *Engine) refitCalibration() {
+	var results []types.TradeResult
+	for _, marketResults := range e.storage.GetAllResults() {
+		results = append(results, marketResults...)
+	}
+	e.calibration.Fit(results)
+}
+
+// SaveLearnedState persists learned signal weights, consensus method scores
+// and the stacked model
+func (e *Engine) SaveLearnedState() {
+	if err := e.adaptive.Save(); err != nil {
+		log.Printf("⚠️  %v", err)
+	}
+	if err := e.strategy.SaveConsensus(); err != nil {
+		log.Printf("⚠️  %v", err)
+	}
+}
+
+// AdaptiveWeights returns the learned signal weight multipliers
+func (e *Engine) AdaptiveWeights() []adaptive.Record {
+	return e.adaptive.Records()
+}
+
+// GetLevels returns support/resistance levels for a market,
+// building them on demand if the background refresh hasn't run yet
+func (e *Engine) GetLevels(market string) (levels.Snapshot, error) {
+	if snapshot, ok := e.levels.GetLevels(market); ok {
+		return snapshot, nil
+	}
+
+	if e.storage.GetTickCount(market) == 0 {
+		return levels.Snapshot{}, fmt.Errorf("no data for %s", market)
+	}
+
+	e.levels.RefreshMarket(market)
+	snapshot, _ := e.levels.GetLevels(market)
+	return snapshot, nil
+}
+
+// CrashBoomHazard returns the spike hazard model of a Crash/Boom index and
+// its forecast for each duration, fitting it on demand if the background
+// refresh hasn't run yet
+func (e *Engine) CrashBoomHazard(market string, durations []int) (hazard.Model, []hazard.Forecast, error) {
+	if getMarketTypeHelper(market) != "crash_boom" {
+		return hazard.Model{}, nil, fmt.Errorf("%s is not a Crash/Boom index", market)
+	}
+
+	model, ok := e.hazard.Model(market)
+	if !ok {
+		e.hazard.RefreshMarket(market)
+		if model, ok = e.hazard.Model(market); !ok {
+			return hazard.Model{}, nil, fmt.Errorf("not enough history for %s (%d/%d archived ticks)",
+				market, len(e.storage.GetArchiveTicks(market)), e.config.Hazard.MinTicks)
+		}
+	}
+
+	forecasts := make([]hazard.Forecast, 0, len(durations))
+	for _, duration := range durations {
+		forecasts = append(forecasts, model.Forecast(duration))
+	}
+	return model, forecasts, nil
+}
+
+// VolatilityForecast returns the volatility model of a market and its
+// forecast for each duration, fitting it on demand if the background refresh
+// hasn't run yet
+func (e *Engine) VolatilityForecast(market string, durations []int) (garch.Model, []types.VolatilityForecast, error) {
+	e.garch.Ensure(market)
+	model, ok := e.garch.Model(market)
+	if !ok {
+		return garch.Model{}, nil, fmt.Errorf("not enough history for %s (%d archived ticks)",
+			market, len(e.storage.GetArchiveTicks(market)))
+	}
+
+	forecasts := make([]types.VolatilityForecast, 0, len(durations))
+	for _, duration := range durations {
+		forecasts = append(forecasts, model.Forecast(duration))
+	}
+	return model, forecasts, nil
+}
+
+// TickMarkov returns the tick-direction Markov model of a synthetic index and
+// its forecast over a duration when an order is significant, fitting it on
+// demand if the background refresh hasn't run yet
+func (e *Engine) TickMarkov(market string, duration int) (markov.Model, *markov.Forecast, error) {
+	if getMarketTypeHelper(market) == "forex" {
+		return markov.Model{}, nil, fmt.Errorf("%s is not a synthetic index", market)
+	}
+
+	model, ok := e.markov.Model(market)
+	if !ok {
+		e.markov.RefreshMarket(market)
+		if model, ok = e.markov.Model(market); !ok {
+			return markov.Model{}, nil, fmt.Errorf("not enough history for %s (%d/%d archived ticks)",
+				market, len(e.storage.GetArchiveTicks(market)), e.config.Markov.MinTicks)
+		}
+	}
+
+	if forecast, ok := e.markov.Forecast(market, duration); ok {
+		return model, &forecast, nil
+	}
+	return model, nil, nil
+}
+
 // getCacheTimeout returns appropriate cache timeout based on duration
 func (e *Engine) getCacheTimeout(duration int) time.Duration {
 	switch {
@@ -243,6 +806,15 @@ func (e *Engine) CleanupCache() {
 			delete(e.cache, key)
 		}
 	}
+
+	// Drop predictions the tracker gave up on
+	e.awaitingMu.Lock()
+	defer e.awaitingMu.Unlock()
+	for id, prediction := range e.awaiting {
+		if time.Since(prediction.Timestamp) > time.Duration(prediction.Duration)*time.Second+5*time.Minute {
+			delete(e.awaiting, id)
+		}
+	}
 }
 
 // GetStats returns statistics for a market
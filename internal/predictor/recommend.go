@@ -0,0 +1,154 @@
+package predictor
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"otc-predictor/internal/payout"
+	"otc-predictor/internal/tracker"
+	"otc-predictor/pkg/types"
+)
+
+// DurationCandidate is one candidate duration's tracked record and outlook
+type DurationCandidate struct {
+	Duration          int                       `json:"duration"`
+	Rank              int                       `json:"rank"` // 1 = recommended, 0 = not eligible
+	Trades            int                       `json:"trades"`
+	WinRate           float64                   `json:"win_rate"`            // 0-1
+	WinRateLower      float64                   `json:"win_rate_lower"`      // 95% Wilson interval
+	WinRateUpper      float64                   `json:"win_rate_upper"`      // 95% Wilson interval
+	CalibratedWinRate float64                   `json:"calibrated_win_rate"` // Mean calibrated confidence of the tracked calls
+	Payout            float64                   `json:"payout"`
+	BreakEven         float64                   `json:"break_even"`
+	ExpectedValue     float64                   `json:"expected_value"` // At the calibrated win rate
+	Score             float64                   `json:"score"`          // EV at the lower of the Wilson bound and calibrated win rate
+	Volatility        *types.VolatilityForecast `json:"volatility_forecast,omitempty"`
+	Eligible          bool                      `json:"eligible"`
+	Note              string                    `json:"note,omitempty"` // Why it isn't eligible
+}
+
+// DurationRecommendation ranks a market's candidate durations
+type DurationRecommendation struct {
+	Market     string              `json:"market"`
+	MarketType string              `json:"market_type"`
+	Duration   int                 `json:"duration"`
+	NoEdge     bool                `json:"no_edge"`               // No duration has a proven edge
+	ReasonCode string              `json:"reason_code,omitempty"` // no_edge or insufficient_history when NoEdge
+	Reason     string              `json:"reason"`
+	Candidates []DurationCandidate `json:"candidates"`
+	Time       time.Time           `json:"time"`
+}
+
+// RecommendDuration ranks the candidate durations of a market by the
+// expected value of their tracked record. A duration is eligible once it has
+// recommend.min_trades results and its volatility forecast is under the
+// skip threshold; its score prices the contract at the pessimistic end of
+// what it has shown, the Wilson lower bound of its win rate, unless the
+// calibrated confidence claims even less. When every eligible duration scores
+// zero or less, or none is eligible yet, the market type's preferred_duration
+// is reported flagged NoEdge: it is a placeholder, not a recommendation.
+func (e *Engine) RecommendDuration(market string) (DurationRecommendation, error) {
+	if e.storage.GetTickCount(market) == 0 {
+		return DurationRecommendation{}, fmt.Errorf("no data for %s", market)
+	}
+
+	marketType := getMarketTypeHelper(market)
+	threshold := e.volatilityThreshold(marketType)
+
+	results := make(map[int][]types.TradeResult)
+	for _, result := range e.storage.GetResults(market) {
+		results[result.Duration] = append(results[result.Duration], result)
+	}
+
+	candidates := make([]DurationCandidate, 0, len(e.config.Recommend.Durations))
+	for _, duration := range e.config.Recommend.Durations {
+		candidate := DurationCandidate{
+			Duration: duration,
+			Payout:   e.payouts.Payout(market, marketType, duration),
+		}
+		candidate.BreakEven = payout.BreakEven(candidate.Payout)
+
+		wins, calibrated := 0, 0.0
+		for _, result := range results[duration] {
+			if result.Won {
+				wins++
+			}
+			probability, _ := e.calibration.Calibrate(marketType, duration, result.RawConfidence)
+			calibrated += probability
+		}
+		candidate.Trades = len(results[duration])
+		candidate.WinRateLower, candidate.WinRateUpper = tracker.WilsonInterval(wins, candidate.Trades)
+		if candidate.Trades > 0 {
+			candidate.WinRate = float64(wins) / float64(candidate.Trades)
+			candidate.CalibratedWinRate = calibrated / float64(candidate.Trades)
+			candidate.ExpectedValue = payout.ExpectedValue(candidate.CalibratedWinRate, candidate.Payout)
+			candidate.Score = payout.ExpectedValue(math.Min(candidate.WinRateLower, candidate.CalibratedWinRate), candidate.Payout)
+		}
+
+		if forecast, ok := e.volatilityForecast(market, duration); ok {
+			candidate.Volatility = &forecast
+		}
+
+		switch {
+		case candidate.Trades < e.config.Recommend.MinTrades:
+			candidate.Note = fmt.Sprintf("%d/%d tracked results", candidate.Trades, e.config.Recommend.MinTrades)
+		case candidate.Volatility != nil && candidate.Volatility.Ratio > threshold:
+			candidate.Note = fmt.Sprintf("forecast volatility %.1fx its long-run level exceeds %.1fx", candidate.Volatility.Ratio, threshold)
+		default:
+			candidate.Eligible = true
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	ranked := make([]*DurationCandidate, 0, len(candidates))
+	for i := range candidates {
+		if candidates[i].Eligible {
+			ranked = append(ranked, &candidates[i])
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].ExpectedValue > ranked[j].ExpectedValue
+	})
+	for i, candidate := range ranked {
+		candidate.Rank = i + 1
+	}
+
+	recommendation := DurationRecommendation{
+		Market:     market,
+		MarketType: marketType,
+		Candidates: candidates,
+		Time:       time.Now(),
+	}
+	preferred := e.config.Risk.Synthetics.PreferredDuration
+	if marketType == "forex" {
+		preferred = e.config.Risk.Forex.PreferredDuration
+	}
+
+	switch {
+	case len(ranked) > 0 && ranked[0].Score > 0:
+		best := ranked[0]
+		recommendation.Duration = best.Duration
+		recommendation.Reason = fmt.Sprintf("%ds: win rate %.1f%% [%.1f-%.1f%%] over %d trades, calibrated EV %+.3f, score %+.3f",
+			best.Duration, best.WinRate*100, best.WinRateLower*100, best.WinRateUpper*100, best.Trades, best.ExpectedValue, best.Score)
+	case len(ranked) > 0:
+		best := ranked[0]
+		recommendation.Duration = preferred
+		recommendation.NoEdge = true
+		recommendation.ReasonCode = "no_edge"
+		recommendation.Reason = fmt.Sprintf("%ds: preferred duration, no duration has a proven edge (best %ds scores %+.3f at a %.1f%% win rate lower bound)",
+			preferred, best.Duration, best.Score, best.WinRateLower*100)
+	default:
+		recommendation.Duration = preferred
+		recommendation.NoEdge = true
+		recommendation.ReasonCode = "insufficient_history"
+		recommendation.Reason = fmt.Sprintf("%ds: preferred duration, no candidate has %d tracked results under the volatility threshold",
+			recommendation.Duration, e.config.Recommend.MinTrades)
+	}
+
+	return recommendation, nil
+}
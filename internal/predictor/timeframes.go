@@ -0,0 +1,104 @@
+package predictor
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"otc-predictor/internal/candles"
+	"otc-predictor/internal/indicators"
+	"otc-predictor/pkg/types"
+)
+
+// maxHigherTimeframes bounds how many higher timeframes confirm an entry
+const maxHigherTimeframes = 2
+
+// confirmTimeframes measures the trend on the primary and higher timeframes,
+// vetoes entries against a strong higher trend and boosts entries with it.
+// Higher timeframes come from the tick archive since the live window is too
+// short for their candles.
+func (e *Engine) confirmTimeframes(prediction *types.Prediction, market string, primary time.Duration, primaryCandles []types.Candle) {
+	settings := e.config.Strategy.MultiTimeframe
+	if !settings.Enabled {
+		return
+	}
+
+	trends := []types.TimeframeTrend{timeframeTrend(primaryCandles, primary, e.config.Strategy)}
+	trends[0].Primary = true
+
+	var archive []types.Tick
+	for _, seconds := range settings.Higher[prediction.MarketType] {
+		period := time.Duration(seconds) * time.Second
+		if period <= primary {
+			continue
+		}
+		if len(trends) > maxHigherTimeframes {
+			break
+		}
+		if archive == nil {
+			archive = e.storage.GetArchiveTicks(market)
+		}
+		trends = append(trends, timeframeTrend(candles.TicksToCandles(archive, period), period, e.config.Strategy))
+	}
+
+	if prediction.Direction != "NONE" {
+		for i := 1; i < len(trends); i++ {
+			trend := &trends[i]
+			switch {
+			case trend.Trend == prediction.Direction:
+				trend.Effect = "boost"
+				prediction.Confidence = math.Min(0.95, prediction.Confidence*(1+settings.Boost))
+			case (trend.Trend == "UP" || trend.Trend == "DOWN") && trend.Strength >= settings.VetoStrength:
+				trend.Effect = "veto"
+			}
+		}
+
+		for _, trend := range trends[1:] {
+			if trend.Effect == "veto" {
+				prediction.Reason = fmt.Sprintf("%s entry vetoed by %s %s trend (%.2f ATR): %s",
+					prediction.Direction, time.Duration(trend.Period)*time.Second, trend.Trend, trend.Strength, prediction.Reason)
+				prediction.ReasonCode = "timeframe_veto"
+				prediction.Direction = "NONE"
+				prediction.Confidence = 0
+				break
+			}
+		}
+	}
+
+	prediction.Timeframes = trends
+}
+
+// timeframeTrend classifies a timeframe by its fast/slow EMA spread in ATRs.
+// Spreads under a tenth of an ATR are flat.
+func timeframeTrend(candleData []types.Candle, period time.Duration, config types.StrategyConfig) types.TimeframeTrend {
+	trend := types.TimeframeTrend{
+		Period:  int(period / time.Second),
+		Candles: len(candleData),
+		Trend:   "NONE",
+	}
+	if len(candleData) < config.EMASlow+1 {
+		return trend
+	}
+
+	ticks := candles.CandlesToTicks(candleData)
+	trend.EMAFast = indicators.CalculateEMA(ticks, config.EMAFast)
+	trend.EMASlow = indicators.CalculateEMA(ticks, config.EMASlow)
+
+	atr := indicators.CalculateATR(candleData, 14)
+	if atr <= 0 {
+		trend.Trend = "FLAT"
+		return trend
+	}
+
+	spread := (trend.EMAFast - trend.EMASlow) / atr
+	trend.Strength = math.Abs(spread)
+	switch {
+	case spread > 0.1:
+		trend.Trend = "UP"
+	case spread < -0.1:
+		trend.Trend = "DOWN"
+	default:
+		trend.Trend = "FLAT"
+	}
+	return trend
+}
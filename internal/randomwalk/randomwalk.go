@@ -0,0 +1,276 @@
+package randomwalk
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"otc-predictor/internal/candles"
+	"otc-predictor/internal/storage"
+	"otc-predictor/pkg/types"
+)
+
+// SecondsPerYear annualizes volatility the way the indices are specified
+const SecondsPerYear = 365 * 24 * 60 * 60
+
+// minTicks is the archive needed before realized sigma is trusted
+const minTicks = 300
+
+// Service estimates realized sigma per Volatility index from the tick archive.
+// The indices are driftless geometric random walks with a constant annual
+// sigma set by their name (Volatility 75 = 75%), so outcome probabilities
+// are known in advance and any strategy edge can be measured against them.
+type Service struct {
+	storage    *storage.MemoryStorage
+	marketType func(string) string
+	models     map[string]Model
+	mu         sync.RWMutex
+}
+
+// Model compares an index's realized sigma with its nominal sigma
+type Model struct {
+	Market        string    `json:"market"`
+	NominalSigma  float64   `json:"nominal_sigma"`  // Annualized, from the name; 0 if unknown
+	RealizedSigma float64   `json:"realized_sigma"` // Annualized, from the archive
+	Ratio         float64   `json:"ratio"`          // Realized / nominal
+	RatioLower    float64   `json:"ratio_lower"`    // 95% sampling band of the ratio
+	RatioUpper    float64   `json:"ratio_upper"`
+	Consistent    bool      `json:"consistent"` // Nominal sigma inside the band
+	Returns       int       `json:"returns"`
+	TickSeconds   float64   `json:"tick_seconds"`
+	Updated       time.Time `json:"updated"`
+}
+
+// Barrier holds the theoretical outcomes of a barrier contract
+type Barrier struct {
+	Offset   float64 `json:"offset"`    // Barrier minus entry price
+	EndAbove float64 `json:"end_above"` // P(price above the barrier at expiry)
+	EndBelow float64 `json:"end_below"`
+	Touch    float64 `json:"touch"` // P(price touches the barrier before expiry)
+	NoTouch  float64 `json:"no_touch"`
+}
+
+// Performance compares tracked predictions with the random-walk baseline
+type Performance struct {
+	Trades         int     `json:"trades"`
+	WinRate        float64 `json:"win_rate"`        // Realized, 0-1
+	MeanBaseline   float64 `json:"mean_baseline"`   // Theoretical win rate of the same calls
+	MeanConfidence float64 `json:"mean_confidence"` // What the predictions claimed
+	Edge           float64 `json:"edge"`            // WinRate - MeanBaseline
+	ZScore         float64 `json:"z_score"`         // Wins above baseline in standard errors
+}
+
+// NewService creates a random-walk service. marketType maps a market symbol
+// to "forex", "volatility" or "crash_boom".
+func NewService(store *storage.MemoryStorage, marketType func(string) string) *Service {
+	return &Service{
+		storage:    store,
+		marketType: marketType,
+		models:     make(map[string]Model),
+	}
+}
+
+// Refresh re-estimates realized sigma for every active Volatility index
+func (s *Service) Refresh() {
+	for _, market := range s.storage.GetActiveMarkets() {
+		if s.marketType(market) == "volatility" {
+			s.RefreshMarket(market)
+		}
+	}
+}
+
+// RefreshMarket re-estimates one index from its archive
+func (s *Service) RefreshMarket(market string) {
+	ticks := s.storage.GetArchiveTicks(market)
+	if len(ticks) < minTicks {
+		return
+	}
+
+	model := fit(market, ticks)
+
+	s.mu.Lock()
+	s.models[market] = model
+	s.mu.Unlock()
+}
+
+// Model returns the fitted model of a market
+func (s *Service) Model(market string) (Model, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	model, ok := s.models[market]
+	return model, ok
+}
+
+// Sigma returns the annual sigma to price a market with: realized once the
+// archive is long enough, nominal before that
+func (s *Service) Sigma(market string) float64 {
+	if model, ok := s.Model(market); ok && model.RealizedSigma > 0 {
+		return model.RealizedSigma
+	}
+	return NominalSigma(market)
+}
+
+// fit estimates annualized sigma from log returns and checks it against the
+// nominal sigma with the chi-square sampling band of a variance estimate.
+// Returns across feed gaps are dropped, and the tick spacing is measured on
+// the steps inside the feed, so a reconnect neither adds a multi-tick move
+// nor counts its downtime as tick time.
+func fit(market string, ticks []types.Tick) Model {
+	model := Model{
+		Market:       market,
+		NominalSigma: NominalSigma(market),
+		Updated:      time.Now(),
+	}
+
+	inFeed, tickSeconds := candles.FeedSteps(ticks)
+	var sum, sumSq float64
+	for i := 1; i < len(ticks); i++ {
+		if !inFeed[i] || ticks[i-1].Price <= 0 || ticks[i].Price <= 0 {
+			continue
+		}
+		r := math.Log(ticks[i].Price / ticks[i-1].Price)
+		sum += r
+		sumSq += r * r
+		model.Returns++
+	}
+	if model.Returns < 2 {
+		return model
+	}
+
+	model.TickSeconds = tickSeconds
+	if model.TickSeconds <= 0 {
+		model.TickSeconds = 1
+	}
+
+	n := float64(model.Returns)
+	mean := sum / n
+	variance := (sumSq - n*mean*mean) / (n - 1)
+	model.RealizedSigma = math.Sqrt(math.Max(0, variance) * SecondsPerYear / model.TickSeconds)
+
+	if model.NominalSigma > 0 {
+		model.Ratio = model.RealizedSigma / model.NominalSigma
+
+		// sigma_hat/sigma ~ sqrt(chi2(n-1)/(n-1)) ≈ 1 ± 1.96/sqrt(2(n-1))
+		halfWidth := 1.96 / math.Sqrt(2*(n-1))
+		model.RatioLower = math.Max(0, 1-halfWidth)
+		model.RatioUpper = 1 + halfWidth
+		model.Consistent = model.Ratio >= model.RatioLower && model.Ratio <= model.RatioUpper
+	}
+
+	return model
+}
+
+// RiseProbability is P(price at expiry > entry) for a driftless geometric
+// random walk: the log price drifts by -sigma²/2 per unit time
+func RiseProbability(sigma float64, seconds int) float64 {
+	if sigma <= 0 || seconds <= 0 {
+		return 0.5
+	}
+	s := sigma * math.Sqrt(float64(seconds)/SecondsPerYear)
+	return normalCDF(-s / 2)
+}
+
+// DirectionProbability is the theoretical chance of a rise/fall call winning
+func DirectionProbability(direction string, sigma float64, seconds int) float64 {
+	up := RiseProbability(sigma, seconds)
+	if direction == "DOWN" {
+		return 1 - up
+	}
+	return up
+}
+
+// BarrierProbabilities prices end-above and touch outcomes for a barrier at
+// entry+offset. Touch uses the first-passage law of Brownian motion with drift.
+func BarrierProbabilities(price, offset, sigma float64, seconds int) Barrier {
+	barrier := Barrier{Offset: offset}
+	if price <= 0 || price+offset <= 0 || sigma <= 0 || seconds <= 0 {
+		return barrier
+	}
+
+	t := float64(seconds) / SecondsPerYear
+	s := sigma * math.Sqrt(t)
+	nu := -sigma * sigma / 2 // Log drift
+	b := math.Log((price + offset) / price)
+
+	barrier.EndAbove = normalCDF((nu*t - b) / s)
+	barrier.EndBelow = 1 - barrier.EndAbove
+
+	switch {
+	case b == 0:
+		barrier.Touch = 1
+	case b > 0:
+		barrier.Touch = normalCDF((nu*t-b)/s) + math.Exp(2*nu*b/(sigma*sigma))*normalCDF((-b-nu*t)/s)
+	default:
+		barrier.Touch = normalCDF((b-nu*t)/s) + math.Exp(2*nu*b/(sigma*sigma))*normalCDF((b+nu*t)/s)
+	}
+	barrier.Touch = math.Min(1, barrier.Touch)
+	barrier.NoTouch = 1 - barrier.Touch
+
+	return barrier
+}
+
+// Measure compares tracked results carrying a baseline with that baseline.
+// Under the random walk wins are Bernoulli(baseline), so the z-score tests
+// whether the strategy beats chance.
+func Measure(results []types.TradeResult) Performance {
+	var performance Performance
+	var wins, variance float64
+	for _, result := range results {
+		if result.BaselineProbability == 0 {
+			continue
+		}
+		performance.Trades++
+		performance.MeanBaseline += result.BaselineProbability
+		performance.MeanConfidence += result.Confidence
+		variance += result.BaselineProbability * (1 - result.BaselineProbability)
+		if result.Won {
+			wins++
+		}
+	}
+	if performance.Trades == 0 {
+		return performance
+	}
+
+	n := float64(performance.Trades)
+	if variance > 0 {
+		performance.ZScore = (wins - performance.MeanBaseline) / math.Sqrt(variance)
+	}
+	performance.WinRate = wins / n
+	performance.MeanBaseline /= n
+	performance.MeanConfidence /= n
+	performance.Edge = performance.WinRate - performance.MeanBaseline
+	return performance
+}
+
+// NominalSigma reads the annual sigma from an index symbol such as
+// volatility_75_1s, R_75 or 1HZ75V, or 0 if it has none
+func NominalSigma(market string) float64 {
+	m := strings.ToLower(market)
+	switch {
+	case strings.HasPrefix(m, "volatility_"):
+		m = strings.TrimPrefix(m, "volatility_")
+	case strings.HasPrefix(m, "r_"):
+		m = strings.TrimPrefix(m, "r_")
+	case strings.HasPrefix(m, "1hz"):
+		m = strings.TrimPrefix(m, "1hz")
+	default:
+		return 0
+	}
+
+	end := 0
+	for end < len(m) && m[end] >= '0' && m[end] <= '9' {
+		end++
+	}
+	percent, err := strconv.Atoi(m[:end])
+	if err != nil {
+		return 0
+	}
+	return float64(percent) / 100
+}
+
+func normalCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
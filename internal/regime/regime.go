@@ -0,0 +1,154 @@
+package regime
+
+import (
+	"math"
+	"strings"
+
+	"otc-predictor/internal/candles"
+	"otc-predictor/internal/indicators"
+	"otc-predictor/pkg/types"
+)
+
+// Regime names
+const (
+	TrendingUp     = "trending_up"
+	TrendingDown   = "trending_down"
+	Ranging        = "ranging"
+	HighVolatility = "high_volatility"
+	Quiet          = "quiet"
+)
+
+// Detect classifies the market from candles.
+// High volatility wins over everything; a trend needs ADX above the threshold
+// confirmed by either the efficiency ratio or the Hurst exponent; quiet
+// markets are low-volatility non-trends; everything else is ranging.
+func Detect(candleData []types.Candle, config types.RegimeConfig) types.MarketRegime {
+	ticks := candles.CandlesToTicks(candleData)
+	adx := indicators.CalculateADX(candleData, config.ADXPeriod)
+
+	result := types.MarketRegime{
+		ADX:                  adx.ADX,
+		PlusDI:               adx.PlusDI,
+		MinusDI:              adx.MinusDI,
+		VolatilityPercentile: volatilityPercentile(ticks, config.VolatilityWindow),
+		Hurst:                indicators.CalculateHurst(ticks, config.HurstWindow),
+		EfficiencyRatio:      indicators.CalculateEfficiencyRatio(ticks, config.EfficiencyPeriod),
+	}
+
+	trending := adx.ADX >= config.TrendADX &&
+		(result.EfficiencyRatio >= config.TrendEfficiency || result.Hurst >= config.TrendHurst)
+
+	switch {
+	case result.VolatilityPercentile >= config.HighVolPercentile:
+		result.Regime = HighVolatility
+	case trending && adx.PlusDI >= adx.MinusDI:
+		result.Regime = TrendingUp
+	case trending:
+		result.Regime = TrendingDown
+	case result.VolatilityPercentile <= config.QuietVolPercentile:
+		result.Regime = Quiet
+	default:
+		result.Regime = Ranging
+	}
+
+	return result
+}
+
+// Apply filters and reweights signals with the rule for the detected regime.
+// Dropped signal names are recorded on the regime.
+func Apply(signals []types.StrategySignal, regime *types.MarketRegime, rules map[string]types.RegimeRule) []types.StrategySignal {
+	rule, exists := rules[regime.Regime]
+	if !exists {
+		return signals
+	}
+
+	kept := make([]types.StrategySignal, 0, len(signals))
+	for _, signal := range signals {
+		if (len(rule.Enable) > 0 && !matchesAny(signal.Name, rule.Enable)) || matchesAny(signal.Name, rule.Disable) {
+			regime.Disabled = append(regime.Disabled, signal.Name)
+			continue
+		}
+
+		for pattern, multiplier := range rule.Weights {
+			if matches(signal.Name, pattern) {
+				signal.Weight *= multiplier
+			}
+		}
+		kept = append(kept, signal)
+	}
+
+	return kept
+}
+
+// matches reports whether a signal name matches a rule entry;
+// a trailing "*" matches a prefix
+func matches(name, pattern string) bool {
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(name, strings.TrimSuffix(pattern, "*"))
+	}
+	return name == pattern
+}
+
+// matchesAny reports whether a signal name matches any rule entry
+func matchesAny(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matches(name, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// volatilityPercentile ranks the current rolling standard deviation of log
+// returns against every window in the series (0-1).
+// Returns 0.5 with fewer than 10 windows to compare against.
+func volatilityPercentile(ticks []types.Tick, window int) float64 {
+	if window < 2 {
+		window = 2
+	}
+
+	returns := make([]float64, 0, len(ticks))
+	for i := 1; i < len(ticks); i++ {
+		prev, price := ticks[i-1].Price, ticks[i].Price
+		if prev > 0 && price > 0 && !math.IsInf(prev, 0) && !math.IsInf(price, 0) {
+			returns = append(returns, math.Log(price/prev))
+		}
+	}
+
+	if len(returns) < window+10 {
+		return 0.5
+	}
+
+	history := make([]float64, 0, len(returns)-window+1)
+	for end := window; end <= len(returns); end++ {
+		history = append(history, stdDev(returns[end-window:end]))
+	}
+
+	// Mid-rank so a constant series sits at 0.5 rather than at the top
+	current := history[len(history)-1]
+	below, equal := 0, 0
+	for _, v := range history {
+		if v < current {
+			below++
+		} else if v == current {
+			equal++
+		}
+	}
+
+	return (float64(below) + float64(equal)/2) / float64(len(history))
+}
+
+// stdDev returns the population standard deviation
+func stdDev(values []float64) float64 {
+	m := 0.0
+	for _, v := range values {
+		m += v
+	}
+	m /= float64(len(values))
+
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - m) * (v - m)
+	}
+	return math.Sqrt(variance / float64(len(values)))
+}
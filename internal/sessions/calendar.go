@@ -0,0 +1,262 @@
+package sessions
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	_ "time/tzdata" // Session timezones must resolve on hosts without zoneinfo
+
+	"otc-predictor/pkg/types"
+)
+
+// Calendar knows when the forex market and its sessions are open.
+// Sessions are defined in their local timezones so DST shifts them
+// automatically; holidays close the market (currency ALL) or the sessions
+// trading a currency.
+type Calendar struct {
+	risk     types.ForexRisk
+	location *time.Location // Forex week boundary zone
+	rollover clock
+	sessions []session
+	holidays []Holiday
+}
+
+// Holiday is one entry of the holiday list
+type Holiday struct {
+	Date     string `json:"date"`     // "2006-01-02"
+	Currency string `json:"currency"` // "ALL" closes the market
+	Name     string `json:"name"`
+}
+
+type session struct {
+	types.SessionConfig
+	location *time.Location
+	open     clock
+	close    clock
+}
+
+// clock is minutes after local midnight
+type clock int
+
+// NewCalendar builds a calendar and loads the holiday list.
+// A missing holiday file is not an error.
+func NewCalendar(config types.SessionsConfig, risk types.ForexRisk) (*Calendar, error) {
+	location, err := time.LoadLocation(config.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("sessions timezone %q: %w", config.Timezone, err)
+	}
+	rollover, err := parseClock(config.Rollover)
+	if err != nil {
+		return nil, fmt.Errorf("sessions rollover: %w", err)
+	}
+
+	calendar := &Calendar{
+		risk:     risk,
+		location: location,
+		rollover: rollover,
+	}
+
+	for _, cfg := range config.Sessions {
+		s := session{SessionConfig: cfg}
+		if s.location, err = time.LoadLocation(cfg.Timezone); err != nil {
+			return nil, fmt.Errorf("session %s timezone %q: %w", cfg.Name, cfg.Timezone, err)
+		}
+		if s.open, err = parseClock(cfg.Open); err != nil {
+			return nil, fmt.Errorf("session %s open: %w", cfg.Name, err)
+		}
+		if s.close, err = parseClock(cfg.Close); err != nil {
+			return nil, fmt.Errorf("session %s close: %w", cfg.Name, err)
+		}
+		calendar.sessions = append(calendar.sessions, s)
+	}
+
+	if config.HolidaysFile != "" {
+		holidays, err := LoadHolidays(config.HolidaysFile)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		calendar.holidays = holidays
+	}
+
+	return calendar, nil
+}
+
+// LoadHolidays reads a CSV holiday list: date,currency,name.
+// Blank lines, # comments and a "date" header are skipped.
+func LoadHolidays(filename string) ([]Holiday, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var holidays []Holiday
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(strings.ToLower(text), "date") {
+			continue
+		}
+
+		fields := strings.SplitN(text, ",", 3)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("%s:%d: want date,currency,name", filename, line)
+		}
+		date := strings.TrimSpace(fields[0])
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return nil, fmt.Errorf("%s:%d: bad date %q", filename, line, date)
+		}
+
+		holiday := Holiday{Date: date, Currency: strings.ToUpper(strings.TrimSpace(fields[1]))}
+		if len(fields) == 3 {
+			holiday.Name = strings.TrimSpace(fields[2])
+		}
+		holidays = append(holidays, holiday)
+	}
+
+	return holidays, scanner.Err()
+}
+
+// Status reports whether a pair can be traded at t, which sessions are
+// open and the session confidence multiplier
+func (c *Calendar) Status(market string, t time.Time) types.SessionStatus {
+	status := types.SessionStatus{
+		Sessions:   []string{},
+		Multiplier: 0.85, // Between sessions
+		Time:       t,
+	}
+
+	if reason := c.marketClosed(t); reason != "" {
+		status.Reason = reason
+		status.Multiplier = 0
+		return status
+	}
+
+	pair := PairCurrencies(market)
+	home := false
+	asianOnly := true
+	openByName := make(map[string]bool)
+
+	for _, s := range c.sessions {
+		local := t.In(s.location)
+		if holiday := c.holiday(local, s.Currencies); holiday != "" {
+			status.Holidays = append(status.Holidays, fmt.Sprintf("%s (%s closed)", holiday, s.Name))
+			continue
+		}
+		if !s.isOpen(local) {
+			continue
+		}
+
+		status.Sessions = append(status.Sessions, s.Name)
+		openByName[s.Name] = true
+		if !s.Asian {
+			asianOnly = false
+		}
+		for _, currency := range s.Currencies {
+			if currency == pair[0] || currency == pair[1] {
+				home = true
+			}
+		}
+	}
+
+	status.Open = true
+	status.Overlap = openByName["london"] && openByName["new_york"]
+	status.Asian = len(status.Sessions) > 0 && asianOnly
+
+	switch {
+	case status.Overlap:
+		status.Multiplier = 1.10
+		if c.risk.BoostLondonNYOverlap {
+			status.Multiplier = 1.20
+		}
+	case len(status.Sessions) > 0 && !asianOnly:
+		status.Multiplier = 1.10
+	case status.Asian && home:
+		status.Multiplier = 1.00 // Asian pairs are liquid in their own session
+	case status.Asian:
+		status.Multiplier = 0.95
+	}
+
+	if status.Asian && c.risk.AvoidAsianSession {
+		status.Open = false
+		status.Reason = "Asian session only (avoid_asian_session)"
+	}
+
+	return status
+}
+
+// marketClosed returns why the whole forex market is closed at t, if it is.
+// The week runs from Sunday to Friday rollover in the calendar timezone.
+func (c *Calendar) marketClosed(t time.Time) string {
+	local := t.In(c.location)
+	now := clockOf(local)
+
+	switch local.Weekday() {
+	case time.Saturday:
+		return "Forex market closed for the weekend"
+	case time.Sunday:
+		if now < c.rollover {
+			return "Forex market closed for the weekend"
+		}
+	case time.Friday:
+		if now >= c.rollover {
+			return "Forex market closed for the weekend"
+		}
+	}
+
+	if holiday := c.holiday(local, []string{"ALL"}); holiday != "" {
+		return fmt.Sprintf("Forex market closed: %s", holiday)
+	}
+	return ""
+}
+
+// holiday returns the name of a holiday on local's date for any of the currencies
+func (c *Calendar) holiday(local time.Time, currencies []string) string {
+	date := local.Format("2006-01-02")
+	for _, holiday := range c.holidays {
+		if holiday.Date != date {
+			continue
+		}
+		for _, currency := range currencies {
+			if holiday.Currency == currency {
+				if holiday.Name == "" {
+					return holiday.Currency + " holiday"
+				}
+				return holiday.Name
+			}
+		}
+	}
+	return ""
+}
+
+// isOpen reports whether local falls inside the session, which may span midnight
+func (s session) isOpen(local time.Time) bool {
+	now := clockOf(local)
+	if s.open <= s.close {
+		return now >= s.open && now < s.close
+	}
+	return now >= s.open || now < s.close
+}
+
+// PairCurrencies splits a forex symbol such as frxEURUSD into its currencies
+func PairCurrencies(market string) [2]string {
+	symbol := strings.ToUpper(strings.TrimPrefix(market, "frx"))
+	if len(symbol) < 6 {
+		return [2]string{}
+	}
+	return [2]string{symbol[:3], symbol[3:6]}
+}
+
+func parseClock(value string) (clock, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("want HH:MM, got %q", value)
+	}
+	return clock(t.Hour()*60 + t.Minute()), nil
+}
+
+func clockOf(t time.Time) clock {
+	return clock(t.Hour()*60 + t.Minute())
+}
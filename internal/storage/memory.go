@@ -13,19 +13,29 @@ type MemoryStorage struct {
 	results     map[string][]types.TradeResult
 	pending     map[string]*types.PendingPrediction
 	stats       map[string]*types.Stats
+	archive     map[string][]types.Tick
 	mu          sync.RWMutex
 	maxTicks    int
+	maxArchive  int
 }
 
-// NewMemoryStorage creates a new memory storage
-func NewMemoryStorage(maxTicks int) *MemoryStorage {
+// NewMemoryStorage creates a new memory storage.
+// maxTicks bounds the live window used for predictions; maxArchive bounds the
+// longer per-market archive used by background analysis (levels, models).
+func NewMemoryStorage(maxTicks, maxArchive int) *MemoryStorage {
+	if maxArchive < maxTicks {
+		maxArchive = maxTicks
+	}
+
 	return &MemoryStorage{
 		markets:     make(map[string]*types.MarketData),
 		predictions: make(map[string][]types.Prediction),
 		results:     make(map[string][]types.TradeResult),
 		pending:     make(map[string]*types.PendingPrediction),
 		stats:       make(map[string]*types.Stats),
+		archive:     make(map[string][]types.Tick),
 		maxTicks:    maxTicks,
+		maxArchive:  maxArchive,
 	}
 }
 
@@ -49,6 +59,20 @@ func (s *MemoryStorage) AddTick(market string, tick types.Tick) {
 	if len(s.markets[market].Ticks) > s.maxTicks {
 		s.markets[market].Ticks = s.markets[market].Ticks[len(s.markets[market].Ticks)-s.maxTicks:]
 	}
+
+	// Long archive for background analysis
+	s.archive[market] = append(s.archive[market], tick)
+	if len(s.archive[market]) > s.maxArchive {
+		s.archive[market] = s.archive[market][len(s.archive[market])-s.maxArchive:]
+	}
+}
+
+// GetArchiveTicks returns a copy of the long tick archive for a market
+func (s *MemoryStorage) GetArchiveTicks(market string) []types.Tick {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]types.Tick{}, s.archive[market]...)
 }
 
 // GetTicks returns last N ticks for a market
@@ -2,32 +2,78 @@ package strategy
 
 import (
 	"fmt"
+	"log"
 	"math"
+	"otc-predictor/internal/adaptive"
 	"otc-predictor/internal/candles"
+	"otc-predictor/internal/consensus"
 	"otc-predictor/internal/indicators"
+	"otc-predictor/internal/news"
+	"otc-predictor/internal/regime"
 	"otc-predictor/pkg/types"
+	"time"
 )
 
-// CombinedStrategy combines all strategies with mode awareness
+// CombinedStrategy runs the strategies enabled for each market type and
+// combines their signals
 type CombinedStrategy struct {
-	volatilityStrategy *VolatilityStrategy
-	crashBoomStrategy  *CrashBoomStrategy
-	forexStrategy      *ForexStrategy
-	config             types.StrategyConfig
+	strategies map[string][]Strategy // Keyed by market type
+	consensus  *consensus.Combiner
+	news       *news.Service
+	adaptive   *adaptive.Weights
+	config     types.StrategyConfig
 }
 
-// NewCombinedStrategy creates a combined strategy
-func NewCombinedStrategy(config types.StrategyConfig) *CombinedStrategy {
+// NewCombinedStrategy creates a combined strategy from strategy.enabled.
+// Unknown strategy names are logged and skipped.
+func NewCombinedStrategy(deps Dependencies, adaptiveWeights *adaptive.Weights) *CombinedStrategy {
+	config := deps.Config
+
+	strategies := make(map[string][]Strategy)
+	for marketType, defaults := range DefaultStrategies {
+		names, configured := config.Enabled[marketType]
+		if !configured {
+			names = defaults
+		}
+
+		for _, name := range names {
+			factory, exists := Lookup(name)
+			if !exists {
+				log.Printf("⚠️  Unknown strategy %q for %s markets - skipped", name, marketType)
+				continue
+			}
+			strategies[marketType] = append(strategies[marketType], factory(deps))
+		}
+	}
+
+	combiner := consensus.NewCombiner(config.ConsensusFile)
+	if err := combiner.Load(); err != nil {
+		log.Printf("⚠️  Starting with fresh consensus scores: %v", err)
+	}
+
 	return &CombinedStrategy{
-		volatilityStrategy: NewVolatilityStrategy(config),
-		crashBoomStrategy:  NewCrashBoomStrategy(config),
-		forexStrategy:      NewForexStrategy(config),
-		config:             config,
+		strategies: strategies,
+		consensus:  combiner,
+		news:       deps.News,
+		adaptive:   adaptiveWeights,
+		config:     config,
+	}
+}
+
+// Strategies returns the names of the strategies run for a market type
+func (s *CombinedStrategy) Strategies(marketType string) []string {
+	names := []string{}
+	for _, strategy := range s.strategies[marketType] {
+		names = append(names, strategy.Name())
 	}
+	return names
 }
 
 // GeneratePrediction generates prediction with market-type awareness
-func (s *CombinedStrategy) GeneratePrediction(market string, ticks []types.Tick, duration int) types.Prediction {
+func (s *CombinedStrategy) GeneratePrediction(market string, candleData []types.Candle, duration int) types.Prediction {
+	// Indicators work on close prices; OHLC stays available for registry indicators
+	ticks := candles.CandlesToTicks(candleData)
+
 	prediction := types.Prediction{
 		Market:     market,
 		Direction:  "NONE",
@@ -47,16 +93,32 @@ func (s *CombinedStrategy) GeneratePrediction(market string, ticks []types.Tick,
 		return prediction
 	}
 
+	strategies, known := s.strategies[marketType]
+	if !known {
+		prediction.Reason = "Unknown market type"
+		return prediction
+	}
+
+	// Releases turn forex into a coin flip around the announcement
+	if marketType == "forex" {
+		if event, blocked := s.news.Blackout(market, prediction.Timestamp, time.Duration(duration)*time.Second); blocked {
+			prediction.Reason = fmt.Sprintf("News blackout: %s %s (%s impact) at %s",
+				event.Currency, event.Title, event.Impact, event.Time.UTC().Format("15:04 MST"))
+			prediction.ReasonCode = news.ReasonCode
+			prediction.Blackout = event
+			return prediction
+		}
+	}
+
 	// Calculate indicators
 	inds := indicators.CalculateAllIndicators(ticks, s.config)
+	inds.Extra = indicators.ComputeExtra(candleData, s.config.ExtraIndicators)
 	prediction.Indicators = inds
 	prediction.CurrentPrice = ticks[len(ticks)-1].Price
 
-	// ✨ Check for RSI Divergence (very reliable signal)
-	divergence := indicators.DetectRSIDivergence(ticks, s.config)
-
-	// ✨ Check for advanced patterns
-	pattern := indicators.DetectAdvancedPatterns(ticks)
+	// Classify the market regime; its rules gate strategies below
+	marketRegime := regime.Detect(candleData, s.config.Regime)
+	prediction.Regime = &marketRegime
 
 	// ✅ ULTRA-RELAXED pre-filter - only block extreme chaos
 	if !s.isMarketTradeable(inds, ticks, marketType) {
@@ -65,46 +127,54 @@ func (s *CombinedStrategy) GeneratePrediction(market string, ticks []types.Tick,
 		return prediction
 	}
 
-	// Collect signals based on market type
-	var allSignals []types.StrategySignal
+	ctx := Context{
+		Market:     market,
+		MarketType: marketType,
+		Duration:   duration,
+		Candles:    candleData,
+		Ticks:      ticks,
+		Indicators: inds,
+		Timeframe:  candles.GetTimeframeConfig(duration, marketType),
+		Time:       prediction.Timestamp,
+		Regime:     prediction.Regime,
+	}
 
-	// ✨ Add divergence signal if detected (HIGHEST PRIORITY)
-	if divergence.Type != indicators.NoDivergence {
-		divSignal := s.createDivergenceSignal(divergence)
-		if divSignal.Direction != "NONE" {
-			allSignals = append(allSignals, divSignal)
-		}
+	prediction.Parameters = &types.PredictionParameters{
+		Strategies:      s.Strategies(marketType),
+		Weights:         []types.SignalWeight{},
+		ConsensusConfig: s.config.Consensus[marketType],
 	}
 
-	// ✨ Add pattern signal if detected (HIGH PRIORITY)
-	if pattern.Type != indicators.NoPattern {
-		patternSignal := s.createPatternSignal(pattern)
-		if patternSignal.Direction != "NONE" {
-			allSignals = append(allSignals, patternSignal)
+	// Collect signals from every strategy enabled for this market type
+	var allSignals []types.StrategySignal
+	for _, strategy := range strategies {
+		for _, signal := range strategy.Analyze(ctx) {
+			// A configured weight of 0 disables the signal
+			if signal.Weight > 0 {
+				allSignals = append(allSignals, signal)
+			}
 		}
 	}
 
-	switch marketType {
-	case "volatility":
-		allSignals = append(allSignals, s.volatilityStrategy.Analyze(ticks, inds)...)
-
-	case "crash_boom":
-		allSignals = append(allSignals, s.crashBoomStrategy.Analyze(ticks, inds, market)...)
-
-	case "forex":
-		tfConfig := candles.GetTimeframeConfig(duration, "forex")
-		allSignals = append(allSignals, s.forexStrategy.AnalyzeWithTimeframe(ticks, inds, tfConfig, duration)...)
-
-	default:
-		prediction.Reason = "Unknown market type"
+	if len(allSignals) == 0 {
+		prediction.Reason = "No trading signals generated"
+		prediction.Confidence = 0
 		return prediction
 	}
 
+	// Enable, disable or reweight strategies for the current regime
+	allSignals = regime.Apply(allSignals, prediction.Regime, s.config.Regime.Rules)
 	if len(allSignals) == 0 {
-		prediction.Reason = "No trading signals generated"
+		prediction.Reason = fmt.Sprintf("All signals disabled in %s regime", marketRegime.Regime)
 		prediction.Confidence = 0
 		return prediction
 	}
+	// Scale weights by each signal's tracked record on this market type and duration
+	allSignals = s.adaptive.Apply(allSignals, marketType, duration)
+	for _, signal := range allSignals {
+		prediction.Parameters.Weights = append(prediction.Parameters.Weights, types.SignalWeight{Name: signal.Name, Weight: signal.Weight})
+	}
+	prediction.Signals = allSignals
 
 	// Use market-specific consensus rules
 	return s.marketAwareConsensus(allSignals, prediction, marketType)
@@ -201,155 +271,71 @@ func (s *CombinedStrategy) getMarketConditionReason(inds types.Indicators, marke
 	return "Market conditions too unstable"
 }
 
-// marketAwareConsensus applies market-specific consensus rules
-// ✅ ULTRA-FAST: Just need ANY reasonable signal
+// marketAwareConsensus combines signals with the market type's consensus
+// method. Every method's verdict is attached for comparison.
 func (s *CombinedStrategy) marketAwareConsensus(signals []types.StrategySignal, basePrediction types.Prediction, marketType string) types.Prediction {
-	upVotes := 0
-	downVotes := 0
-	upConfidence := 0.0
-	downConfidence := 0.0
-	upWeight := 0.0
-	downWeight := 0.0
-	upReasons := []string{}
-	downReasons := []string{}
-
-	// Collect votes
-	for _, signal := range signals {
-		if signal.Direction == "UP" {
-			upVotes++
-			upConfidence += signal.Confidence * signal.Weight
-			upWeight += signal.Weight
-			upReasons = append(upReasons, fmt.Sprintf("%s(%.0f%%)", signal.Name, signal.Confidence*100))
-		} else if signal.Direction == "DOWN" {
-			downVotes++
-			downConfidence += signal.Confidence * signal.Weight
-			downWeight += signal.Weight
-			downReasons = append(downReasons, fmt.Sprintf("%s(%.0f%%)", signal.Name, signal.Confidence*100))
-		}
+	config := s.config.Consensus[marketType]
+	verdicts := s.consensus.Combine(marketType, signals, config)
+	basePrediction.Consensus = verdicts
+
+	// The selected method's verdict comes first
+	verdict := verdicts[0]
+	if verdict.Direction == "NONE" {
+		return s.noPrediction(basePrediction, verdict.Reason)
 	}
 
-	totalVotes := upVotes + downVotes
-	if totalVotes == 0 {
-		return s.noPrediction(basePrediction, "No directional signals")
-	}
-
-	// ✅ ULTRA-FAST: Accept ANY single clear signal (no consensus needed)
-	requiredAgreement := 0.40 // Just 40% - even minority signals count!
-
-	finalDirection := "NONE"
-	finalConfidence := 0.0
-	finalReasons := []string{}
-	voteRatio := 0.0
-
-	if upVotes > downVotes {
-		voteRatio = float64(upVotes) / float64(totalVotes)
-		if voteRatio >= requiredAgreement {
-			finalDirection = "UP"
-			finalConfidence = upConfidence / upWeight
-			finalReasons = upReasons
-		}
-	} else if downVotes > upVotes {
-		voteRatio = float64(downVotes) / float64(totalVotes)
-		if voteRatio >= requiredAgreement {
-			finalDirection = "DOWN"
-			finalConfidence = downConfidence / downWeight
-			finalReasons = downReasons
-		}
-	}
-
-	// No signal at all
-	if finalDirection == "NONE" {
-		reason := fmt.Sprintf("Conflicting signals: %d UP vs %d DOWN", upVotes, downVotes)
-		return s.noPrediction(basePrediction, reason)
-	}
-
-	// ✅ ULTRA-LOW threshold: Accept weak signals too
-	minConfidence := 0.52 // Was 0.55 - now MUCH lower
-
-	if finalConfidence < minConfidence {
+	if verdict.Confidence < config.MinConfidence {
 		reason := fmt.Sprintf("Confidence %.1f%% below %.1f%% minimum",
-			finalConfidence*100, minConfidence*100)
+			verdict.Confidence*100, config.MinConfidence*100)
 		return s.noPrediction(basePrediction, reason)
 	}
 
 	// Success - return prediction
-	basePrediction.Direction = finalDirection
-	basePrediction.Confidence = finalConfidence
-	basePrediction.Reason = fmt.Sprintf("[%s] %d/%d strategies %s (%.0f%% agreement): %v",
-		marketType, int(voteRatio*float64(totalVotes)), totalVotes, finalDirection,
-		voteRatio*100, finalReasons)
+	basePrediction.Direction = verdict.Direction
+	basePrediction.Confidence = verdict.Confidence
+	basePrediction.Reason = fmt.Sprintf("[%s] %s", marketType, verdict.Reason)
 
 	return basePrediction
 }
 
-// noPrediction creates a "NONE" prediction with reason
-func (s *CombinedStrategy) noPrediction(base types.Prediction, reason string) types.Prediction {
-	base.Direction = "NONE"
-	base.Confidence = 0
-	base.Reason = reason
-	return base
-}
-
-// createDivergenceSignal converts divergence to strategy signal
-func (s *CombinedStrategy) createDivergenceSignal(div indicators.Divergence) types.StrategySignal {
-	signal := types.StrategySignal{
-		Name:   "RSI_Divergence",
-		Weight: 0.55, // HIGHEST WEIGHT
+// Observe scores every consensus method on a resolved prediction and trains
+// the learned ones. up is whether price finished above the entry.
+func (s *CombinedStrategy) Observe(prediction types.Prediction, up bool) {
+	if len(prediction.Signals) == 0 {
+		return
 	}
 
-	switch div.Type {
-	case indicators.BullishDivergence:
-		signal.Direction = "UP"
-		signal.Confidence = div.Confidence
-		signal.Reason = fmt.Sprintf("Bullish RSI divergence (%.0f%% strength)", div.Strength*100)
-
-	case indicators.BearishDivergence:
-		signal.Direction = "DOWN"
-		signal.Confidence = div.Confidence
-		signal.Reason = fmt.Sprintf("Bearish RSI divergence (%.0f%% strength)", div.Strength*100)
-
-	case indicators.HiddenBullishDivergence:
-		signal.Direction = "UP"
-		signal.Confidence = div.Confidence
-		signal.Reason = "Hidden bullish divergence (trend continuation)"
-
-	case indicators.HiddenBearishDivergence:
-		signal.Direction = "DOWN"
-		signal.Confidence = div.Confidence
-		signal.Reason = "Hidden bearish divergence (trend continuation)"
-
-	default:
-		signal.Direction = "NONE"
-	}
-
-	return signal
+	s.consensus.Observe(prediction.MarketType, prediction.Signals, prediction.Consensus, up, s.config.Consensus[prediction.MarketType])
 }
 
-// createPatternSignal converts pattern to strategy signal
-func (s *CombinedStrategy) createPatternSignal(pattern indicators.Pattern) types.StrategySignal {
-	signal := types.StrategySignal{
-		Name:       "Advanced_Pattern",
-		Direction:  pattern.Direction,
-		Confidence: pattern.Confidence,
-		Weight:     0.45, // HIGH WEIGHT
-	}
+// SaveConsensus persists consensus method scores and the stacked model
+func (s *CombinedStrategy) SaveConsensus() error {
+	return s.consensus.Save()
+}
 
-	patternNames := map[indicators.PatternType]string{
-		indicators.HeadAndShoulders:        "Head & Shoulders",
-		indicators.InverseHeadAndShoulders: "Inverse H&S",
-		indicators.DoubleTop:               "Double Top",
-		indicators.DoubleBottom:            "Double Bottom",
-		indicators.TripleTop:               "Triple Top",
-		indicators.TripleBottom:            "Triple Bottom",
-		indicators.AscendingTriangle:       "Ascending Triangle",
-		indicators.DescendingTriangle:      "Descending Triangle",
-	}
+// ConsensusReport is a market type's consensus settings and how each method
+// would have scored on the same resolved predictions
+type ConsensusReport struct {
+	Config types.ConsensusConfig            `json:"config"`
+	Scores map[string]consensus.MethodScore `json:"scores"`
+}
 
-	if name, exists := patternNames[pattern.Type]; exists {
-		signal.Reason = fmt.Sprintf("%s pattern detected (%.0f%% quality)", name, pattern.Strength*100)
-	} else {
-		signal.Reason = "Chart pattern detected"
+// ConsensusReport returns consensus settings and scores per market type
+func (s *CombinedStrategy) ConsensusReport() map[string]ConsensusReport {
+	report := make(map[string]ConsensusReport)
+	for marketType := range DefaultStrategies {
+		report[marketType] = ConsensusReport{
+			Config: s.config.Consensus[marketType],
+			Scores: s.consensus.Scores(marketType),
+		}
 	}
+	return report
+}
 
-	return signal
+// noPrediction creates a "NONE" prediction with reason
+func (s *CombinedStrategy) noPrediction(base types.Prediction, reason string) types.Prediction {
+	base.Direction = "NONE"
+	base.Confidence = 0
+	base.Reason = reason
+	return base
 }
@@ -1,27 +1,40 @@
 package strategy
 
 import (
+	"fmt"
 	"math"
+	"otc-predictor/internal/hazard"
 	"otc-predictor/pkg/types"
 	"strings"
 )
 
+const (
+	postSpikeTicks        = 12 // Ticks after a spike the recovery signal trades
+	recentVolatilityTicks = 15 // Short volatility window of the pre-spike signal
+)
+
 // CrashBoomStrategy for Crash/Boom indices
 type CrashBoomStrategy struct {
 	config types.StrategyConfig
+	hazard *hazard.Service
 }
 
 // NewCrashBoomStrategy creates a new crash/boom strategy
-func NewCrashBoomStrategy(config types.StrategyConfig) *CrashBoomStrategy {
+func NewCrashBoomStrategy(config types.StrategyConfig, hazardService *hazard.Service) *CrashBoomStrategy {
 	return &CrashBoomStrategy{
 		config: config,
+		hazard: hazardService,
 	}
 }
 
+// Name returns the registry name
+func (s *CrashBoomStrategy) Name() string { return "crash_boom" }
+
 // Analyze generates signals for crash/boom indices
 // 🔧 FIXED: Adjusted all confidence levels to match new 58% threshold
-func (s *CrashBoomStrategy) Analyze(ticks []types.Tick, inds types.Indicators, market string) []types.StrategySignal {
+func (s *CrashBoomStrategy) Analyze(ctx Context) []types.StrategySignal {
 	signals := []types.StrategySignal{}
+	ticks, inds, market := ctx.Ticks, ctx.Indicators, ctx.Market
 
 	if len(ticks) < 50 { // Was 60 - reduced minimum
 		return signals
@@ -30,13 +43,20 @@ func (s *CrashBoomStrategy) Analyze(ticks []types.Tick, inds types.Indicators, m
 	isCrash := strings.Contains(strings.ToLower(market), "crash")
 	isBoom := strings.Contains(strings.ToLower(market), "boom")
 
-	// Get spike statistics
+	// Spike rate fitted on the long archive, if the market has enough history
+	model, hasModel := s.hazard.Model(market)
+
+	// Where the last spike in the window is, for the post-spike signals
 	spikeStats := s.analyzeSpikePattern(ticks)
 
-	// Strategy 1: Spike prediction (HIGHEST CONFIDENCE)
-	spikeSignal := s.spikeDetectionSignal(ticks, spikeStats, isCrash, isBoom)
-	if spikeSignal.Direction != "NONE" {
-		signals = append(signals, spikeSignal)
+	// Strategy 1: Spike hazard over the contract duration. Spikes arrive
+	// independently of the time since the last one, so this is the only
+	// spike-timing vote.
+	if hasModel {
+		hazardSignal := s.spikeHazardSignal(model.Forecast(ctx.Duration), model)
+		if hazardSignal.Direction != "NONE" {
+			signals = append(signals, hazardSignal)
+		}
 	}
 
 	// Strategy 2: Between-spike trend (MEDIUM CONFIDENCE)
@@ -60,18 +80,16 @@ func (s *CrashBoomStrategy) Analyze(ticks []types.Tick, inds types.Indicators, m
 	return signals
 }
 
-// SpikeStats holds spike pattern information
+// SpikeStats holds spike pattern information. There is no average interval:
+// spikes are memoryless, so time since the last one says nothing about the
+// next; only the hazard model times them.
 type SpikeStats struct {
 	LastSpikeIdx    int
-	AvgInterval     float64
 	TicksSinceSpike int
-	SpikeDue        bool
 	RecentSpikes    []int
-	Confidence      float64
 }
 
-// analyzeSpikePattern analyzes the spike pattern
-// 🔧 FIXED: Adjusted confidence calculations
+// analyzeSpikePattern finds the spikes in the window
 func (s *CrashBoomStrategy) analyzeSpikePattern(ticks []types.Tick) SpikeStats {
 	stats := SpikeStats{
 		LastSpikeIdx: -1,
@@ -96,31 +114,31 @@ func (s *CrashBoomStrategy) analyzeSpikePattern(ticks []types.Tick) SpikeStats {
 	stats.LastSpikeIdx = stats.RecentSpikes[len(stats.RecentSpikes)-1]
 	stats.TicksSinceSpike = len(ticks) - stats.LastSpikeIdx - 1
 
-	// Calculate average interval
-	if len(stats.RecentSpikes) >= 2 {
-		totalInterval := 0.0
-		for i := 1; i < len(stats.RecentSpikes); i++ {
-			totalInterval += float64(stats.RecentSpikes[i] - stats.RecentSpikes[i-1])
-		}
-		stats.AvgInterval = totalInterval / float64(len(stats.RecentSpikes)-1)
-
-		// ✅ FIXED: Relaxed spike due window (85-110% → 80-115%)
-		progress := float64(stats.TicksSinceSpike) / stats.AvgInterval
-		if progress >= 0.80 && progress <= 1.15 {
-			stats.SpikeDue = true
-			// ✅ Adjusted confidence range (0.55-0.75 → 0.50-0.70)
-			stats.Confidence = math.Min(0.70, 0.50+(progress-0.80)*0.57)
-		}
-	} else {
-		// Only one spike found
-		stats.AvgInterval = 70.0
-		if stats.TicksSinceSpike >= 55 { // Was 60
-			stats.SpikeDue = true
-			stats.Confidence = 0.58 // Was 0.62
-		}
+	return stats
+}
+
+// spikeHazardSignal trades the archive-fitted probability of the price
+// finishing up or down over the contract duration
+func (s *CrashBoomStrategy) spikeHazardSignal(forecast hazard.Forecast, model hazard.Model) types.StrategySignal {
+	signal := types.StrategySignal{
+		Name:      "SpikeHazard",
+		Weight:    s.config.CrashBoom.HazardWeight,
+		Direction: "NONE",
 	}
 
-	return stats
+	direction, probability := "UP", forecast.ProbabilityUp
+	if forecast.ProbabilityDown > probability {
+		direction, probability = "DOWN", forecast.ProbabilityDown
+	}
+	if probability < 0.55 {
+		return signal
+	}
+
+	signal.Direction = direction
+	signal.Confidence = math.Min(0.80, probability)
+	signal.Reason = fmt.Sprintf("%.1f%% %s spike chance in %d ticks (1 per %.0f)",
+		forecast.SpikeProbability*100, model.Kind, forecast.Ticks, model.MeanInterval)
+	return signal
 }
 
 // isSpikePoint detects if a point is a spike
@@ -153,70 +171,17 @@ func (s *CrashBoomStrategy) isSpikePoint(ticks []types.Tick, idx int) bool {
 	return (changeFromBefore > 0.022 || changeToAfter > 0.022) && immediateChange > 0.013
 }
 
-// spikeDetectionSignal predicts when spike is imminent
-// 🔧 FIXED: Adjusted confidence levels
-func (s *CrashBoomStrategy) spikeDetectionSignal(ticks []types.Tick, stats SpikeStats, isCrash, isBoom bool) types.StrategySignal {
-	signal := types.StrategySignal{
-		Name:   "SpikeDetection",
-		Weight: 0.50,
-	}
-
-	if !stats.SpikeDue {
-		signal.Direction = "NONE"
-		return signal
-	}
-
-	recentVolatility := s.calculateRecentVolatility(ticks, 10)
-	baseline := s.calculateRecentVolatility(ticks, 30)
-
-	volatilityIncreasing := recentVolatility > baseline*1.12 // Was 1.15
-
-	confidence := stats.Confidence
-
-	if volatilityIncreasing {
-		confidence += 0.05 // Was 0.06
-	}
-
-	// ✅ FIXED: Relaxed from 0.95 to 0.92
-	if stats.TicksSinceSpike >= int(stats.AvgInterval*0.92) {
-		confidence += 0.04
-	}
-
-	if isCrash {
-		signal.Direction = "DOWN"
-		signal.Confidence = math.Min(0.74, confidence) // Was 0.78
-		signal.Reason = "Crash spike imminent (pattern-based)"
-		return signal
-	}
-
-	if isBoom {
-		signal.Direction = "UP"
-		signal.Confidence = math.Min(0.74, confidence) // Was 0.78
-		signal.Reason = "Boom spike imminent (pattern-based)"
-		return signal
-	}
-
-	signal.Direction = "NONE"
-	return signal
-}
-
 // betweenSpikeTrendSignal for the drift period between spikes
 // 🔧 FIXED: Adjusted confidence and relaxed conditions
 func (s *CrashBoomStrategy) betweenSpikeTrendSignal(ticks []types.Tick, inds types.Indicators, stats SpikeStats, isCrash, isBoom bool) types.StrategySignal {
 	signal := types.StrategySignal{
 		Name:   "BetweenSpikeTrend",
-		Weight: 0.30,
-	}
-
-	if stats.LastSpikeIdx == -1 || stats.AvgInterval == 0 {
-		signal.Direction = "NONE"
-		return signal
+		Weight: s.config.CrashBoom.TrendWeight,
 	}
 
-	progress := float64(stats.TicksSinceSpike) / stats.AvgInterval
-
-	// ✅ FIXED: Expanded window (20-75% → 15-80%)
-	if progress < 0.15 || progress > 0.80 {
+	// Right after a spike the recovery signal trades; the drift itself holds
+	// at any distance from the last spike
+	if stats.LastSpikeIdx != -1 && stats.TicksSinceSpike <= postSpikeTicks {
 		signal.Direction = "NONE"
 		return signal
 	}
@@ -225,11 +190,6 @@ func (s *CrashBoomStrategy) betweenSpikeTrendSignal(ticks []types.Tick, inds typ
 	if isCrash && inds.RSI < 68 && inds.Momentum >= -0.0015 { // Was 65 / -0.001
 		confidence := 0.62 // Was 0.66
 
-		// Stronger confidence in mid-range
-		if progress >= 0.30 && progress <= 0.65 { // Was 0.35-0.60
-			confidence = 0.66 // Was 0.70
-		}
-
 		// Trend confirmation
 		if inds.EMA9 > inds.EMA21 {
 			confidence += 0.04
@@ -245,11 +205,6 @@ func (s *CrashBoomStrategy) betweenSpikeTrendSignal(ticks []types.Tick, inds typ
 	if isBoom && inds.RSI > 32 && inds.Momentum <= 0.0015 { // Was 35 / 0.001
 		confidence := 0.62 // Was 0.66
 
-		// Stronger confidence in mid-range
-		if progress >= 0.30 && progress <= 0.65 {
-			confidence = 0.66 // Was 0.70
-		}
-
 		// Trend confirmation
 		if inds.EMA9 < inds.EMA21 {
 			confidence += 0.04
@@ -265,28 +220,22 @@ func (s *CrashBoomStrategy) betweenSpikeTrendSignal(ticks []types.Tick, inds typ
 	return signal
 }
 
-// preSpikeVolatilitySignal - Detects volatility changes before spike
+// preSpikeVolatilitySignal - Detects volatility changes before spike. It
+// reads the current volatility only, not how long ago the last spike was.
 // 🔧 FIXED: Adjusted confidence and thresholds
 func (s *CrashBoomStrategy) preSpikeVolatilitySignal(ticks []types.Tick, inds types.Indicators, stats SpikeStats, isCrash, isBoom bool) types.StrategySignal {
 	signal := types.StrategySignal{
 		Name:   "PreSpikeVolatility",
-		Weight: 0.25,
+		Weight: s.config.CrashBoom.VolatilityWeight,
 	}
 
-	if stats.LastSpikeIdx == -1 || stats.AvgInterval == 0 {
-		signal.Direction = "NONE"
-		return signal
-	}
-
-	progress := float64(stats.TicksSinceSpike) / stats.AvgInterval
-
-	// ✅ FIXED: Expanded window (80-105% → 75-110%)
-	if progress < 0.75 || progress > 1.10 {
+	// A spike inside the short window is the surge itself, not a lead-in
+	if stats.LastSpikeIdx != -1 && stats.TicksSinceSpike < recentVolatilityTicks {
 		signal.Direction = "NONE"
 		return signal
 	}
 
-	recentVol := s.calculateRecentVolatility(ticks, 15)
+	recentVol := s.calculateRecentVolatility(ticks, recentVolatilityTicks)
 	baselineVol := s.calculateRecentVolatility(ticks, 50)
 
 	// ✅ FIXED: Relaxed from 1.25 to 1.20
@@ -322,7 +271,7 @@ func (s *CrashBoomStrategy) preSpikeVolatilitySignal(ticks []types.Tick, inds ty
 func (s *CrashBoomStrategy) postSpikeRecoverySignal(ticks []types.Tick, stats SpikeStats, isCrash, isBoom bool) types.StrategySignal {
 	signal := types.StrategySignal{
 		Name:   "PostSpikeRecovery",
-		Weight: 0.20,
+		Weight: s.config.CrashBoom.RecoveryWeight,
 	}
 
 	if stats.LastSpikeIdx == -1 {
@@ -331,7 +280,7 @@ func (s *CrashBoomStrategy) postSpikeRecoverySignal(ticks []types.Tick, stats Sp
 	}
 
 	// ✅ FIXED: Expanded window (1-10 → 1-12 ticks)
-	if stats.TicksSinceSpike < 1 || stats.TicksSinceSpike > 12 {
+	if stats.TicksSinceSpike < 1 || stats.TicksSinceSpike > postSpikeTicks {
 		signal.Direction = "NONE"
 		return signal
 	}
@@ -5,26 +5,39 @@ import (
 	"math"
 	"otc-predictor/internal/candles"
 	"otc-predictor/internal/indicators"
+	"otc-predictor/internal/levels"
+	"otc-predictor/internal/sessions"
+	"otc-predictor/internal/strength"
 	"otc-predictor/pkg/types"
 	"strings"
-	"time"
 )
 
 // ForexStrategy for Forex pairs (Rise/Fall contracts)
 type ForexStrategy struct {
-	config types.StrategyConfig
+	config   types.StrategyConfig
+	levels   *levels.Service
+	sessions *sessions.Calendar
+	strength *strength.Meter
 }
 
 // NewForexStrategy creates a new forex strategy
-func NewForexStrategy(config types.StrategyConfig) *ForexStrategy {
+func NewForexStrategy(config types.StrategyConfig, levelService *levels.Service, calendar *sessions.Calendar, meter *strength.Meter) *ForexStrategy {
 	return &ForexStrategy{
-		config: config,
+		config:   config,
+		levels:   levelService,
+		sessions: calendar,
+		strength: meter,
 	}
 }
 
-// AnalyzeWithTimeframe generates signals with timeframe awareness
-func (s *ForexStrategy) AnalyzeWithTimeframe(ticks []types.Tick, inds types.Indicators, tfConfig candles.TimeframeConfig, duration int) []types.StrategySignal {
+// Name returns the registry name
+func (s *ForexStrategy) Name() string { return "forex" }
+
+// Analyze generates signals with timeframe awareness
+func (s *ForexStrategy) Analyze(ctx Context) []types.StrategySignal {
 	signals := []types.StrategySignal{}
+	market, ticks, inds := ctx.Market, ctx.Ticks, ctx.Indicators
+	tfConfig, duration := ctx.Timeframe, ctx.Duration
 
 	if len(ticks) < tfConfig.RSIPeriod*2 {
 		return signals
@@ -34,7 +47,7 @@ func (s *ForexStrategy) AnalyzeWithTimeframe(ticks []types.Tick, inds types.Indi
 	currentTime := ticks[len(ticks)-1].Timestamp
 
 	// Check trading session quality
-	sessionMultiplier := s.getSessionMultiplier(currentTime)
+	sessionMultiplier := s.sessions.Status(market, currentTime).Multiplier
 
 	// Check if market conditions are favorable
 	if !s.isFavorableCondition(inds, sessionMultiplier) {
@@ -54,7 +67,7 @@ func (s *ForexStrategy) AnalyzeWithTimeframe(ticks []types.Tick, inds types.Indi
 	}
 
 	// Strategy 3: Support/Resistance Bounces (CONSERVATIVE)
-	srSignal := s.conservativeSRSignal(ticks, currentPrice, inds, tfConfig)
+	srSignal := s.conservativeSRSignal(market, ticks, currentPrice, inds, tfConfig)
 	if srSignal.Direction != "NONE" {
 		signals = append(signals, srSignal)
 	}
@@ -65,9 +78,45 @@ func (s *ForexStrategy) AnalyzeWithTimeframe(ticks []types.Tick, inds types.Indi
 		signals = append(signals, momentumSignal)
 	}
 
+	// Strategy 5: Currency strength differential across all pairs
+	strengthSignal := s.strengthDifferentialSignal(market, sessionMultiplier)
+	if strengthSignal.Direction != "NONE" {
+		signals = append(signals, strengthSignal)
+	}
+
 	return signals
 }
 
+// strengthDifferentialSignal follows the stronger currency of the pair as
+// measured on every other subscribed pair
+func (s *ForexStrategy) strengthDifferentialSignal(market string, sessionMult float64) types.StrategySignal {
+	signal := types.StrategySignal{
+		Name:      "StrengthDifferential",
+		Weight:    s.config.Forex.StrengthWeight,
+		Direction: "NONE",
+	}
+
+	differential, ok := s.strength.Current().Differential(market)
+	minimum := s.strength.MinDifferential()
+	if !ok || math.Abs(differential) < minimum {
+		return signal
+	}
+
+	// 0.60 at the threshold, rising with the gap up to 0.74
+	confidence := math.Min(0.74, 0.60+0.04*(math.Abs(differential)/minimum-1))
+	signal.Confidence = math.Min(0.80, confidence*sessionMult)
+
+	currencies := sessions.PairCurrencies(market)
+	if differential > 0 {
+		signal.Direction = "UP"
+		signal.Reason = fmt.Sprintf("%s stronger than %s by %.2f%%", currencies[0], currencies[1], differential)
+	} else {
+		signal.Direction = "DOWN"
+		signal.Reason = fmt.Sprintf("%s weaker than %s by %.2f%%", currencies[0], currencies[1], -differential)
+	}
+	return signal
+}
+
 // isFavorableCondition checks if market conditions are good for trading
 func (s *ForexStrategy) isFavorableCondition(inds types.Indicators, sessionMult float64) bool {
 	// Avoid extreme volatility
@@ -88,34 +137,11 @@ func (s *ForexStrategy) isFavorableCondition(inds types.Indicators, sessionMult
 	return true
 }
 
-// getSessionMultiplier returns confidence multiplier based on trading session
-func (s *ForexStrategy) getSessionMultiplier(t time.Time) float64 {
-	hour := t.UTC().Hour()
-
-	// Best sessions (London/NY overlap): 12:00-16:00 UTC
-	if hour >= 12 && hour < 16 {
-		return 1.20 // 20% boost
-	}
-
-	// Good sessions (London: 8-12, NY: 13-20 UTC)
-	if (hour >= 8 && hour < 12) || (hour >= 13 && hour < 20) {
-		return 1.10 // 10% boost
-	}
-
-	// Asian session (quieter): 0-7 UTC
-	if hour >= 0 && hour < 7 {
-		return 0.90 // 10% reduction
-	}
-
-	// Off hours
-	return 0.85 // 15% reduction
-}
-
 // strongTrendSignal - Only signals in VERY strong trends
 func (s *ForexStrategy) strongTrendSignal(ticks []types.Tick, inds types.Indicators, sessionMult float64, duration int) types.StrategySignal {
 	signal := types.StrategySignal{
 		Name:   "StrongTrend",
-		Weight: 0.45,
+		Weight: s.config.Forex.TrendFollowingWeight,
 	}
 
 	// Calculate trend strength score
@@ -237,7 +263,7 @@ func (s *ForexStrategy) strongTrendSignal(ticks []types.Tick, inds types.Indicat
 func (s *ForexStrategy) confirmedCrossoverSignal(ticks []types.Tick, inds types.Indicators, sessionMult float64, tfConfig candles.TimeframeConfig) types.StrategySignal {
 	signal := types.StrategySignal{
 		Name:   "ConfirmedCrossover",
-		Weight: 0.40,
+		Weight: s.config.Forex.EMACrossoverWeight,
 	}
 
 	lookback := 15
@@ -330,28 +356,32 @@ func (s *ForexStrategy) confirmedCrossoverSignal(ticks []types.Tick, inds types.
 }
 
 // conservativeSRSignal - Very conservative S/R bounces
-func (s *ForexStrategy) conservativeSRSignal(ticks []types.Tick, currentPrice float64, inds types.Indicators, tfConfig candles.TimeframeConfig) types.StrategySignal {
+func (s *ForexStrategy) conservativeSRSignal(market string, ticks []types.Tick, currentPrice float64, inds types.Indicators, tfConfig candles.TimeframeConfig) types.StrategySignal {
 	signal := types.StrategySignal{
 		Name:   "SupportResistance",
-		Weight: 0.35,
+		Weight: s.config.Forex.SupportResistanceWeight,
 	}
 
-	levels := s.findKeyLevels(ticks, tfConfig.LookbackPeriod)
+	support, resistance := s.keyLevels(market, ticks, tfConfig.LookbackPeriod)
 
 	// Support bounce
-	for _, level := range levels.Support {
-		distance := math.Abs((currentPrice - level) / level)
+	for _, level := range support {
+		distance := math.Abs((currentPrice - level.Price) / level.Price)
 		if distance < 0.0015 { // Within 0.15%
 			strength := 0
 
 			// Multiple touches = stronger level
-			touchCount := s.countLevelTouches(ticks, level, 0.002)
-			if touchCount >= 3 {
+			if level.Touches >= 3 {
 				strength += 3
-			} else if touchCount >= 2 {
+			} else if level.Touches >= 2 {
 				strength += 2
 			}
 
+			// Old resistance now holding as support
+			if level.Flips > 0 {
+				strength += 1
+			}
+
 			// RSI oversold
 			if inds.RSI < 38 {
 				strength += 2
@@ -373,25 +403,28 @@ func (s *ForexStrategy) conservativeSRSignal(ticks []types.Tick, currentPrice fl
 				confidence := 0.63 + float64(strength-6)*0.02
 				signal.Direction = "UP"
 				signal.Confidence = math.Min(0.80, confidence)
-				signal.Reason = fmt.Sprintf("Strong support bounce (%d touches)", touchCount)
+				signal.Reason = fmt.Sprintf("Strong support bounce (%s)", describeLevel(level))
 				return signal
 			}
 		}
 	}
 
 	// Resistance rejection
-	for _, level := range levels.Resistance {
-		distance := math.Abs((currentPrice - level) / level)
+	for _, level := range resistance {
+		distance := math.Abs((currentPrice - level.Price) / level.Price)
 		if distance < 0.0015 {
 			strength := 0
 
-			touchCount := s.countLevelTouches(ticks, level, 0.002)
-			if touchCount >= 3 {
+			if level.Touches >= 3 {
 				strength += 3
-			} else if touchCount >= 2 {
+			} else if level.Touches >= 2 {
 				strength += 2
 			}
 
+			if level.Flips > 0 {
+				strength += 1
+			}
+
 			if inds.RSI > 62 {
 				strength += 2
 			} else if inds.RSI > 55 {
@@ -410,7 +443,7 @@ func (s *ForexStrategy) conservativeSRSignal(ticks []types.Tick, currentPrice fl
 				confidence := 0.63 + float64(strength-6)*0.02
 				signal.Direction = "DOWN"
 				signal.Confidence = math.Min(0.80, confidence)
-				signal.Reason = fmt.Sprintf("Strong resistance rejection (%d touches)", touchCount)
+				signal.Reason = fmt.Sprintf("Strong resistance rejection (%s)", describeLevel(level))
 				return signal
 			}
 		}
@@ -420,11 +453,59 @@ func (s *ForexStrategy) conservativeSRSignal(ticks []types.Tick, currentPrice fl
 	return signal
 }
 
+// keyLevels returns support and resistance from the level service, falling
+// back to swing points of the recent ticks before the service has data
+func (s *ForexStrategy) keyLevels(market string, ticks []types.Tick, lookback int) ([]levels.Level, []levels.Level) {
+	if s.levels != nil {
+		if snapshot, ok := s.levels.GetLevels(market); ok && len(snapshot.Levels) > 0 {
+			support := []levels.Level{}
+			resistance := []levels.Level{}
+			for _, level := range snapshot.Levels {
+				if level.Kind == levels.Support {
+					support = append(support, level)
+				} else {
+					resistance = append(resistance, level)
+				}
+			}
+			return support, resistance
+		}
+	}
+
+	recent := findKeyLevels(ticks, lookback)
+	toLevels := func(prices []float64, kind levels.Kind) []levels.Level {
+		out := make([]levels.Level, 0, len(prices))
+		for _, price := range prices {
+			out = append(out, levels.Level{
+				Price:   price,
+				Kind:    kind,
+				Source:  levels.SourceSwing,
+				Touches: s.countLevelTouches(ticks, price, 0.002),
+			})
+		}
+		return out
+	}
+
+	return toLevels(recent.Support, levels.Support), toLevels(recent.Resistance, levels.Resistance)
+}
+
+// describeLevel summarises a level for signal reasons
+func describeLevel(level levels.Level) string {
+	parts := []string{}
+	if level.Source != levels.SourceSwing {
+		parts = append(parts, strings.TrimSpace(string(level.Source)+" "+level.Label))
+	}
+	parts = append(parts, fmt.Sprintf("%d touches", level.Touches))
+	if level.Flips > 0 {
+		parts = append(parts, "flipped")
+	}
+	return strings.Join(parts, ", ")
+}
+
 // momentumContinuationSignal - Trade with strong momentum
 func (s *ForexStrategy) momentumContinuationSignal(ticks []types.Tick, inds types.Indicators, sessionMult float64) types.StrategySignal {
 	signal := types.StrategySignal{
 		Name:   "MomentumContinuation",
-		Weight: 0.30,
+		Weight: s.config.Forex.PullbackWeight,
 	}
 
 	// Strong upward momentum
@@ -490,7 +571,7 @@ type KeyLevels struct {
 }
 
 // findKeyLevels identifies support and resistance from price action
-func (s *ForexStrategy) findKeyLevels(ticks []types.Tick, lookback int) KeyLevels {
+func findKeyLevels(ticks []types.Tick, lookback int) KeyLevels {
 	levels := KeyLevels{
 		Support:    []float64{},
 		Resistance: []float64{},
@@ -0,0 +1,477 @@
+package strategy
+
+import (
+	"fmt"
+	"math"
+	"otc-predictor/internal/correlation"
+	"otc-predictor/internal/indicators"
+	"otc-predictor/internal/kalman"
+	"otc-predictor/internal/levels"
+	"otc-predictor/internal/markov"
+	"otc-predictor/pkg/types"
+	"strings"
+)
+
+// Signals shared by every market type: divergence, chart patterns,
+// candlesticks, tracked support/resistance levels, cross-market lead-lag
+// the Kalman trend and tick-direction Markov chains
+
+// DivergenceStrategy trades RSI divergences
+type DivergenceStrategy struct {
+	config types.StrategyConfig
+}
+
+// Name returns the registry name
+func (s *DivergenceStrategy) Name() string { return "divergence" }
+
+// Analyze emits a signal when RSI diverges from price
+func (s *DivergenceStrategy) Analyze(ctx Context) []types.StrategySignal {
+	divergence := indicators.DetectRSIDivergence(ctx.Ticks, s.config)
+	if divergence.Type == indicators.NoDivergence {
+		return nil
+	}
+
+	if signal := s.createDivergenceSignal(divergence, s.config.SignalWeights(ctx.MarketType).DivergenceWeight); signal.Direction != "NONE" {
+		return []types.StrategySignal{signal}
+	}
+	return nil
+}
+
+// ChartPatternStrategy trades completed chart patterns
+type ChartPatternStrategy struct {
+	config types.StrategyConfig
+}
+
+// Name returns the registry name
+func (s *ChartPatternStrategy) Name() string { return "chart_patterns" }
+
+// Analyze emits a signal for the highest-priority chart pattern
+func (s *ChartPatternStrategy) Analyze(ctx Context) []types.StrategySignal {
+	pattern := indicators.DetectAdvancedPatterns(ctx.Ticks)
+	if pattern.Type == indicators.NoPattern {
+		return nil
+	}
+
+	if signal := s.createPatternSignal(pattern, s.config.SignalWeights(ctx.MarketType).PatternWeight); signal.Direction != "NONE" {
+		return []types.StrategySignal{signal}
+	}
+	return nil
+}
+
+// CandlestickStrategy trades candlestick patterns in level context
+type CandlestickStrategy struct {
+	config types.StrategyConfig
+	levels *levels.Service
+}
+
+// Name returns the registry name
+func (s *CandlestickStrategy) Name() string { return "candlesticks" }
+
+// Analyze emits one signal for the strongest candlestick pattern on the last
+// candles (needs real OHLC candles). Patterns completing on the same bar read
+// the same candles, so the others only add their names to the reason rather
+// than voting again.
+func (s *CandlestickStrategy) Analyze(ctx Context) []types.StrategySignal {
+	var signals []types.StrategySignal
+	var names []string
+	weight := s.config.SignalWeights(ctx.MarketType).CandlestickWeight
+	for _, candle := range indicators.DetectCandlestickPatterns(ctx.Candles) {
+		signal := s.createCandlestickSignal(ctx.Market, candle, ctx.Ticks, ctx.Indicators, weight)
+		if signal.Direction != "NONE" {
+			signals = append(signals, signal)
+			names = append(names, candlestickNames[candle.Type])
+		}
+	}
+	if len(signals) == 0 {
+		return nil
+	}
+
+	best := 0
+	for i := range signals {
+		if signals[i].Confidence > signals[best].Confidence {
+			best = i
+		}
+	}
+
+	var agreeing []string
+	for i, signal := range signals {
+		if i != best && signal.Direction == signals[best].Direction {
+			agreeing = append(agreeing, names[i])
+		}
+	}
+	if len(agreeing) > 0 {
+		signals[best].Reason = fmt.Sprintf("%s; also %s", signals[best].Reason, strings.Join(agreeing, ", "))
+	}
+	return signals[best : best+1]
+}
+
+// LevelStrategy trades bounces off tracked support/resistance levels
+type LevelStrategy struct {
+	config types.StrategyConfig
+	levels *levels.Service
+}
+
+// Name returns the registry name
+func (s *LevelStrategy) Name() string { return "support_resistance" }
+
+// Analyze emits a signal when price is stretched into a proven level
+func (s *LevelStrategy) Analyze(ctx Context) []types.StrategySignal {
+	if signal := s.createLevelSignal(ctx.Market, ctx.Price(), ctx.Indicators, s.config.SignalWeights(ctx.MarketType).LevelWeight); signal.Direction != "NONE" {
+		return []types.StrategySignal{signal}
+	}
+	return nil
+}
+
+// LeadLagStrategy follows a leader market that has just moved
+type LeadLagStrategy struct {
+	config   types.StrategyConfig
+	analyzer *correlation.Analyzer
+}
+
+// Name returns the registry name
+func (s *LeadLagStrategy) Name() string { return "lead_lag" }
+
+// Analyze emits a signal for the strongest recent leader move, in the
+// direction the lagged correlation says the market will follow
+func (s *LeadLagStrategy) Analyze(ctx Context) []types.StrategySignal {
+	moves := s.analyzer.RecentMoves(ctx.Market, ctx.Time.Add(ctx.Timeframe.CandlePeriod))
+	if len(moves) == 0 {
+		return nil
+	}
+
+	best := moves[0]
+	for _, move := range moves[1:] {
+		if math.Abs(move.Correlation*move.ZScore) > math.Abs(best.Correlation*best.ZScore) {
+			best = move
+		}
+	}
+
+	direction := "UP"
+	if best.Correlation*best.Return < 0 {
+		direction = "DOWN"
+	}
+	verb := "rose"
+	if best.Return < 0 {
+		verb = "fell"
+	}
+
+	return []types.StrategySignal{{
+		Name:       "LeadLag",
+		Direction:  direction,
+		Confidence: math.Min(0.75, 0.58+0.25*math.Abs(best.Correlation)),
+		Weight:     s.config.SignalWeights(ctx.MarketType).LeadLagWeight,
+		Reason: fmt.Sprintf("%s %s %.1fσ; leads by %d bars (r=%.2f)",
+			best.Leader, verb, math.Abs(best.ZScore), best.Lag, best.Correlation),
+	}}
+}
+
+// KalmanTrendStrategy follows the slope of a local-linear-trend Kalman filter
+type KalmanTrendStrategy struct {
+	config  types.StrategyConfig
+	filters *kalman.Service
+}
+
+// Name returns the registry name
+func (s *KalmanTrendStrategy) Name() string { return "kalman_trend" }
+
+// Analyze updates the market's filter with the latest candles and signals
+// when the slope carried over the duration is at least MinZ deviations from
+// zero; confidence follows the projected probability of the move's sign
+func (s *KalmanTrendStrategy) Analyze(ctx Context) []types.StrategySignal {
+	estimate, ok := s.filters.Update(ctx.Market, ctx.Timeframe.CandlePeriod, ctx.Candles)
+	if !ok {
+		return nil
+	}
+
+	projection := estimate.Project(ctx.Duration)
+	if math.Abs(projection.ZScore) < s.config.Kalman.MinZ {
+		return nil
+	}
+
+	direction, probability := "UP", projection.ProbabilityUp
+	if projection.ZScore < 0 {
+		direction, probability = "DOWN", 1-projection.ProbabilityUp
+	}
+
+	return []types.StrategySignal{{
+		Name:       "KalmanTrend",
+		Direction:  direction,
+		Confidence: math.Min(0.78, 0.5+0.6*(probability-0.5)),
+		Weight:     s.config.SignalWeights(ctx.MarketType).KalmanWeight,
+		Reason: fmt.Sprintf("Kalman slope %.3g/candle ±%.2g, %.1fσ over %ds",
+			estimate.Slope, estimate.SlopeSigma, math.Abs(projection.ZScore), ctx.Duration),
+	}}
+}
+
+// TickMarkovStrategy follows tick-direction dependence in synthetic indices
+type TickMarkovStrategy struct {
+	config types.StrategyConfig
+	chains *markov.Service
+}
+
+// Name returns the registry name
+func (s *TickMarkovStrategy) Name() string { return "tick_markov" }
+
+// Analyze signals the direction the fitted chain expects over the duration,
+// at the chain's own probability of ending that way. The chain only forecasts
+// when its chi-square test rejected i.i.d. ticks and the effect is large
+// enough to act on.
+func (s *TickMarkovStrategy) Analyze(ctx Context) []types.StrategySignal {
+	forecast, ok := s.chains.Forecast(ctx.Market, ctx.Duration)
+	if !ok || !forecast.Actionable {
+		return nil
+	}
+
+	direction, probability := "UP", forecast.ProbabilityUp
+	if probability < 0.5 {
+		direction, probability = "DOWN", 1-probability
+	}
+
+	return []types.StrategySignal{{
+		Name:       "TickMarkov",
+		Direction:  direction,
+		Confidence: math.Min(0.78, probability),
+		Weight:     s.config.SignalWeights(ctx.MarketType).MarkovWeight,
+		Reason: fmt.Sprintf("Order-%d chain after %s: next up %.1f%%, %+.1f ticks over %d (χ²=%.1f, df=%d, p=%.2g)",
+			forecast.Order, forecast.Context, forecast.NextUp*100, forecast.ExpectedSteps, forecast.Ticks,
+			forecast.ChiSquare, forecast.DF, forecast.PValue),
+	}}
+}
+
+// createDivergenceSignal converts divergence to strategy signal
+func (s *DivergenceStrategy) createDivergenceSignal(div indicators.Divergence, weight float64) types.StrategySignal {
+	signal := types.StrategySignal{
+		Name:   "RSI_Divergence",
+		Weight: weight,
+	}
+
+	switch div.Type {
+	case indicators.BullishDivergence:
+		signal.Direction = "UP"
+		signal.Confidence = div.Confidence
+		signal.Reason = fmt.Sprintf("Bullish RSI divergence (%.0f%% strength)", div.Strength*100)
+
+	case indicators.BearishDivergence:
+		signal.Direction = "DOWN"
+		signal.Confidence = div.Confidence
+		signal.Reason = fmt.Sprintf("Bearish RSI divergence (%.0f%% strength)", div.Strength*100)
+
+	case indicators.HiddenBullishDivergence:
+		signal.Direction = "UP"
+		signal.Confidence = div.Confidence
+		signal.Reason = "Hidden bullish divergence (trend continuation)"
+
+	case indicators.HiddenBearishDivergence:
+		signal.Direction = "DOWN"
+		signal.Confidence = div.Confidence
+		signal.Reason = "Hidden bearish divergence (trend continuation)"
+
+	default:
+		signal.Direction = "NONE"
+	}
+
+	return signal
+}
+
+// createPatternSignal converts pattern to strategy signal
+func (s *ChartPatternStrategy) createPatternSignal(pattern indicators.Pattern, weight float64) types.StrategySignal {
+	signal := types.StrategySignal{
+		Name:       "Advanced_Pattern",
+		Direction:  pattern.Direction,
+		Confidence: pattern.Confidence,
+		Weight:     weight,
+	}
+
+	patternNames := map[indicators.PatternType]string{
+		indicators.HeadAndShoulders:        "Head & Shoulders",
+		indicators.InverseHeadAndShoulders: "Inverse H&S",
+		indicators.DoubleTop:               "Double Top",
+		indicators.DoubleBottom:            "Double Bottom",
+		indicators.TripleTop:               "Triple Top",
+		indicators.TripleBottom:            "Triple Bottom",
+		indicators.AscendingTriangle:       "Ascending Triangle",
+		indicators.DescendingTriangle:      "Descending Triangle",
+		indicators.RisingWedge:             "Rising Wedge",
+		indicators.FallingWedge:            "Falling Wedge",
+		indicators.BullFlag:                "Bull Flag",
+		indicators.BearFlag:                "Bear Flag",
+	}
+
+	if name, exists := patternNames[pattern.Type]; exists {
+		signal.Reason = fmt.Sprintf("%s pattern detected (%.0f%% quality)", name, pattern.Strength*100)
+	} else {
+		signal.Reason = "Chart pattern detected"
+	}
+
+	return signal
+}
+
+// candlestickNames maps candlestick patterns to display names
+var candlestickNames = map[indicators.CandlestickType]string{
+	indicators.BullishEngulfing:   "Bullish Engulfing",
+	indicators.BearishEngulfing:   "Bearish Engulfing",
+	indicators.Hammer:             "Hammer",
+	indicators.HangingMan:         "Hanging Man",
+	indicators.InvertedHammer:     "Inverted Hammer",
+	indicators.ShootingStar:       "Shooting Star",
+	indicators.BullishPinBar:      "Bullish Pin Bar",
+	indicators.BearishPinBar:      "Bearish Pin Bar",
+	indicators.Doji:               "Doji",
+	indicators.DragonflyDoji:      "Dragonfly Doji",
+	indicators.GravestoneDoji:     "Gravestone Doji",
+	indicators.MorningStar:        "Morning Star",
+	indicators.EveningStar:        "Evening Star",
+	indicators.ThreeWhiteSoldiers: "Three White Soldiers",
+	indicators.ThreeBlackCrows:    "Three Black Crows",
+	indicators.BullishHarami:      "Bullish Harami",
+	indicators.BearishHarami:      "Bearish Harami",
+	indicators.PiercingLine:       "Piercing Line",
+	indicators.DarkCloudCover:     "Dark Cloud Cover",
+	indicators.TweezerBottom:      "Tweezer Bottom",
+	indicators.TweezerTop:         "Tweezer Top",
+	indicators.InsideBar:          "Inside Bar",
+	indicators.BullishOutsideBar:  "Bullish Outside Bar",
+	indicators.BearishOutsideBar:  "Bearish Outside Bar",
+}
+
+// createCandlestickSignal converts a candlestick pattern to a strategy signal.
+// Location matters more than shape: patterns at S/R or BB extremes get boosted,
+// patterns fighting a level get cut, and indecision patterns (doji, inside bar)
+// only become directional at a level.
+func (s *CandlestickStrategy) createCandlestickSignal(market string, pattern indicators.CandlestickPattern, ticks []types.Tick, inds types.Indicators, weight float64) types.StrategySignal {
+	signal := types.StrategySignal{
+		Name:   "Candle_" + string(pattern.Type),
+		Weight: weight,
+	}
+
+	currentPrice := ticks[len(ticks)-1].Price
+	atSupport, atResistance := s.nearKeyLevel(market, ticks, currentPrice)
+	atLowerBand := inds.BBPosition <= -0.8
+	atUpperBand := inds.BBPosition >= 0.8
+
+	direction := pattern.Direction
+	if direction == "NONE" {
+		switch {
+		case atSupport || atLowerBand:
+			direction = "UP"
+		case atResistance || atUpperBand:
+			direction = "DOWN"
+		default:
+			signal.Direction = "NONE"
+			return signal
+		}
+	}
+
+	confidence := pattern.Confidence
+	context := []string{}
+
+	if direction == "UP" {
+		if atSupport {
+			confidence += 0.04
+			context = append(context, "at support")
+		}
+		if atLowerBand {
+			confidence += 0.03
+			context = append(context, "at lower BB")
+		}
+		if atResistance {
+			confidence -= 0.03
+			context = append(context, "into resistance")
+		}
+	} else {
+		if atResistance {
+			confidence += 0.04
+			context = append(context, "at resistance")
+		}
+		if atUpperBand {
+			confidence += 0.03
+			context = append(context, "at upper BB")
+		}
+		if atSupport {
+			confidence -= 0.03
+			context = append(context, "into support")
+		}
+	}
+
+	name := candlestickNames[pattern.Type]
+	signal.Direction = direction
+	signal.Confidence = math.Min(0.74, confidence)
+	if len(context) > 0 {
+		signal.Reason = fmt.Sprintf("%s (%s)", name, strings.Join(context, ", "))
+	} else {
+		signal.Reason = fmt.Sprintf("%s (no level context)", name)
+	}
+
+	return signal
+}
+
+// nearKeyLevel reports whether price sits in the zone of a tracked support or
+// resistance level, or within 0.15% of a recent swing before levels are built
+func (s *CandlestickStrategy) nearKeyLevel(market string, ticks []types.Tick, price float64) (bool, bool) {
+	if s.levels != nil {
+		if snapshot, ok := s.levels.GetLevels(market); ok && len(snapshot.Levels) > 0 {
+			support, resistance := snapshot.Near(price)
+			return len(support) > 0, len(resistance) > 0
+		}
+	}
+
+	recent := findKeyLevels(ticks, 50)
+	atSupport := false
+	atResistance := false
+
+	for _, level := range recent.Support {
+		if math.Abs(price-level)/level < 0.0015 {
+			atSupport = true
+		}
+	}
+	for _, level := range recent.Resistance {
+		if math.Abs(price-level)/level < 0.0015 {
+			atResistance = true
+		}
+	}
+
+	return atSupport, atResistance
+}
+
+// createLevelSignal trades bounces off tracked levels on synthetics.
+// Needs a level with a history (touches or a flip) and RSI/BB agreeing that
+// price is stretched into it.
+func (s *LevelStrategy) createLevelSignal(market string, price float64, inds types.Indicators, weight float64) types.StrategySignal {
+	signal := types.StrategySignal{
+		Name:      "SupportResistance",
+		Direction: "NONE",
+		Weight:    weight,
+	}
+
+	if s.levels == nil {
+		return signal
+	}
+	snapshot, ok := s.levels.GetLevels(market)
+	if !ok {
+		return signal
+	}
+
+	support, resistance := snapshot.Near(price)
+
+	if len(support) > 0 && inds.RSI < 45 && inds.BBPosition < -0.3 {
+		level := support[0]
+		if level.Touches >= 2 || level.Flips > 0 {
+			signal.Direction = "UP"
+			signal.Confidence = math.Min(0.76, 0.62+level.Strength*0.10)
+			signal.Reason = fmt.Sprintf("Bounce off support %.5f (%s)", level.Price, describeLevel(level))
+			return signal
+		}
+	}
+
+	if len(resistance) > 0 && inds.RSI > 55 && inds.BBPosition > 0.3 {
+		level := resistance[0]
+		if level.Touches >= 2 || level.Flips > 0 {
+			signal.Direction = "DOWN"
+			signal.Confidence = math.Min(0.76, 0.62+level.Strength*0.10)
+			signal.Reason = fmt.Sprintf("Rejection at resistance %.5f (%s)", level.Price, describeLevel(level))
+			return signal
+		}
+	}
+
+	return signal
+}
@@ -0,0 +1,153 @@
+package strategy
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"otc-predictor/internal/candles"
+	"otc-predictor/internal/correlation"
+	"otc-predictor/internal/hazard"
+	"otc-predictor/internal/kalman"
+	"otc-predictor/internal/levels"
+	"otc-predictor/internal/markov"
+	"otc-predictor/internal/news"
+	"otc-predictor/internal/sessions"
+	"otc-predictor/internal/strength"
+	"otc-predictor/pkg/types"
+)
+
+// Context is everything a strategy can look at for one prediction
+type Context struct {
+	Market     string
+	MarketType string // "forex", "volatility", "crash_boom"
+	Duration   int    // Contract duration in seconds
+	Candles    []types.Candle
+	Ticks      []types.Tick // Candle closes, for tick-based indicators
+	Indicators types.Indicators
+	Timeframe  candles.TimeframeConfig
+	Time       time.Time // Time of the latest candle
+	Regime     *types.MarketRegime
+}
+
+// Price returns the latest close
+func (c Context) Price() float64 {
+	return c.Ticks[len(c.Ticks)-1].Price
+}
+
+// Strategy produces signals for a prediction
+type Strategy interface {
+	Name() string
+	Analyze(ctx Context) []types.StrategySignal
+}
+
+// Dependencies are the shared services handed to strategy factories
+type Dependencies struct {
+	Config      types.StrategyConfig
+	Levels      *levels.Service
+	Sessions    *sessions.Calendar
+	News        *news.Service
+	Hazard      *hazard.Service
+	Strength    *strength.Meter
+	Correlation *correlation.Analyzer
+	Kalman      *kalman.Service
+	Markov      *markov.Service
+}
+
+// Factory builds a strategy from shared dependencies
+type Factory func(deps Dependencies) Strategy
+
+var (
+	registry   = make(map[string]Factory)
+	registryMu sync.RWMutex
+)
+
+// Register adds a strategy factory under a name usable in strategy.enabled
+func Register(name string, factory Factory) error {
+	if name == "" {
+		return fmt.Errorf("strategy name is required")
+	}
+	if factory == nil {
+		return fmt.Errorf("strategy %s has no factory", name)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[name]; exists {
+		return fmt.Errorf("strategy %s already registered", name)
+	}
+	registry[name] = factory
+	return nil
+}
+
+// MustRegister registers a strategy and panics on error
+func MustRegister(name string, factory Factory) {
+	if err := Register(name, factory); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns a registered strategy factory
+func Lookup(name string) (Factory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	factory, exists := registry[name]
+	return factory, exists
+}
+
+// Registered returns all registered strategy names sorted
+func Registered() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DefaultStrategies are the strategies run per market type when
+// strategy.enabled doesn't list one
+var DefaultStrategies = map[string][]string{
+	"volatility": {"divergence", "chart_patterns", "candlesticks", "volatility", "support_resistance", "kalman_trend", "tick_markov"},
+	"crash_boom": {"divergence", "chart_patterns", "candlesticks", "crash_boom", "support_resistance", "tick_markov"},
+	"forex":      {"divergence", "chart_patterns", "candlesticks", "forex", "lead_lag", "kalman_trend"},
+}
+
+func init() {
+	MustRegister("volatility", func(deps Dependencies) Strategy {
+		return NewVolatilityStrategy(deps.Config)
+	})
+	MustRegister("crash_boom", func(deps Dependencies) Strategy {
+		return NewCrashBoomStrategy(deps.Config, deps.Hazard)
+	})
+	MustRegister("forex", func(deps Dependencies) Strategy {
+		return NewForexStrategy(deps.Config, deps.Levels, deps.Sessions, deps.Strength)
+	})
+	MustRegister("divergence", func(deps Dependencies) Strategy {
+		return &DivergenceStrategy{config: deps.Config}
+	})
+	MustRegister("chart_patterns", func(deps Dependencies) Strategy {
+		return &ChartPatternStrategy{config: deps.Config}
+	})
+	MustRegister("candlesticks", func(deps Dependencies) Strategy {
+		return &CandlestickStrategy{config: deps.Config, levels: deps.Levels}
+	})
+	MustRegister("support_resistance", func(deps Dependencies) Strategy {
+		return &LevelStrategy{config: deps.Config, levels: deps.Levels}
+	})
+	MustRegister("lead_lag", func(deps Dependencies) Strategy {
+		return &LeadLagStrategy{config: deps.Config, analyzer: deps.Correlation}
+	})
+	MustRegister("kalman_trend", func(deps Dependencies) Strategy {
+		return &KalmanTrendStrategy{config: deps.Config, filters: deps.Kalman}
+	})
+	MustRegister("tick_markov", func(deps Dependencies) Strategy {
+		return &TickMarkovStrategy{config: deps.Config, chains: deps.Markov}
+	})
+}
@@ -1,9 +1,12 @@
 package strategy
 
 import (
+	"fmt"
 	"otc-predictor/internal/indicators"
+	"otc-predictor/internal/meanreversion"
 	"otc-predictor/pkg/types"
 	"strings"
+	"time"
 )
 
 // VolatilityStrategy for Volatility indices (V10, V25, V50, V75, V100)
@@ -18,9 +21,13 @@ func NewVolatilityStrategy(config types.StrategyConfig) *VolatilityStrategy {
 	}
 }
 
+// Name returns the registry name
+func (s *VolatilityStrategy) Name() string { return "volatility" }
+
 // Analyze generates signals for volatility indices
-func (s *VolatilityStrategy) Analyze(ticks []types.Tick, inds types.Indicators) []types.StrategySignal {
+func (s *VolatilityStrategy) Analyze(ctx Context) []types.StrategySignal {
 	signals := []types.StrategySignal{}
+	ticks, inds := ctx.Ticks, ctx.Indicators
 
 	if len(ticks) < s.config.RSIPeriod*2 {
 		return signals
@@ -30,7 +37,8 @@ func (s *VolatilityStrategy) Analyze(ticks []types.Tick, inds types.Indicators)
 	pattern := indicators.DetectPattern(ticks)
 
 	// Strategy 1: Mean Reversion (STRONGEST for volatility indices)
-	meanReversionSignal := s.meanReversionSignal(currentPrice, inds, pattern)
+	reversion := meanreversion.FitCandles(ctx.Candles, int(ctx.Timeframe.CandlePeriod/time.Second))
+	meanReversionSignal := s.meanReversionSignal(inds, pattern, reversion, ctx.Duration)
 	if meanReversionSignal.Direction != "NONE" {
 		signals = append(signals, meanReversionSignal)
 	}
@@ -64,14 +72,23 @@ func (s *VolatilityStrategy) Analyze(ticks []types.Tick, inds types.Indicators)
 
 // meanReversionSignal - Price returns to mean (HIGHEST WEIGHT)
 // 🔧 FIXED: Adjusted confidence to match new 58% threshold
-func (s *VolatilityStrategy) meanReversionSignal(price float64, inds types.Indicators, pattern string) types.StrategySignal {
+// Only fires when the OU fit says the series is mean reverting, half the
+// gap to the mean closes within the contract, and price sits at least
+// MinZScore deviations on the side of the mean the call reverts from
+func (s *VolatilityStrategy) meanReversionSignal(inds types.Indicators, pattern string, fit meanreversion.Fit, duration int) types.StrategySignal {
 	signal := types.StrategySignal{
 		Name:   "MeanReversion",
-		Weight: 0.45,
+		Weight: s.config.Volatility.MeanReversionWeight,
+	}
+
+	if !fit.Within(duration) {
+		signal.Direction = "NONE"
+		return signal
 	}
+	detail := fmt.Sprintf(" (z %.2f, half-life %.0fs)", fit.ZScore, fit.HalfLife)
 
 	// ✅ FIXED: Relaxed from -0.75 to -0.70 for more signals
-	if inds.RSI < s.config.RSIOversold && inds.BBPosition < -0.70 {
+	if inds.RSI < s.config.RSIOversold && inds.BBPosition < -0.70 && fit.ZScore <= -meanreversion.MinZScore {
 		confidence := 0.68 // Was 0.72 - adjusted down
 
 		// Pattern confirmation
@@ -89,14 +106,19 @@ func (s *VolatilityStrategy) meanReversionSignal(price float64, inds types.Indic
 			confidence += 0.03
 		}
 
+		// Stretched below the fitted mean
+		if fit.ZScore < -2 {
+			confidence += 0.03
+		}
+
 		signal.Direction = "UP"
 		signal.Confidence = confidence
-		signal.Reason = "Strong oversold mean reversion signal"
+		signal.Reason = "Strong oversold mean reversion signal" + detail
 		return signal
 	}
 
 	// ✅ FIXED: Relaxed from 0.75 to 0.70 for more signals
-	if inds.RSI > s.config.RSIOverbought && inds.BBPosition > 0.70 {
+	if inds.RSI > s.config.RSIOverbought && inds.BBPosition > 0.70 && fit.ZScore >= meanreversion.MinZScore {
 		confidence := 0.68 // Was 0.72
 
 		// Pattern confirmation
@@ -114,9 +136,14 @@ func (s *VolatilityStrategy) meanReversionSignal(price float64, inds types.Indic
 			confidence += 0.03
 		}
 
+		// Stretched above the fitted mean
+		if fit.ZScore > 2 {
+			confidence += 0.03
+		}
+
 		signal.Direction = "DOWN"
 		signal.Confidence = confidence
-		signal.Reason = "Strong overbought mean reversion signal"
+		signal.Reason = "Strong overbought mean reversion signal" + detail
 		return signal
 	}
 
@@ -129,7 +156,7 @@ func (s *VolatilityStrategy) meanReversionSignal(price float64, inds types.Indic
 func (s *VolatilityStrategy) momentumSignal(inds types.Indicators) types.StrategySignal {
 	signal := types.StrategySignal{
 		Name:   "Momentum",
-		Weight: 0.35,
+		Weight: s.config.Volatility.MomentumWeight,
 	}
 
 	// ✅ FIXED: Relaxed RSI range (48-62 → 45-65)
@@ -187,7 +214,7 @@ func (s *VolatilityStrategy) momentumSignal(inds types.Indicators) types.Strateg
 func (s *VolatilityStrategy) bollingerBandSignal(price float64, inds types.Indicators) types.StrategySignal {
 	signal := types.StrategySignal{
 		Name:   "BollingerBands",
-		Weight: 0.25,
+		Weight: s.config.Volatility.BollingerWeight,
 	}
 
 	// ✅ FIXED: Relaxed from -0.85 to -0.80
@@ -247,7 +274,7 @@ func (s *VolatilityStrategy) bollingerBandSignal(price float64, inds types.Indic
 func (s *VolatilityStrategy) rsiSignal(inds types.Indicators, ticks []types.Tick) types.StrategySignal {
 	signal := types.StrategySignal{
 		Name:   "RSI",
-		Weight: 0.15,
+		Weight: s.config.Volatility.RSIWeight,
 	}
 
 	// Check RSI trend (is it turning?)
@@ -307,7 +334,7 @@ func (s *VolatilityStrategy) rsiSignal(inds types.Indicators, ticks []types.Tick
 func (s *VolatilityStrategy) emaCrossoverSignal(ticks []types.Tick, inds types.Indicators) types.StrategySignal {
 	signal := types.StrategySignal{
 		Name:   "EMACrossover",
-		Weight: 0.30,
+		Weight: s.config.Volatility.EMACrossoverWeight,
 	}
 
 	if len(ticks) < s.config.RSIPeriod+10 {
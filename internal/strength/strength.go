@@ -0,0 +1,263 @@
+package strength
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"otc-predictor/internal/sessions"
+	"otc-predictor/internal/storage"
+	"otc-predictor/pkg/types"
+)
+
+// Meter measures currency strength across every subscribed forex pair.
+// A pair's log return over the lookback is credited to its base currency and
+// debited from its quote currency; a currency's strength is the mean over
+// the pairs it trades in, so EUR strength is how EUR did against the basket.
+// The snapshot strategies read is measured once per Refresh.
+type Meter struct {
+	storage *storage.MemoryStorage
+	config  types.StrengthConfig
+	current *Snapshot
+	mu      sync.RWMutex
+}
+
+// Snapshot is the strength of every currency and the triangulation check
+type Snapshot struct {
+	Lookback    int                `json:"lookback_minutes"`
+	Pairs       int                `json:"pairs"` // Pairs with enough history
+	Currencies  []CurrencyStrength `json:"currencies"`
+	Triangles   int                `json:"triangles_checked"`
+	Mispricings []Triangle         `json:"mispricings"` // Triangles beyond tolerance
+	Time        time.Time          `json:"time"`
+
+	sums    map[string]float64 // Credited minus debited log returns per currency
+	counts  map[string]int     // Pairs per currency
+	changes map[string]float64 // Log return per pair
+}
+
+// CurrencyStrength is one currency's rolling strength
+type CurrencyStrength struct {
+	Currency string  `json:"currency"`
+	Strength float64 `json:"strength"` // Mean percent move against the basket
+	Pairs    int     `json:"pairs"`
+	Rank     int     `json:"rank"` // 1 = strongest
+}
+
+// Triangle compares a cross rate with the one implied through a third currency
+type Triangle struct {
+	Cross     string  `json:"cross"` // e.g. frxEURJPY
+	Via       string  `json:"via"`   // e.g. USD
+	Actual    float64 `json:"actual"`
+	Implied   float64 `json:"implied"`
+	Deviation float64 `json:"deviation_bps"` // (implied/actual - 1) in basis points
+}
+
+// pair is one subscribed pair's current price and lookback return
+type pair struct {
+	market      string
+	base, quote string
+	price       float64
+	change      float64 // Log return over the lookback
+}
+
+// NewMeter creates a strength meter over the forex pairs in storage
+func NewMeter(store *storage.MemoryStorage, config types.StrengthConfig) *Meter {
+	return &Meter{storage: store, config: config}
+}
+
+// MinDifferential is the strength gap (percent) worth trading
+func (m *Meter) MinDifferential() float64 {
+	return m.config.MinDifferential
+}
+
+// Refresh measures strength over the lookback ending now and keeps it for
+// Current
+func (m *Meter) Refresh() {
+	snapshot := m.Snapshot(time.Now())
+
+	m.mu.Lock()
+	m.current = &snapshot
+	m.mu.Unlock()
+}
+
+// Current returns the snapshot from the last Refresh, measuring one if
+// there hasn't been a refresh yet
+func (m *Meter) Current() Snapshot {
+	m.mu.RLock()
+	current := m.current
+	m.mu.RUnlock()
+
+	if current == nil {
+		m.Refresh()
+		m.mu.RLock()
+		current = m.current
+		m.mu.RUnlock()
+	}
+	return *current
+}
+
+// Snapshot measures strength over the lookback ending at now
+func (m *Meter) Snapshot(now time.Time) Snapshot {
+	pairs := m.pairs(now)
+
+	snapshot := Snapshot{
+		Lookback:    m.config.LookbackMinutes,
+		Pairs:       len(pairs),
+		Currencies:  []CurrencyStrength{},
+		Mispricings: []Triangle{},
+		Time:        now,
+		sums:        make(map[string]float64),
+		counts:      make(map[string]int),
+		changes:     make(map[string]float64),
+	}
+
+	for _, p := range pairs {
+		snapshot.sums[p.base] += p.change
+		snapshot.sums[p.quote] -= p.change
+		snapshot.counts[p.base]++
+		snapshot.counts[p.quote]++
+		snapshot.changes[p.market] = p.change
+	}
+	for currency, sum := range snapshot.sums {
+		snapshot.Currencies = append(snapshot.Currencies, CurrencyStrength{
+			Currency: currency,
+			Strength: sum / float64(snapshot.counts[currency]) * 100,
+			Pairs:    snapshot.counts[currency],
+		})
+	}
+	sort.Slice(snapshot.Currencies, func(i, j int) bool {
+		return snapshot.Currencies[i].Strength > snapshot.Currencies[j].Strength
+	})
+	for i := range snapshot.Currencies {
+		snapshot.Currencies[i].Rank = i + 1
+	}
+
+	snapshot.Triangles, snapshot.Mispricings = m.triangulate(pairs)
+	return snapshot
+}
+
+// Differential returns base minus quote strength for a pair in percent,
+// measured on every other pair. Leaving the pair itself out keeps its own
+// move, which would count once for each currency, out of the reading, so
+// the differential is what the rest of the market says. Returns false if
+// either currency trades in no other pair.
+func (s Snapshot) Differential(market string) (float64, bool) {
+	currencies := sessions.PairCurrencies(market)
+	baseSum, baseCount := s.sums[currencies[0]], s.counts[currencies[0]]
+	quoteSum, quoteCount := s.sums[currencies[1]], s.counts[currencies[1]]
+
+	if change, measured := s.changes[market]; measured {
+		baseSum -= change
+		baseCount--
+		quoteSum += change
+		quoteCount--
+	}
+	if baseCount == 0 || quoteCount == 0 {
+		return 0, false
+	}
+	return (baseSum/float64(baseCount) - quoteSum/float64(quoteCount)) * 100, true
+}
+
+// pairs collects every forex pair whose live window covers at least half
+// the lookback
+func (m *Meter) pairs(now time.Time) []pair {
+	lookback := time.Duration(m.config.LookbackMinutes) * time.Minute
+	start := now.Add(-lookback)
+
+	markets := m.storage.GetActiveMarkets()
+	sort.Strings(markets)
+
+	var pairs []pair
+	for _, market := range markets {
+		if !strings.HasPrefix(market, "frx") {
+			continue
+		}
+		currencies := sessions.PairCurrencies(market)
+		if currencies[0] == "" {
+			continue
+		}
+
+		ticks := m.storage.GetAllTicks(market)
+		if len(ticks) < 2 || ticks[0].Timestamp.After(now.Add(-lookback/2)) {
+			continue
+		}
+
+		// Price at the start of the lookback (or the oldest tick) and now
+		first := ticks[0]
+		for _, tick := range ticks {
+			if tick.Timestamp.After(start) {
+				break
+			}
+			first = tick
+		}
+		last := ticks[len(ticks)-1]
+		if first.Price <= 0 || last.Price <= 0 {
+			continue
+		}
+
+		pairs = append(pairs, pair{
+			market: market,
+			base:   currencies[0],
+			quote:  currencies[1],
+			price:  last.Price,
+			change: math.Log(last.Price / first.Price),
+		})
+	}
+	return pairs
+}
+
+// triangulate checks each pair against the rate implied through every third
+// currency quoted against both of its legs. Each currency triangle is checked
+// once, from the first of its pairs.
+func (m *Meter) triangulate(pairs []pair) (int, []Triangle) {
+	rates := make(map[[2]string]float64)
+	currencies := make(map[string]bool)
+	for _, p := range pairs {
+		rates[[2]string{p.base, p.quote}] = p.price
+		rates[[2]string{p.quote, p.base}] = 1 / p.price
+		currencies[p.base] = true
+		currencies[p.quote] = true
+	}
+
+	checked := 0
+	seen := make(map[[3]string]bool)
+	mispricings := []Triangle{}
+	for _, p := range pairs {
+		for via := range currencies {
+			first, ok1 := rates[[2]string{p.base, via}]
+			second, ok2 := rates[[2]string{via, p.quote}]
+			if via == p.base || via == p.quote || !ok1 || !ok2 {
+				continue
+			}
+
+			triangle := []string{p.base, p.quote, via}
+			sort.Strings(triangle)
+			key := [3]string{triangle[0], triangle[1], triangle[2]}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			checked++
+
+			implied := first * second
+			deviation := (implied/p.price - 1) * 10000
+			if math.Abs(deviation) > m.config.TriangulationToleranceBps {
+				mispricings = append(mispricings, Triangle{
+					Cross:     p.market,
+					Via:       via,
+					Actual:    p.price,
+					Implied:   implied,
+					Deviation: deviation,
+				})
+			}
+		}
+	}
+
+	sort.Slice(mispricings, func(i, j int) bool {
+		return math.Abs(mispricings[i].Deviation) > math.Abs(mispricings[j].Deviation)
+	})
+	return checked, mispricings
+}
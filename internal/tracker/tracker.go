@@ -3,24 +3,39 @@ package tracker
 import (
 	"fmt"
 	"log"
+	"math"
 	"otc-predictor/internal/storage"
 	"otc-predictor/pkg/types"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
 // ResultTracker tracks prediction outcomes
 type ResultTracker struct {
-	storage *storage.MemoryStorage
+	storage     *storage.MemoryStorage
+	listeners   []func(types.TradeResult)
+	signalStats map[string]*types.SignalStats // Keyed by name|market type|duration
+	mu          sync.RWMutex
 }
 
 // NewResultTracker creates a new result tracker
 func NewResultTracker(storage *storage.MemoryStorage) *ResultTracker {
 	return &ResultTracker{
-		storage: storage,
+		storage:     storage,
+		signalStats: make(map[string]*types.SignalStats),
 	}
 }
 
+// OnResult registers a callback run for every resolved prediction
+func (t *ResultTracker) OnResult(listener func(types.TradeResult)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.listeners = append(t.listeners, listener)
+}
+
 // TrackPrediction starts tracking a prediction
 func (t *ResultTracker) TrackPrediction(pred types.Prediction, currentPrice float64) {
 	if pred.Direction == "NONE" {
@@ -28,14 +43,22 @@ func (t *ResultTracker) TrackPrediction(pred types.Prediction, currentPrice floa
 	}
 
 	pending := &types.PendingPrediction{
-		ID:         pred.ID,
-		Market:     pred.Market,
-		Direction:  pred.Direction,
-		EntryPrice: currentPrice,
-		EntryTime:  pred.Timestamp,
-		Duration:   pred.Duration,
-		Confidence: pred.Confidence,
-		ExpiryTime: pred.Timestamp.Add(time.Duration(pred.Duration) * time.Second),
+		ID:            pred.ID,
+		Market:        pred.Market,
+		MarketType:    pred.MarketType,
+		Direction:     pred.Direction,
+		EntryPrice:    currentPrice,
+		EntryTime:     pred.Timestamp,
+		Duration:      pred.Duration,
+		Confidence:    pred.Confidence,
+		RawConfidence: pred.RawConfidence,
+		Payout:        pred.Payout,
+		ExpiryTime:    pred.Timestamp.Add(time.Duration(pred.Duration) * time.Second),
+		Signals:       pred.Signals,
+	}
+
+	if pred.Baseline != nil {
+		pending.Baseline = pred.Baseline.Probability
 	}
 
 	t.storage.StorePendingPrediction(pending)
@@ -67,26 +90,35 @@ func (t *ResultTracker) checkResultLater(pending *types.PendingPrediction) {
 		won = true
 	}
 
-	// Calculate P/L (assuming $10 stake, 85% payout)
+	// Calculate P/L (assuming $10 stake at the contract's payout)
+	payout := pending.Payout
+	if payout == 0 {
+		payout = 0.85
+	}
 	profitLoss := -10.0
 	if won {
-		profitLoss = 8.5 // 85% profit
+		profitLoss = 10 * payout
 	}
 
 	// Create result
 	result := types.TradeResult{
-		PredictionID: pending.ID,
-		Market:       pending.Market,
-		Direction:    pending.Direction,
-		EntryPrice:   pending.EntryPrice,
-		ExitPrice:    currentPrice,
-		EntryTime:    pending.EntryTime,
-		ExitTime:     time.Now(),
-		Duration:     pending.Duration,
-		Confidence:   pending.Confidence,
-		Won:          won,
-		ProfitLoss:   profitLoss,
-		PriceChange:  priceChange,
+		PredictionID:  pending.ID,
+		Market:        pending.Market,
+		MarketType:    pending.MarketType,
+		Direction:     pending.Direction,
+		EntryPrice:    pending.EntryPrice,
+		ExitPrice:     currentPrice,
+		EntryTime:     pending.EntryTime,
+		ExitTime:      time.Now(),
+		Duration:      pending.Duration,
+		Confidence:    pending.Confidence,
+		RawConfidence: pending.RawConfidence,
+		Won:           won,
+		ProfitLoss:    profitLoss,
+		PriceChange:   priceChange,
+		Signals:       pending.Signals,
+
+		BaselineProbability: pending.Baseline,
 	}
 
 	// Store result
@@ -106,6 +138,14 @@ func (t *ResultTracker) checkResultLater(pending *types.PendingPrediction) {
 
 	// Update statistics
 	t.UpdateStats(pending.Market)
+	t.creditSignals(result)
+
+	t.mu.RLock()
+	listeners := t.listeners
+	t.mu.RUnlock()
+	for _, listener := range listeners {
+		listener(result)
+	}
 }
 
 // UpdateStats calculates and updates statistics
@@ -170,6 +210,87 @@ func (t *ResultTracker) UpdateStats(market string) {
 	t.storage.UpdateStats(market, stats)
 }
 
+// creditSignals credits each signal that voted in the direction price moved
+// and debits the rest
+func (t *ResultTracker) creditSignals(result types.TradeResult) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, signal := range result.Signals {
+		if signal.Direction != "UP" && signal.Direction != "DOWN" {
+			continue
+		}
+
+		key := fmt.Sprintf("%s|%s|%d", signal.Name, result.MarketType, result.Duration)
+		stats := t.signalStats[key]
+		if stats == nil {
+			stats = &types.SignalStats{
+				Name:       signal.Name,
+				MarketType: result.MarketType,
+				Duration:   result.Duration,
+			}
+			t.signalStats[key] = stats
+		}
+
+		stats.Trades++
+		if (signal.Direction == "UP" && result.PriceChange > 0) ||
+			(signal.Direction == "DOWN" && result.PriceChange < 0) {
+			stats.Wins++
+		} else {
+			stats.Losses++
+		}
+
+		stats.WinRate = float64(stats.Wins) / float64(stats.Trades) * 100
+		lower, upper := WilsonInterval(stats.Wins, stats.Trades)
+		stats.CILower, stats.CIUpper = lower*100, upper*100
+		stats.LastUpdate = result.ExitTime
+	}
+}
+
+// GetSignalStats returns per-signal records sorted by market type, duration
+// and name. Empty marketType or zero duration match everything.
+func (t *ResultTracker) GetSignalStats(marketType string, duration int) []types.SignalStats {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	all := make([]types.SignalStats, 0, len(t.signalStats))
+	for _, stats := range t.signalStats {
+		if marketType != "" && stats.MarketType != marketType {
+			continue
+		}
+		if duration != 0 && stats.Duration != duration {
+			continue
+		}
+		all = append(all, *stats)
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].MarketType != all[j].MarketType {
+			return all[i].MarketType < all[j].MarketType
+		}
+		if all[i].Duration != all[j].Duration {
+			return all[i].Duration < all[j].Duration
+		}
+		return all[i].Name < all[j].Name
+	})
+	return all
+}
+
+// WilsonInterval is the 95% Wilson score interval for a win rate (0-1)
+func WilsonInterval(wins, trades int) (float64, float64) {
+	if trades == 0 {
+		return 0, 1
+	}
+
+	const z = 1.96
+	n := float64(trades)
+	p := float64(wins) / n
+	denominator := 1 + z*z/n
+	center := (p + z*z/(2*n)) / denominator
+	margin := z * math.Sqrt(p*(1-p)/n+z*z/(4*n*n)) / denominator
+	return math.Max(0, center-margin), math.Min(1, center+margin)
+}
+
 // CalculateAllStats updates stats for all markets
 func (t *ResultTracker) CalculateAllStats() {
 	allResults := t.storage.GetAllResults()
@@ -34,6 +34,9 @@ type Indicators struct {
 	Volatility    float64 `json:"volatility"`
 	Momentum      float64 `json:"momentum"`
 	TrendStrength float64 `json:"trend_strength"`
+
+	// Extra holds registry indicators requested by config, keyed "key.output"
+	Extra map[string]float64 `json:"extra,omitempty"`
 }
 
 // Prediction represents a trading prediction
@@ -42,43 +45,187 @@ type Prediction struct {
 	Market       string     `json:"market"`
 	MarketType   string     `json:"market_type"` // "forex", "volatility", "crash_boom"
 	Direction    string     `json:"direction"`   // "UP", "DOWN", "NONE"
-	Confidence   float64    `json:"confidence"`
+	Confidence   float64    `json:"confidence"`  // Calibrated when a calibration is fitted, otherwise raw
 	Reason       string     `json:"reason"`
+	ReasonCode   string     `json:"reason_code,omitempty"` // Machine-readable NONE reason, e.g. "news_blackout"
 	CurrentPrice float64    `json:"current_price"`
 	Duration     int        `json:"duration"` // seconds
 	Timestamp    time.Time  `json:"timestamp"`
 	Indicators   Indicators `json:"indicators"`
 	DataPoints   int        `json:"data_points"`
+
+	// Blackout is the release that blocked the prediction (news_blackout)
+	Blackout *NewsEvent `json:"blackout,omitempty"`
+
+	// Session is the forex session calendar status (forex only)
+	Session *SessionStatus `json:"session,omitempty"`
+
+	// Payout is the profit per unit stake on a win for this contract
+	Payout float64 `json:"payout"`
+
+	// ExpectedValue is the expected profit per unit stake at the calibrated
+	// confidence; BreakEven is the confidence where it is zero
+	ExpectedValue float64 `json:"expected_value"`
+	BreakEven     float64 `json:"break_even"`
+
+	// RawConfidence is the confidence before calibration
+	RawConfidence float64 `json:"raw_confidence"`
+
+	// Calibration describes the mapping applied to RawConfidence
+	Calibration *CalibrationInfo `json:"calibration,omitempty"`
+
+	// Baseline is the random-walk win probability of the same call
+	// (Volatility indices only)
+	Baseline *RandomWalkBaseline `json:"baseline,omitempty"`
+
+	// AutoDuration explains the duration chosen by /predict/:market/auto
+	AutoDuration string `json:"auto_duration,omitempty"`
+
+	// Volatility is the GARCH (or EWMA) forecast of the return deviation
+	// over the contract and the expected size of the move
+	Volatility *VolatilityForecast `json:"volatility_forecast,omitempty"`
+
+	// Timeframes is the trend on the primary and higher timeframes and its
+	// effect on the entry (multi-timeframe confirmation)
+	Timeframes []TimeframeTrend `json:"timeframes,omitempty"`
+
+	// Regime is the market regime the prediction was made in
+	Regime *MarketRegime `json:"regime,omitempty"`
+
+	// Parameters are the effective settings that produced the prediction
+	Parameters *PredictionParameters `json:"parameters,omitempty"`
+
+	// Signals are the strategy signals that voted, with final weights
+	Signals []StrategySignal `json:"signals,omitempty"`
+
+	// Consensus holds every consensus method's verdict on the same signals;
+	// the one marked Selected decided the prediction
+	Consensus []ConsensusVerdict `json:"consensus,omitempty"`
+}
+
+// CalibrationInfo describes how a prediction's confidence was calibrated
+type CalibrationInfo struct {
+	Method     string  `json:"method"` // "isotonic", "platt", or "none" until enough results
+	Samples    int     `json:"samples"`
+	Calibrated float64 `json:"calibrated"` // Realized win probability for the raw confidence
+}
+
+// TimeframeTrend is the trend of one candle period
+type TimeframeTrend struct {
+	Period   int     `json:"period"` // Candle seconds
+	Primary  bool    `json:"primary"`
+	Candles  int     `json:"candles"`
+	Trend    string  `json:"trend"`    // "UP", "DOWN", "FLAT", or "NONE" without enough candles
+	Strength float64 `json:"strength"` // EMA fast/slow spread in ATRs
+	EMAFast  float64 `json:"ema_fast"`
+	EMASlow  float64 `json:"ema_slow"`
+	Effect   string  `json:"effect,omitempty"` // "boost" or "veto" on the entry
+}
+
+// RandomWalkBaseline is what a driftless random walk gives a rise/fall call;
+// Edge is how far the prediction's confidence claims to beat it
+type RandomWalkBaseline struct {
+	Sigma        float64 `json:"sigma"`         // Annualized sigma priced with
+	NominalSigma float64 `json:"nominal_sigma"` // From the index name
+	Probability  float64 `json:"probability"`   // Theoretical win probability
+	Edge         float64 `json:"edge"`          // Confidence - Probability
+}
+
+// VolatilityForecast is a volatility model's view of one contract duration
+type VolatilityForecast struct {
+	Method       string  `json:"method"` // "garch" or "ewma"
+	Duration     int     `json:"duration"`
+	Bars         float64 `json:"bars"`          // Model bars the duration spans
+	Sigma        float64 `json:"sigma"`         // Deviation of the log return over the duration
+	Ratio        float64 `json:"ratio"`         // Sigma over the long-run sigma for the same duration
+	ExpectedMove float64 `json:"expected_move"` // Mean absolute log return, sigma·√(2/π)
+}
+
+// ConsensusVerdict is one consensus method's combination of the signals
+type ConsensusVerdict struct {
+	Method        string               `json:"method"`
+	Selected      bool                 `json:"selected"`
+	Direction     string               `json:"direction"` // "UP", "DOWN", "NONE"
+	Confidence    float64              `json:"confidence"`
+	Reason        string               `json:"reason"`
+	Contributions []SignalContribution `json:"contributions"`
+}
+
+// SignalContribution is how much one signal pushed a consensus verdict.
+// Positive values favour UP, negative DOWN; units depend on the method.
+type SignalContribution struct {
+	Name         string  `json:"name"`
+	Direction    string  `json:"direction"`
+	Confidence   float64 `json:"confidence"`
+	Weight       float64 `json:"weight"`
+	Contribution float64 `json:"contribution"`
+	Vetoed       bool    `json:"vetoed,omitempty"` // Blocked a unanimity verdict
+}
+
+// PredictionParameters are the effective settings behind a prediction
+type PredictionParameters struct {
+	Strategies []string       `json:"strategies"` // Strategies run for the market type
+	Weights    []SignalWeight `json:"weights"`    // Final weight of each signal that voted, in signal order
+	ConsensusConfig
+}
+
+// SignalWeight is the final weight a signal voted with. A strategy can emit
+// several signals under one name, so weights are listed rather than keyed.
+type SignalWeight struct {
+	Name   string  `json:"name"`
+	Weight float64 `json:"weight"`
+}
+
+// MarketRegime classifies current market conditions
+type MarketRegime struct {
+	Regime               string   `json:"regime"` // "trending_up", "trending_down", "ranging", "high_volatility", "quiet"
+	ADX                  float64  `json:"adx"`
+	PlusDI               float64  `json:"plus_di"`
+	MinusDI              float64  `json:"minus_di"`
+	VolatilityPercentile float64  `json:"volatility_percentile"` // 0-1, current vs recent history
+	Hurst                float64  `json:"hurst"`
+	EfficiencyRatio      float64  `json:"efficiency_ratio"`
+	Disabled             []string `json:"disabled,omitempty"` // Signals dropped by regime rules
 }
 
 // PendingPrediction tracks a prediction waiting for outcome
 type PendingPrediction struct {
-	ID         string
-	Market     string
-	MarketType string
-	Direction  string
-	EntryPrice float64
-	EntryTime  time.Time
-	Duration   int
-	Confidence float64
-	ExpiryTime time.Time
+	ID            string
+	Market        string
+	MarketType    string
+	Direction     string
+	EntryPrice    float64
+	EntryTime     time.Time
+	Duration      int
+	Confidence    float64
+	RawConfidence float64
+	Payout        float64
+	Baseline      float64 // Random-walk win probability, 0 if not priced
+	ExpiryTime    time.Time
+	Signals       []StrategySignal
 }
 
 // TradeResult stores the outcome of a prediction
 type TradeResult struct {
-	PredictionID string    `json:"prediction_id"`
-	Market       string    `json:"market"`
-	MarketType   string    `json:"market_type"`
-	Direction    string    `json:"direction"`
-	EntryPrice   float64   `json:"entry_price"`
-	ExitPrice    float64   `json:"exit_price"`
-	EntryTime    time.Time `json:"entry_time"`
-	ExitTime     time.Time `json:"exit_time"`
-	Duration     int       `json:"duration"`
-	Confidence   float64   `json:"confidence"`
-	Won          bool      `json:"won"`
-	ProfitLoss   float64   `json:"profit_loss"`
-	PriceChange  float64   `json:"price_change"`
+	PredictionID  string    `json:"prediction_id"`
+	Market        string    `json:"market"`
+	MarketType    string    `json:"market_type"`
+	Direction     string    `json:"direction"`
+	EntryPrice    float64   `json:"entry_price"`
+	ExitPrice     float64   `json:"exit_price"`
+	EntryTime     time.Time `json:"entry_time"`
+	ExitTime      time.Time `json:"exit_time"`
+	Duration      int       `json:"duration"`
+	Confidence    float64   `json:"confidence"`
+	RawConfidence float64   `json:"raw_confidence"`
+	Won           bool      `json:"won"`
+	ProfitLoss    float64   `json:"profit_loss"`
+	PriceChange   float64   `json:"price_change"`
+
+	// BaselineProbability is the random-walk win probability of the call
+	BaselineProbability float64 `json:"baseline_probability,omitempty"`
+
+	Signals []StrategySignal `json:"signals,omitempty"` // Signals that voted on the prediction
 }
 
 // Stats represents performance statistics
@@ -97,6 +244,21 @@ type Stats struct {
 	RecentTrades    []TradeResult `json:"recent_trades,omitempty"`
 }
 
+// SignalStats is one signal's record for a market type and duration.
+// A signal wins when price moved the way it voted, whatever the prediction.
+type SignalStats struct {
+	Name       string    `json:"name"`
+	MarketType string    `json:"market_type"`
+	Duration   int       `json:"duration"`
+	Trades     int       `json:"trades"`
+	Wins       int       `json:"wins"`
+	Losses     int       `json:"losses"`
+	WinRate    float64   `json:"win_rate"` // Percent
+	CILower    float64   `json:"ci_lower"` // 95% Wilson interval, percent
+	CIUpper    float64   `json:"ci_upper"` // 95% Wilson interval, percent
+	LastUpdate time.Time `json:"last_updated"`
+}
+
 // MarketData holds all data for a single market
 type MarketData struct {
 	Market     string
@@ -108,26 +270,45 @@ type MarketData struct {
 
 // StrategySignal represents a signal from a single strategy
 type StrategySignal struct {
-	Name       string
-	Direction  string // "UP", "DOWN", "NONE"
-	Confidence float64
-	Reason     string
-	Weight     float64
+	Name       string  `json:"name"`
+	Direction  string  `json:"direction"` // "UP", "DOWN", "NONE"
+	Confidence float64 `json:"confidence"`
+	Reason     string  `json:"reason"`
+	Weight     float64 `json:"weight"`
+	WinRate    float64 `json:"win_rate,omitempty"` // Confidence shrunk towards the signal's tracked win rate
 }
 
+// MinDuration and MaxDuration bound the contract durations, in seconds, that
+// every endpoint predicts or reports at and the recommender ranks
+const (
+	MinDuration = 30
+	MaxDuration = 3600
+)
+
 // Config represents application configuration
 type Config struct {
-	Mode             string           `yaml:"mode"`    // "synthetics", "forex", "both"
-	Markets          []string         `yaml:"markets"` // Deprecated - use specific lists
-	SyntheticMarkets []string         `yaml:"synthetic_markets"`
-	ForexMarkets     []string         `yaml:"forex_markets"`
-	DataSource       DataSourceConfig `yaml:"datasource"`
-	Strategy         StrategyConfig   `yaml:"strategy"`
-	Risk             RiskConfig       `yaml:"risk"`
-	Storage          StorageConfig    `yaml:"storage"`
-	API              APIConfig        `yaml:"api"`
-	Logging          LoggingConfig    `yaml:"logging"`
-	Tracking         TrackingConfig   `yaml:"tracking"`
+	Mode             string            `yaml:"mode"`    // "synthetics", "forex", "both"
+	Markets          []string          `yaml:"markets"` // Deprecated - use specific lists
+	SyntheticMarkets []string          `yaml:"synthetic_markets"`
+	ForexMarkets     []string          `yaml:"forex_markets"`
+	DataSource       DataSourceConfig  `yaml:"datasource"`
+	Strategy         StrategyConfig    `yaml:"strategy"`
+	Risk             RiskConfig        `yaml:"risk"`
+	Storage          StorageConfig     `yaml:"storage"`
+	API              APIConfig         `yaml:"api"`
+	Logging          LoggingConfig     `yaml:"logging"`
+	Tracking         TrackingConfig    `yaml:"tracking"`
+	Levels           LevelsConfig      `yaml:"levels"`
+	Calibration      CalibrationConfig `yaml:"calibration"`
+	Payout           PayoutConfig      `yaml:"payout"`
+	Sessions         SessionsConfig    `yaml:"sessions"`
+	News             NewsConfig        `yaml:"news"`
+	Hazard           HazardConfig      `yaml:"hazard"`
+	Strength         StrengthConfig    `yaml:"strength"`
+	Correlation      CorrelationConfig `yaml:"correlation"`
+	GARCH            GARCHConfig       `yaml:"garch"`
+	Markov           MarkovConfig      `yaml:"markov"`
+	Recommend        RecommendConfig   `yaml:"recommend"`
 }
 
 type DataSourceConfig struct {
@@ -149,26 +330,153 @@ type StrategyConfig struct {
 	Volatility    VolatilityWeights `yaml:"volatility"`
 	CrashBoom     CrashBoomWeights  `yaml:"crash_boom"`
 	Forex         ForexWeights      `yaml:"forex"`
+
+	// Enabled lists the registered strategies run per market type
+	// ("forex", "volatility", "crash_boom"); missing types use the defaults
+	Enabled map[string][]string `yaml:"enabled"`
+
+	// ExtraIndicators are registry indicators computed for every prediction
+	ExtraIndicators []IndicatorSpec `yaml:"extra_indicators"`
+
+	// Regime classifies market conditions and gates strategies per regime
+	Regime RegimeConfig `yaml:"regime"`
+
+	// Consensus thresholds per market type
+	Consensus map[string]ConsensusConfig `yaml:"consensus"`
+
+	// ConsensusFile keeps consensus method scores and the stacked model
+	ConsensusFile string `yaml:"consensus_file"`
+
+	// Adaptive scales signal weights by their tracked win rates
+	Adaptive AdaptiveConfig `yaml:"adaptive"`
+
+	// MultiTimeframe confirms entries with higher-timeframe trends
+	MultiTimeframe MultiTimeframeConfig `yaml:"multi_timeframe"`
+
+	// Kalman tunes the local-linear-trend filter behind kalman_trend
+	Kalman KalmanConfig `yaml:"kalman"`
+}
+
+// SignalWeights returns the shared signal weights for a market type
+func (c StrategyConfig) SignalWeights(marketType string) SignalWeights {
+	switch marketType {
+	case "volatility":
+		return c.Volatility.SignalWeights
+	case "crash_boom":
+		return c.CrashBoom.SignalWeights
+	case "forex":
+		return c.Forex.SignalWeights
+	}
+	return SignalWeights{}
+}
+
+// RegimeConfig controls the market regime detector
+type RegimeConfig struct {
+	ADXPeriod          int     `yaml:"adx_period"`
+	TrendADX           float64 `yaml:"trend_adx"` // ADX at or above this can be a trend
+	EfficiencyPeriod   int     `yaml:"efficiency_period"`
+	TrendEfficiency    float64 `yaml:"trend_efficiency"` // Efficiency ratio confirming a trend
+	HurstWindow        int     `yaml:"hurst_window"`
+	TrendHurst         float64 `yaml:"trend_hurst"` // Hurst exponent confirming a trend
+	VolatilityWindow   int     `yaml:"volatility_window"`
+	HighVolPercentile  float64 `yaml:"high_vol_percentile"`  // At or above: high_volatility
+	QuietVolPercentile float64 `yaml:"quiet_vol_percentile"` // At or below (without trend): quiet
+
+	// Rules enable, disable or reweight strategy signals per regime
+	Rules map[string]RegimeRule `yaml:"rules"`
+}
+
+// RegimeRule adjusts strategy signals in one regime. Names match
+// StrategySignal.Name; a trailing "*" matches a prefix (e.g. "Candle_*").
+type RegimeRule struct {
+	Enable  []string           `yaml:"enable"`  // When set, only these signals are kept
+	Disable []string           `yaml:"disable"` // Signals dropped in this regime
+	Weights map[string]float64 `yaml:"weights"` // Weight multipliers
+}
+
+// IndicatorSpec requests a registered indicator by key with optional params
+type IndicatorSpec struct {
+	Key    string             `yaml:"key" json:"key"`
+	Params map[string]float64 `yaml:"params" json:"params,omitempty"`
+}
+
+// SignalWeights weight the signals every market type can run
+// (divergence, chart patterns, candlesticks, tracked levels)
+type SignalWeights struct {
+	DivergenceWeight  float64 `yaml:"divergence_weight"`
+	PatternWeight     float64 `yaml:"pattern_weight"`
+	CandlestickWeight float64 `yaml:"candlestick_weight"`
+	LevelWeight       float64 `yaml:"level_weight"`
+	LeadLagWeight     float64 `yaml:"lead_lag_weight"`
+	KalmanWeight      float64 `yaml:"kalman_weight"`
+	MarkovWeight      float64 `yaml:"markov_weight"`
 }
 
 type VolatilityWeights struct {
 	MeanReversionWeight float64 `yaml:"mean_reversion_weight"`
 	MomentumWeight      float64 `yaml:"momentum_weight"`
-	PatternWeight       float64 `yaml:"pattern_weight"`
+	BollingerWeight     float64 `yaml:"bollinger_weight"`
+	RSIWeight           float64 `yaml:"rsi_weight"`
+	EMACrossoverWeight  float64 `yaml:"ema_crossover_weight"`
+	SignalWeights       `yaml:",inline"`
 }
 
 type CrashBoomWeights struct {
-	SpikeDetectionWeight float64 `yaml:"spike_detection_weight"`
-	TrendWeight          float64 `yaml:"trend_weight"`
-	VolatilityWeight     float64 `yaml:"volatility_weight"`
+	TrendWeight      float64 `yaml:"trend_weight"`      // Between-spike trend
+	VolatilityWeight float64 `yaml:"volatility_weight"` // Pre-spike volatility
+	RecoveryWeight   float64 `yaml:"recovery_weight"`   // Post-spike recovery
+	HazardWeight     float64 `yaml:"hazard_weight"`     // Spike hazard model
+	SignalWeights    `yaml:",inline"`
 }
 
 type ForexWeights struct {
 	TrendFollowingWeight    float64 `yaml:"trend_following_weight"`
 	SupportResistanceWeight float64 `yaml:"support_resistance_weight"`
 	EMACrossoverWeight      float64 `yaml:"ema_crossover_weight"`
-	PullbackWeight          float64 `yaml:"pullback_weight"`
-	RangeWeight             float64 `yaml:"range_weight"`
+	PullbackWeight          float64 `yaml:"pullback_weight"` // Momentum continuation
+	StrengthWeight          float64 `yaml:"strength_weight"` // Currency strength differential
+	SignalWeights           `yaml:",inline"`
+}
+
+// MultiTimeframeConfig controls higher-timeframe confirmation. Up to two
+// configured candle periods longer than the primary one are checked; a trend
+// against the entry at VetoStrength or more blocks it, each agreeing trend
+// boosts confidence.
+type MultiTimeframeConfig struct {
+	Enabled      bool             `yaml:"enabled"`
+	Higher       map[string][]int `yaml:"higher"`        // Candle periods in seconds per market type
+	Boost        float64          `yaml:"boost"`         // Confidence multiplier step per agreeing timeframe
+	VetoStrength float64          `yaml:"veto_strength"` // EMA spread in ATRs for an opposing trend to veto
+}
+
+// KalmanConfig controls the local-linear-trend Kalman filter. Noise
+// variances are relative to the observation noise, so higher LevelNoise
+// tracks price more closely and higher SlopeNoise lets the slope turn faster.
+type KalmanConfig struct {
+	LevelNoise float64 `yaml:"level_noise"`
+	SlopeNoise float64 `yaml:"slope_noise"`
+	MinZ       float64 `yaml:"min_z"` // Projected move over the duration, in deviations, for a signal
+}
+
+// AdaptiveConfig controls online signal weighting from tracked outcomes
+type AdaptiveConfig struct {
+	HalfLifeTrades int     `yaml:"half_life_trades"` // Results after which old evidence counts half
+	PriorStrength  float64 `yaml:"prior_strength"`   // Pseudo-results at a 50% win rate
+	MinSamples     int     `yaml:"min_samples"`      // Results before a multiplier moves from 1
+	Floor          float64 `yaml:"floor"`            // Lowest weight multiplier
+	Ceiling        float64 `yaml:"ceiling"`          // Highest weight multiplier
+	File           string  `yaml:"file"`             // Learned weights are saved here
+}
+
+// ConsensusConfig sets how signals are combined for one market type
+type ConsensusConfig struct {
+	// Method is "majority", "weighted_majority", "unanimity", "log_odds" or "stacked"
+	Method            string   `yaml:"method" json:"method"`
+	MinConfidence     float64  `yaml:"min_confidence" json:"min_confidence"`         // Defaults to strategy.min_confidence
+	RequiredAgreement float64  `yaml:"required_agreement" json:"required_agreement"` // Share of votes (or weight) for the winning side
+	Veto              []string `yaml:"veto" json:"veto,omitempty"`                   // Unanimity: signals that block when they disagree (empty = all)
+	StackedMinSamples int      `yaml:"stacked_min_samples" json:"stacked_min_samples"`
+	StackedLearnRate  float64  `yaml:"stacked_learn_rate" json:"stacked_learn_rate"`
 }
 
 type RiskConfig struct {
@@ -198,6 +506,7 @@ type ForexRisk struct {
 
 type StorageConfig struct {
 	MaxTicksInMemory     int `yaml:"max_ticks_in_memory"`
+	MaxArchiveTicks      int `yaml:"max_archive_ticks"`
 	KeepPredictionsHours int `yaml:"keep_predictions_hours"`
 	AutoCleanupInterval  int `yaml:"auto_cleanup_interval"`
 }
@@ -217,8 +526,136 @@ type LoggingConfig struct {
 }
 
 type TrackingConfig struct {
-	CalculateStatsInterval int  `yaml:"calculate_stats_interval"`
-	MinTradesForStats      int  `yaml:"min_trades_for_stats"`
-	DisplayRecentTrades    int  `yaml:"display_recent_trades"`
-	SeparateStatsByType    bool `yaml:"separate_stats_by_type"`
+	CalculateStatsInterval   int  `yaml:"calculate_stats_interval"`
+	AnalyticsRefreshInterval int  `yaml:"analytics_refresh_interval"`
+	MinTradesForStats        int  `yaml:"min_trades_for_stats"`
+	DisplayRecentTrades      int  `yaml:"display_recent_trades"`
+	SeparateStatsByType      bool `yaml:"separate_stats_by_type"`
+}
+
+// SessionsConfig is the forex session calendar
+type SessionsConfig struct {
+	Timezone     string          `yaml:"timezone"`      // Forex week boundary zone (New York)
+	Rollover     string          `yaml:"rollover"`      // Local time the week opens Sunday and closes Friday
+	HolidaysFile string          `yaml:"holidays_file"` // CSV: date,currency,name (currency ALL closes the market)
+	Sessions     []SessionConfig `yaml:"sessions"`
+}
+
+// SessionConfig is one trading session in its local, DST-aware timezone
+type SessionConfig struct {
+	Name       string   `yaml:"name"`
+	Timezone   string   `yaml:"timezone"` // IANA name, e.g. Europe/London
+	Open       string   `yaml:"open"`     // Local "HH:MM"
+	Close      string   `yaml:"close"`    // Local "HH:MM"
+	Currencies []string `yaml:"currencies"`
+	Asian      bool     `yaml:"asian"` // Counts toward avoid_asian_session
+}
+
+// SessionStatus is the forex calendar's view of a pair at a moment
+type SessionStatus struct {
+	Open       bool      `json:"open"` // Market open and not avoided
+	Reason     string    `json:"reason,omitempty"`
+	Sessions   []string  `json:"sessions"` // Sessions currently trading
+	Overlap    bool      `json:"london_ny_overlap"`
+	Asian      bool      `json:"asian_only"` // Only Asian sessions trading
+	Holidays   []string  `json:"holidays,omitempty"`
+	Multiplier float64   `json:"multiplier"` // Confidence multiplier for the session
+	Time       time.Time `json:"time"`
+}
+
+// NewsConfig controls forex blackouts around economic releases
+type NewsConfig struct {
+	File    string                `yaml:"file"`    // CSV (time,currency,impact,title) or JSON
+	Windows map[string]NewsWindow `yaml:"windows"` // Per impact; impacts without a window are ignored
+}
+
+// NewsWindow is the blackout around one release, in minutes
+type NewsWindow struct {
+	Before int `yaml:"before"`
+	After  int `yaml:"after"`
+}
+
+// NewsEvent is one scheduled economic release
+type NewsEvent struct {
+	Time     time.Time `json:"time"`
+	Currency string    `json:"currency"`
+	Impact   string    `json:"impact"` // "high", "medium", "low"
+	Title    string    `json:"title"`
+}
+
+// PayoutConfig sets contract payouts used for expected value
+type PayoutConfig struct {
+	Default float64      `yaml:"default"` // Profit per unit stake on a win (0.85 = 85%)
+	MinEV   float64      `yaml:"min_ev"`  // Best markets skip predictions below this EV
+	Rules   []PayoutRule `yaml:"rules"`   // First match wins
+}
+
+// PayoutRule overrides the payout for matching contracts; empty or zero
+// fields match anything
+type PayoutRule struct {
+	Market      string  `yaml:"market"`
+	MarketType  string  `yaml:"market_type"`
+	MinDuration int     `yaml:"min_duration"`
+	MaxDuration int     `yaml:"max_duration"`
+	Payout      float64 `yaml:"payout"`
+}
+
+// CalibrationConfig controls mapping raw confidence to realized win rate
+type CalibrationConfig struct {
+	Method     string `yaml:"method"`      // "isotonic" or "platt"
+	MinSamples int    `yaml:"min_samples"` // Results per market type and duration before calibrating
+	Bins       int    `yaml:"bins"`        // Reliability diagram bins
+}
+
+// HazardConfig controls the Crash/Boom spike hazard model
+type HazardConfig struct {
+	SpikeSigma  float64 `yaml:"spike_sigma"`  // Robust deviations of a one-tick return that count as a spike
+	PriorSpikes float64 `yaml:"prior_spikes"` // Pseudo-spikes at the nominal rate from the index name
+	MinTicks    int     `yaml:"min_ticks"`    // Archive ticks before a market is modelled
+}
+
+// StrengthConfig controls the forex currency strength meter
+type StrengthConfig struct {
+	LookbackMinutes           int     `yaml:"lookback_minutes"`            // Window strength is measured over
+	TriangulationToleranceBps float64 `yaml:"triangulation_tolerance_bps"` // Cross vs implied rate gap flagged
+	MinDifferential           float64 `yaml:"min_differential"`            // Base minus quote strength (percent) for a signal
+}
+
+// CorrelationConfig controls the cross-market correlation analyzer
+type CorrelationConfig struct {
+	BarSeconds     int     `yaml:"bar_seconds"`     // Shared bar size markets are resampled to
+	WindowBars     int     `yaml:"window_bars"`     // Bars correlations are measured over
+	MaxLag         int     `yaml:"max_lag"`         // Longest lead tested, in bars
+	MinCorrelation float64 `yaml:"min_correlation"` // Lagged correlation for a lead-lag pair
+	MoveSigma      float64 `yaml:"move_sigma"`      // Leader move, in bar deviations, that triggers a signal
+}
+
+// GARCHConfig controls the per-market volatility forecaster
+type GARCHConfig struct {
+	BarSeconds int     `yaml:"bar_seconds"` // Archive ticks are resampled to bars of this size
+	MinBars    int     `yaml:"min_bars"`    // Bar returns before GARCH is fitted instead of EWMA
+	EWMALambda float64 `yaml:"ewma_lambda"` // Decay of the EWMA fallback
+}
+
+// MarkovConfig controls the tick-direction Markov model of synthetic indices
+type MarkovConfig struct {
+	MaxOrder     int     `yaml:"max_order"`    // Longest context of previous tick directions (1-3)
+	Significance float64 `yaml:"significance"` // Chi-square p-value, split across orders, to trust a chain
+	MinTicks     int     `yaml:"min_ticks"`    // Archive ticks before a market is modelled
+	MinEffect    float64 `yaml:"min_effect"`   // |P(up) - 0.5| over the duration before a forecast is acted on
+}
+
+// RecommendConfig controls the per-market duration recommender
+type RecommendConfig struct {
+	Durations []int `yaml:"durations"`  // Candidate durations in seconds
+	MinTrades int   `yaml:"min_trades"` // Tracked results before a duration is ranked
+}
+
+// LevelsConfig controls the support/resistance level service
+type LevelsConfig struct {
+	SwingWindow        int     `yaml:"swing_window"`         // Candles on each side of a swing point
+	ClusterTolerance   float64 `yaml:"cluster_tolerance"`    // Fraction of price merged into one level
+	PivotPeriodMinutes int     `yaml:"pivot_period_minutes"` // Session length for pivot points
+	MaxLevels          int     `yaml:"max_levels"`           // Swing clusters kept per market
+	RoundNumbers       bool    `yaml:"round_numbers"`
 }